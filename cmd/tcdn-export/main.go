@@ -0,0 +1,236 @@
+// Command tcdn-export writes the Terraform configuration of an existing Transparent Edge account,
+// with the import blocks that bring its objects under Terraform management.
+//
+// The settings are read like the provider does: from the TCDN_* environment variables,
+// then from the credentials profile. Those of the connection (TLS, proxy and request timeout) only from the environment.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/export"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/profiles"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+const defaultAPIURL = "https://api.transparentcdn.com"
+
+var (
+	// variables are set by goreleaser.
+	version = "dev"
+)
+
+// settings are the connection settings of the API client.
+type settings struct {
+	apiURL            string
+	companyID         int
+	clientID          string
+	clientSecret      string
+	accessToken       string
+	credentialProcess string
+	insecure          bool
+	// options holds the settings of the connection, the ones of the client are set by run.
+	options teclient.ClientOptions
+}
+
+func main() {
+	var (
+		dir       string
+		profile   string
+		companyID int
+		force     bool
+		showVer   bool
+	)
+
+	flag.StringVar(&dir, "dir", ".", "directory the configuration is written to")
+	flag.StringVar(&profile, "profile", os.Getenv("TCDN_PROFILE"), "credentials profile, default: 'default' if the credentials file has it")
+	flag.IntVar(&companyID, "company-id", 0, "company to export instead of the one of the credentials, i.e: a child company of a reseller")
+	flag.BoolVar(&force, "force", false, "overwrite the existing files")
+	flag.BoolVar(&showVer, "version", false, "print the version and exit")
+	flag.Parse()
+
+	if showVer {
+		fmt.Println("tcdn-export", version)
+
+		return
+	}
+
+	if err := run(dir, profile, companyID, force); err != nil {
+		fmt.Fprintln(os.Stderr, "tcdn-export:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, profile string, companyID int, force bool) error {
+	ctx := context.Background()
+
+	s, err := loadSettings(profile)
+	if err != nil {
+		return err
+	}
+
+	auth := true
+	providerVersion := version
+
+	opts := s.options
+	opts.MaxRetries = 3
+	opts.AccessToken = s.accessToken
+	opts.CredentialProcess = s.credentialProcess
+	opts.ReadOnly = true
+
+	client, err := teclient.NewClient(ctx, &s.apiURL, &s.companyID, &s.clientID, &s.clientSecret, &s.insecure, &auth, &providerVersion, opts)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", s.apiURL, err)
+	}
+
+	summary, err := export.Export(teclient.WithCompanyID(ctx, companyID), client, export.Options{Dir: dir, Overwrite: force})
+	if errors.Is(err, export.ErrExists) {
+		return fmt.Errorf("%w, use -force to overwrite it", err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, rtype := range slices.Sorted(maps.Keys(summary)) {
+		fmt.Printf("%-40s %d\n", rtype, summary[rtype])
+	}
+
+	fmt.Printf("\nConfiguration written to %s, run 'terraform init' and 'terraform plan' there to review the imports.\n", dir)
+
+	return nil
+}
+
+// loadSettings reads the settings from the environment, completed with the credentials profile.
+func loadSettings(profileName string) (*settings, error) {
+	s := &settings{
+		apiURL: os.Getenv("TCDN_API_URL"),
+	}
+
+	var err error
+
+	if value := os.Getenv("TCDN_COMPANY_ID"); value != "" {
+		if s.companyID, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid TCDN_COMPANY_ID: %w", err)
+		}
+	}
+
+	if value := os.Getenv("TCDN_INSECURE"); value != "" {
+		if s.insecure, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid TCDN_INSECURE: %w", err)
+		}
+	}
+
+	if err := loadConnectionSettings(&s.options); err != nil {
+		return nil, err
+	}
+
+	profile, err := profiles.Select(profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile != nil {
+		if value, found := profile.Get("api_url"); found && s.apiURL == "" {
+			s.apiURL = value
+		}
+
+		if value, found := profile.Get("company_id"); found && s.companyID == 0 {
+			if s.companyID, err = strconv.Atoi(value); err != nil {
+				return nil, fmt.Errorf("invalid company_id in the %s: %w", profile, err)
+			}
+		}
+	}
+
+	// The same precedence as the provider, the token of a profile doesn't replace the client credentials of the environment
+	credentials, _ := profiles.ResolveCredentials(
+		profiles.Credentials{
+			ClientID:          os.Getenv("TCDN_CLIENT_ID"),
+			ClientSecret:      os.Getenv("TCDN_CLIENT_SECRET"),
+			AccessToken:       os.Getenv("TCDN_ACCESS_TOKEN"),
+			CredentialProcess: os.Getenv("TCDN_CREDENTIAL_PROCESS"),
+		},
+		profile.Credentials(),
+	)
+
+	s.clientID = credentials.ClientID
+	s.clientSecret = credentials.ClientSecret
+	s.accessToken = credentials.AccessToken
+	s.credentialProcess = credentials.CredentialProcess
+
+	if s.apiURL == "" {
+		s.apiURL = defaultAPIURL
+	}
+
+	if s.companyID < 1 {
+		return nil, errors.New("missing company ID, set TCDN_COMPANY_ID or a credentials profile")
+	}
+
+	if s.accessToken != "" && s.credentialProcess != "" {
+		return nil, errors.New("conflicting credentials, set either an access_token or a credential_process, not both")
+	}
+
+	if !credentials.TokenAuth() && (s.clientID == "" || s.clientSecret == "") {
+		return nil, errors.New("missing credentials, set TCDN_CLIENT_ID and TCDN_CLIENT_SECRET, TCDN_ACCESS_TOKEN, TCDN_CREDENTIAL_PROCESS or a credentials profile")
+	}
+
+	return s, nil
+}
+
+// loadConnectionSettings reads the TLS, proxy and timeout settings of the provider from the environment into opts.
+func loadConnectionSettings(opts *teclient.ClientOptions) error {
+	if value := os.Getenv("TCDN_REQUEST_TIMEOUT"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 1 {
+			return fmt.Errorf("invalid TCDN_REQUEST_TIMEOUT '%s', expected a number of seconds", value)
+		}
+
+		opts.RequestTimeout = time.Duration(seconds) * time.Second
+	}
+
+	opts.ProxyURL = os.Getenv("TCDN_PROXY_URL")
+	opts.NoProxy = os.Getenv("TCDN_NO_PROXY")
+
+	opts.UserAgentSuffix = "tcdn-export/" + version
+	if suffix := os.Getenv("TCDN_USER_AGENT_SUFFIX"); suffix != "" {
+		opts.UserAgentSuffix += " " + suffix
+	}
+
+	for envVar, data := range map[string]*[]byte{
+		"TCDN_CA_BUNDLE":          &opts.CABundle,
+		"TCDN_CLIENT_CERTIFICATE": &opts.ClientCertificate,
+		"TCDN_CLIENT_KEY":         &opts.ClientKey,
+	} {
+		if value := os.Getenv(envVar); value != "" {
+			var err error
+			if *data, err = helpers.ReadPEMOrFile(value); err != nil {
+				return fmt.Errorf("invalid %s, it's neither PEM encoded data nor a readable file: %w", envVar, err)
+			}
+		}
+	}
+
+	if (opts.ClientCertificate == nil) != (opts.ClientKey == nil) {
+		return errors.New("incomplete client certificate, set both TCDN_CLIENT_CERTIFICATE and TCDN_CLIENT_KEY, or none of them")
+	}
+
+	if value := os.Getenv("TCDN_MIN_TLS_VERSION"); value != "" {
+		tlsVersion, found := teclient.TLSVersions[value]
+		if !found {
+			return fmt.Errorf("invalid TCDN_MIN_TLS_VERSION '%s', expected one of: %s", value, strings.Join(teclient.TLSVersionNames(), ", "))
+		}
+
+		opts.MinTLSVersion = tlsVersion
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+data "transparentedge_staging_vclconf_history" "last" {
+  limit = 10
+}
+
+output "staging_vcl_history" {
+  value = data.transparentedge_staging_vclconf_history.last.vclconfs
+}
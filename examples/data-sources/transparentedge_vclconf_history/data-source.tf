@@ -0,0 +1,16 @@
+# Hotfixes uploaded by a user since 2024, with their code
+data "transparentedge_vclconf_history" "audit" {
+  since         = "2024-01-01"
+  uploaded_by   = "user@example.com"
+  comment_regex = "(?i)hotfix"
+  include_code  = true
+}
+
+output "hotfixes" {
+  value = [for conf in data.transparentedge_vclconf_history.audit.vclconfs : "${conf.uploaddate}: ${conf.comment}"]
+}
+
+# The last 10 configurations
+data "transparentedge_vclconf_history" "last" {
+  limit = 10
+}
@@ -0,0 +1,16 @@
+# Find the backends to generate their configuration with:
+#   terraform query -generate-config-out=backends.tf
+list "transparentedge_backend" "all" {
+  provider         = transparentedge
+  include_resource = true
+}
+
+# Only the backends of another company with an origin in example.com
+list "transparentedge_backend" "other_company" {
+  provider = transparentedge
+
+  config {
+    company_id   = 301
+    origin_regex = "\\.example\\.com$"
+  }
+}
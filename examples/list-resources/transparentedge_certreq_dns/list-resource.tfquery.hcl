@@ -0,0 +1,9 @@
+# Find the DNS certificate requests using a credential
+list "transparentedge_certreq_dns" "route53" {
+  provider         = transparentedge
+  include_resource = true
+
+  config {
+    credential = 321
+  }
+}
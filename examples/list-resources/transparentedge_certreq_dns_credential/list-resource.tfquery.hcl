@@ -0,0 +1,9 @@
+# Find the DNS credentials of a DNS provider
+list "transparentedge_certreq_dns_credential" "route53" {
+  provider         = transparentedge
+  include_resource = true
+
+  config {
+    dns_provider = "AWS (Route53)"
+  }
+}
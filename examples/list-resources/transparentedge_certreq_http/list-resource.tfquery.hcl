@@ -0,0 +1,9 @@
+# Find the HTTP certificate requests for a domain
+list "transparentedge_certreq_http" "example_com" {
+  provider         = transparentedge
+  include_resource = true
+
+  config {
+    domain_regex = "(^|\\.)example\\.com$"
+  }
+}
@@ -0,0 +1,14 @@
+# Find the custom certificates, autogenerated ones are left out
+list "transparentedge_custom_certificate" "all" {
+  provider = transparentedge
+}
+
+# Only the certificates for a domain
+list "transparentedge_custom_certificate" "example_com" {
+  provider         = transparentedge
+  include_resource = true
+
+  config {
+    domain_regex = "(^|\\.)example\\.com$"
+  }
+}
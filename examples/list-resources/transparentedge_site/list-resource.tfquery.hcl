@@ -0,0 +1,15 @@
+# Find the active sites to generate their configuration with:
+#   terraform query -generate-config-out=sites.tf
+list "transparentedge_site" "all" {
+  provider         = transparentedge
+  include_resource = true
+}
+
+# Only the sites of a domain
+list "transparentedge_site" "example_com" {
+  provider = transparentedge
+
+  config {
+    domain_regex = "(^|\\.)example\\.com$"
+  }
+}
@@ -0,0 +1,15 @@
+# Find the staging backends to generate their configuration with:
+#   terraform query -generate-config-out=staging_backends.tf
+list "transparentedge_staging_backend" "all" {
+  provider         = transparentedge
+  include_resource = true
+}
+
+# Only the backends with a name starting with 'stag'
+list "transparentedge_staging_backend" "stag" {
+  provider = transparentedge
+
+  config {
+    name_regex = "^stag"
+  }
+}
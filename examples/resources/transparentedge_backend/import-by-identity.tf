@@ -0,0 +1,8 @@
+import {
+  to = transparentedge_backend.origin1
+  identity = {
+    name = "origin1"
+    # Optional, defaults to the provider company
+    company_id = 301
+  }
+}
@@ -1,2 +1,5 @@
 # Import a backend by name
 terraform import 'transparentedge_backend.origin1' 'origin1'
+
+# Prefix the name with the company ID to import a backend of another company
+terraform import 'transparentedge_backend.origin1' '301/origin1'
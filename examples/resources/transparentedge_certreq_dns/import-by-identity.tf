@@ -0,0 +1,8 @@
+import {
+  to = transparentedge_certreq_dns.dnscertreq
+  identity = {
+    id = 147
+    # Optional, defaults to the provider company
+    company_id = 301
+  }
+}
@@ -1,3 +1,5 @@
 # Import DNS Certificate Request by ID
 terraform import 'transparentedge_certreq_dns.dnscertreq' 147
 
+# Prefix the ID with the company ID to import a request of another company
+terraform import 'transparentedge_certreq_dns.dnscertreq' 301/147
@@ -0,0 +1,8 @@
+import {
+  to = transparentedge_certreq_dns_credential.mycred
+  identity = {
+    id = 321
+    # Optional, defaults to the provider company
+    company_id = 301
+  }
+}
@@ -1,3 +1,5 @@
 # Import credentials by ID
 terraform import 'transparentedge_certreq_dns_credential.mycred' 321
 
+# Prefix the ID with the company ID to import credentials of another company
+terraform import 'transparentedge_certreq_dns_credential.mycred' 301/321
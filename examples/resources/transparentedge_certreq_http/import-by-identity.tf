@@ -0,0 +1,8 @@
+import {
+  to = transparentedge_certreq_http.http_certreq
+  identity = {
+    id = 1058
+    # Optional, defaults to the provider company
+    company_id = 301
+  }
+}
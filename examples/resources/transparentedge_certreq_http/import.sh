@@ -1,3 +1,5 @@
 # Import HTTP Certificate Request by ID
 terraform import 'transparentedge_certreq_http.http_certreq' 1058
 
+# Prefix the ID with the company ID to import a request of another company
+terraform import 'transparentedge_certreq_http.http_certreq' 301/1058
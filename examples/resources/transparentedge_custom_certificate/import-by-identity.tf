@@ -0,0 +1,8 @@
+import {
+  to = transparentedge_custom_certificate.mysite
+  identity = {
+    id = 321
+    # Optional, defaults to the provider company
+    company_id = 301
+  }
+}
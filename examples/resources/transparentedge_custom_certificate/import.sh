@@ -1,2 +1,5 @@
 # Import a custom certificate by ID
 terraform import 'transparentedge_custom_certificate.mysite' 321
+
+# Prefix the ID with the company ID to import a certificate of another company
+terraform import 'transparentedge_custom_certificate.mysite' 301/321
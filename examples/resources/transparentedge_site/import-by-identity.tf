@@ -0,0 +1,8 @@
+import {
+  to = transparentedge_site.www_example3_com
+  identity = {
+    domain = "www.example3.com"
+    # Optional, defaults to the provider company
+    company_id = 301
+  }
+}
@@ -3,3 +3,6 @@ terraform import 'transparentedge_site.www_example3_com' 'www.example3.com'
 
 # If the sites are in a list/set, import a single site from the list with:
 terraform import 'transparentedge_site.all["www.example2.com"]' 'www.example2.com'
+
+# Prefix the domain with the company ID to import a site of another company
+terraform import 'transparentedge_site.www_example3_com' '301/www.example3.com'
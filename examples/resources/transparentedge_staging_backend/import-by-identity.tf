@@ -0,0 +1,8 @@
+import {
+  to = transparentedge_staging_backend.stagorigin1
+  identity = {
+    name = "stagorigin1"
+    # Optional, defaults to the provider company
+    company_id = 301
+  }
+}
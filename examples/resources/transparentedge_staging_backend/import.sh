@@ -1,2 +1,5 @@
 # Import a backend by name
 terraform import 'transparentedge_staging_backend.stagorigin1' 'stagorigin1'
+
+# Prefix the name with the company ID to import a backend of another company
+terraform import 'transparentedge_staging_backend.stagorigin1' '301/stagorigin1'
@@ -0,0 +1,7 @@
+import {
+  to = transparentedge_staging_vclconf.staging
+  identity = {
+    # Optional, defaults to the provider company
+    company_id = 301
+  }
+}
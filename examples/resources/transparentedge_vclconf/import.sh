@@ -1,6 +1,9 @@
 # Import the active VCL configuration, the ID value doesn't matter.
 terraform import 'transparentedge_vclconf.prod' 0
 
+# Use 'company_id/0' to import the configuration of another company
+terraform import 'transparentedge_vclconf.prod' 301/0
+
 # Importing VCL code has its quirks, since the API parses the code and
 # add/removes newlines and spaces the diff won't be equal, it's usually
 # better to just copy the last configuration from the dashboard and
@@ -72,3 +72,31 @@ EOF
 
 # Another option would be to use template files:
 # https://developer.hashicorp.com/terraform/language/functions/templatefile
+
+#################
+### EXAMPLE 4 ###
+#################
+# The code can also be assembled from snippets, so different teams can own the parts of the same subroutine.
+# Within a subroutine the snippets are sorted by priority, the ones without subroutine go outside of them.
+# The optional name identifies the snippet in the comments that delimit it in the assembled code.
+resource "transparentedge_vclconf" "snippets" {
+  snippets {
+    content = "import std;"
+  }
+
+  snippets {
+    name       = "routing"
+    subroutine = "vcl_recv"
+    priority   = 10
+    content    = <<EOF
+if (req.http.host == "www.example.com") {
+    set req.backend_hint = ${resource.transparentedge_backend.myorig.vclname}.backend();
+}
+EOF
+  }
+
+  snippets {
+    subroutine = "vcl_deliver"
+    content    = "unset resp.http.Via;"
+  }
+}
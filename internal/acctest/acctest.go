@@ -0,0 +1,98 @@
+// Package acctest holds the helpers shared by the acceptance tests.
+// The tests run against the in-memory API of the testserver package, so they only need
+// TF_ACC=1 and a terraform binary, no Transparent Edge account.
+package acctest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+
+	transparentedge "github.com/TransparentEdge/terraform-provider-transparentedge/internal/provider"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+// ProtoV6ProviderFactories runs the provider in-process for resource.Test.
+var ProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"transparentedge": providerserver.NewProtocol6WithError(transparentedge.New("acctest", "none")()),
+}
+
+// NewServer starts a fake API and points the provider to it through the environment variables.
+func NewServer(t *testing.T) *testserver.Server {
+	t.Helper()
+
+	srv := testserver.New(t)
+	setEnv(t, srv)
+
+	return srv
+}
+
+// NewTLSServer is NewServer serving HTTPS, see testserver.NewTLS.
+// The certificate of the server is not trusted, the tests configure ca_bundle.
+func NewTLSServer(t *testing.T, clientCAs *x509.CertPool) *testserver.Server {
+	t.Helper()
+
+	srv := testserver.NewTLS(t, clientCAs)
+	setEnv(t, srv)
+
+	return srv
+}
+
+// setEnv points the provider to srv through the environment variables.
+func setEnv(t *testing.T, srv *testserver.Server) {
+	t.Helper()
+
+	t.Setenv("TCDN_API_URL", srv.URL)
+	t.Setenv("TCDN_COMPANY_ID", strconv.Itoa(testserver.DefaultCompanyID))
+	t.Setenv("TCDN_CLIENT_ID", testserver.ClientID)
+	t.Setenv("TCDN_CLIENT_SECRET", testserver.ClientSecret)
+	t.Setenv("TCDN_MAX_BACKOFF", "1")
+	// Keep the credentials profiles of the workstation out of the tests
+	t.Setenv("TCDN_CONFIG_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("TCDN_PROFILE", "")
+}
+
+// SelfSignedCertificate returns a PEM encoded certificate for domains, and its private key.
+// The first domain is used as the common name.
+func SelfSignedCertificate(t *testing.T, domains ...string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		DNSNames:     domains,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(30 * 24 * time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	return string(certPEM), string(keyPEM)
+}
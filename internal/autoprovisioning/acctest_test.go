@@ -0,0 +1,32 @@
+package autoprovisioning_test
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+// removeFromAPI deletes the object behind resourceName from the fake API, as if it was removed from the dashboard.
+func removeFromAPI(srv *testserver.Server, resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%s not found in the state", resourceName)
+		}
+
+		id, err := strconv.Atoi(rs.Primary.Attributes["id"])
+		if err != nil {
+			return err
+		}
+
+		if !srv.Remove(id) {
+			return fmt.Errorf("%s (id %d) not found in the API", resourceName, id)
+		}
+
+		return nil
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -40,6 +41,7 @@ func (*backendDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 		MarkdownDescription: "Read a backend.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("backend"),
 			"id": schema.Int64Attribute{
 				Computed:            true,
 				Description:         "ID of the backend.",
@@ -115,7 +117,9 @@ func (d *backendDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	// Read the config to the state
 	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
 
-	backend, err := d.client.GetBackendByName(state.Name.ValueString(), apiEnv)
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	backend, err := d.client.GetBackendByName(ctx, state.Name.ValueString(), apiEnv)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			fmt.Sprintf("Unable to read the backend with name: %+v", state.Name),
@@ -141,6 +145,7 @@ func (d *backendDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	state.HCDisabled = types.BoolValue(backend.HCDisabled)
 
 	// Set state
+	state.CompanyID = helpers.CompanyIDValue(ctx, d.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -0,0 +1,125 @@
+package autoprovisioning
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &backendListResource{}
+	_ list.ListResourceWithConfigure = &backendListResource{}
+)
+
+// NewBackendListResource is a helper function to simplify the provider implementation.
+func NewBackendListResource() list.ListResource {
+	return &backendListResource{}
+}
+
+// backendListResource lists the backends for 'terraform query'.
+type backendListResource struct {
+	client *teclient.Client
+}
+
+type backendListConfig struct {
+	CompanyID   types.Int64  `tfsdk:"company_id"`
+	NameRegex   types.String `tfsdk:"name_regex"`
+	OriginRegex types.String `tfsdk:"origin_regex"`
+}
+
+// Metadata returns the type name of the listed resource.
+func (*backendListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_backend"
+}
+
+// ListResourceConfigSchema defines the filters of the list block.
+func (*backendListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Lists the backends of the company.",
+		MarkdownDescription: "Lists the backends of the company.",
+		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDListAttribute("backends"),
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only list the backends with a name matching this regular expression.",
+				MarkdownDescription: "Only list the backends with a name matching this regular expression.",
+			},
+			"origin_regex": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only list the backends with an origin matching this regular expression.",
+				MarkdownDescription: "Only list the backends with an origin matching this regular expression.",
+			},
+		},
+	}
+}
+
+// List streams the backends matching the filters.
+func (r *backendListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config backendListConfig
+
+	diags := req.Config.Get(ctx, &config)
+
+	nameRegex := helpers.ListRegexp("name_regex", config.NameRegex, &diags)
+	originRegex := helpers.ListRegexp("origin_regex", config.OriginRegex, &diags)
+
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, config.CompanyID)
+
+	backends, err := r.client.GetBackends(ctx, apiEnv)
+	if err != nil {
+		diags.AddError("Unable to list the backends", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	var matching []teclient.BackendAPIModel
+
+	for _, backend := range backends {
+		if (nameRegex == nil || nameRegex.MatchString(backend.Name)) && (originRegex == nil || originRegex.MatchString(backend.Origin)) {
+			matching = append(matching, backend)
+		}
+	}
+
+	companyID := helpers.CompanyIDValue(ctx, r.client)
+
+	stream.Results = helpers.ListResults(ctx, req, matching, func(backend teclient.BackendAPIModel) (helpers.ListItem, diag.Diagnostics) {
+		state := Backend{CompanyID: companyID}
+		state.fromAPI(&backend)
+
+		return helpers.ListItem{
+			DisplayName: backend.Name,
+			Identity:    BackendIdentity{CompanyID: companyID, Name: state.Name},
+			Resource:    &state,
+		}, nil
+	})
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *backendListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*teclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to configure", "error while configuring API client")
+
+		return
+	}
+
+	r.client = client
+}
@@ -10,16 +10,19 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -28,8 +31,24 @@ var (
 	_ resource.Resource                = &backendResource{}
 	_ resource.ResourceWithConfigure   = &backendResource{}
 	_ resource.ResourceWithImportState = &backendResource{}
+	_ resource.ResourceWithModifyPlan  = &backendResource{}
+	_ resource.ResourceWithIdentity    = &backendResource{}
 )
 
+// backendAPIFields maps the API fields of a backend to the resource attributes.
+var backendAPIFields = map[string]path.Path{
+	"name":          path.Root("name"),
+	"origin":        path.Root("origin"),
+	"ssl":           path.Root("ssl"),
+	"port":          path.Root("port"),
+	"extra_headers": path.Root("headers"),
+	"host":          path.Root("hchost"),
+	"health_check":  path.Root("hcpath"),
+	"status_code":   path.Root("hcstatuscode"),
+	"interval":      path.Root("hcinterval"),
+	"no_probe":      path.Root("hcdisabled"),
+}
+
 // NewBackendResource is a helper function to simplify the provider implementation.
 func NewBackendResource() resource.Resource {
 	return &backendResource{}
@@ -43,6 +62,8 @@ type backendResource struct {
 // Metadata returns the resource type name.
 func (*backendResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_backend"
+	// Renaming a backend changes its identity
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Schema defines the schema for the resource.
@@ -52,6 +73,7 @@ func (*backendResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 		MarkdownDescription: "Provides a Backend resource. This allows backends to be created, updated and deleted.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDResourceAttribute("backend"),
 			"id": schema.Int64Attribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.Int64{
@@ -79,7 +101,10 @@ func (*backendResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				MarkdownDescription: "Name of the backend.",
 			},
 			"vclname": schema.StringAttribute{
-				Computed:            true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Description:         "Final unique name of the backend to be referenced in VCL Code: 'c{company_id}_{name}'.",
 				MarkdownDescription: "Final unique name of the backend to be referenced in VCL Code: `c{company_id}_{name}`.",
 			},
@@ -165,6 +190,8 @@ func (r *backendResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	tflog.Info(ctx, "Creating backend: "+plan.Name.ValueString())
 	newBackend := teclient.NewBackendAPIModel{
 		Name:         plan.Name.ValueString(),
@@ -179,12 +206,10 @@ func (r *backendResource) Create(ctx context.Context, req resource.CreateRequest
 		HCDisabled:   plan.HCDisabled.ValueBool(),
 	}
 
-	backendState, errCreate := r.client.CreateBackend(newBackend, apiEnv)
+	backendState, errCreate := r.client.CreateBackend(ctx, newBackend, apiEnv)
 	if errCreate != nil {
-		resp.Diagnostics.AddError(
-			"Error creating backend",
-			fmt.Sprintf("Could not create the backend '%s': %s", plan.Name.ValueString(), errCreate),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error creating backend",
+			fmt.Sprintf("Could not create the backend '%s'", plan.Name.ValueString()), errCreate, backendAPIFields)
 
 		return
 	}
@@ -204,7 +229,9 @@ func (r *backendResource) Create(ctx context.Context, req resource.CreateRequest
 	plan.HCInterval = types.Int64Value(int64(backendState.HCInterval))
 	plan.HCDisabled = types.BoolValue(backendState.HCDisabled)
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, BackendIdentity{CompanyID: plan.CompanyID, Name: plan.Name})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -218,6 +245,8 @@ func (r *backendResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	tflog.Info(ctx, "Updating backend: "+plan.Name.ValueString())
 	newBackend := teclient.BackendAPIModel{
 		ID:           int(plan.ID.ValueInt64()),
@@ -234,12 +263,10 @@ func (r *backendResource) Update(ctx context.Context, req resource.UpdateRequest
 		HCDisabled:   plan.HCDisabled.ValueBool(),
 	}
 
-	backendState, errCreate := r.client.UpdateBackend(newBackend, apiEnv)
+	backendState, errCreate := r.client.UpdateBackend(ctx, newBackend, apiEnv)
 	if errCreate != nil {
-		resp.Diagnostics.AddError(
-			"Error updating backend",
-			fmt.Sprintf("Could not update the backend '%s': %s", plan.Name.ValueString(), errCreate),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error updating backend",
+			fmt.Sprintf("Could not update the backend '%s'", plan.Name.ValueString()), errCreate, backendAPIFields)
 
 		return
 	}
@@ -259,7 +286,9 @@ func (r *backendResource) Update(ctx context.Context, req resource.UpdateRequest
 	plan.HCInterval = types.Int64Value(int64(backendState.HCInterval))
 	plan.HCDisabled = types.BoolValue(backendState.HCDisabled)
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, BackendIdentity{CompanyID: plan.CompanyID, Name: plan.Name})...)
 }
 
 // Read resource information.
@@ -274,54 +303,60 @@ func (r *backendResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	var (
+		backend *teclient.BackendAPIModel
+		err     error
+	)
+
 	// Try to find by ID
 	if !state.ID.IsNull() {
-		backend, err := r.client.GetBackend(int(state.ID.ValueInt64()), apiEnv)
-		if err == nil {
-			state.ID = types.Int64Value(int64(backend.ID))
-			state.Company = types.Int64Value(int64(backend.Company))
-			state.Name = types.StringValue(backend.Name)
-			state.VclName = types.StringValue("c" + strconv.Itoa(backend.Company) + "_" + backend.Name)
-			state.Origin = types.StringValue(backend.Origin)
-			state.Ssl = types.BoolValue(backend.Ssl)
-			state.Port = types.Int64Value(int64(backend.Port))
-			state.Headers = types.StringValue(backend.Headers)
-			state.HCHost = types.StringValue(backend.HCHost)
-			state.HCPath = types.StringValue(backend.HCPath)
-			state.HCStatusCode = types.Int64Value(int64(backend.HCStatusCode))
-			state.HCInterval = types.Int64Value(int64(backend.HCInterval))
-			state.HCDisabled = types.BoolValue(backend.HCDisabled)
-			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-
-			return
-		}
+		backend, err = r.client.GetBackend(ctx, int(state.ID.ValueInt64()), apiEnv)
 	}
 
-	// Try to find by Name
-	backend, err := r.client.GetBackendByName(state.Name.ValueString(), apiEnv)
-	if err == nil {
-		if backend.Name == state.Name.ValueString() {
-			state.ID = types.Int64Value(int64(backend.ID))
-			state.Company = types.Int64Value(int64(backend.Company))
-			state.Name = types.StringValue(backend.Name)
-			state.VclName = types.StringValue("c" + strconv.Itoa(backend.Company) + "_" + backend.Name)
-			state.Origin = types.StringValue(backend.Origin)
-			state.Ssl = types.BoolValue(backend.Ssl)
-			state.Port = types.Int64Value(int64(backend.Port))
-			state.Headers = types.StringValue(backend.Headers)
-			state.HCHost = types.StringValue(backend.HCHost)
-			state.HCPath = types.StringValue(backend.HCPath)
-			state.HCStatusCode = types.Int64Value(int64(backend.HCStatusCode))
-			state.HCInterval = types.Int64Value(int64(backend.HCInterval))
-			state.HCDisabled = types.BoolValue(backend.HCDisabled)
-			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-
-			return
-		}
+	// Try to find by Name, imports only know the name
+	if state.ID.IsNull() || teclient.IsNotFound(err) {
+		backend, err = r.client.GetBackendByName(ctx, state.Name.ValueString(), apiEnv)
 	}
 
-	// Not found
-	resp.Diagnostics.AddError("Backend not found", "Backend '"+state.Name.ValueString()+"' doesn't exist in API")
+	if teclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Backend '"+state.Name.ValueString()+"' no longer exists, removing it from the state")
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading backend",
+			fmt.Sprintf("Could not read the backend '%s': %s", state.Name.ValueString(), err),
+		)
+
+		return
+	}
+
+	state.fromAPI(backend)
+	state.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, BackendIdentity{CompanyID: state.CompanyID, Name: state.Name})...)
+}
+
+// fromAPI sets the attributes read from the API, for Read, the backends listing and the list resource.
+func (b *BackendAttributes) fromAPI(backend *teclient.BackendAPIModel) {
+	b.ID = types.Int64Value(int64(backend.ID))
+	b.Company = types.Int64Value(int64(backend.Company))
+	b.Name = types.StringValue(backend.Name)
+	b.VclName = types.StringValue("c" + strconv.Itoa(backend.Company) + "_" + backend.Name)
+	b.Origin = types.StringValue(backend.Origin)
+	b.Ssl = types.BoolValue(backend.Ssl)
+	b.Port = types.Int64Value(int64(backend.Port))
+	b.Headers = types.StringValue(backend.Headers)
+	b.HCHost = types.StringValue(backend.HCHost)
+	b.HCPath = types.StringValue(backend.HCPath)
+	b.HCStatusCode = types.Int64Value(int64(backend.HCStatusCode))
+	b.HCInterval = types.Int64Value(int64(backend.HCInterval))
+	b.HCDisabled = types.BoolValue(backend.HCDisabled)
 }
 
 // Delete.
@@ -335,10 +370,12 @@ func (r *backendResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	// 204 on successful delete
 	tflog.Info(ctx, "Deleting backend: '"+state.Name.ValueString()+"' with id: "+state.ID.String())
 
-	err := r.client.DeleteBackend(int(state.ID.ValueInt64()), apiEnv)
+	err := r.client.DeleteBackend(ctx, int(state.ID.ValueInt64()), apiEnv)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting a backend",
@@ -349,6 +386,32 @@ func (r *backendResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 }
 
+// ModifyPlan refuses any change in read-only mode, and plans the vclname of a renamed backend.
+func (r *backendResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	helpers.CheckReadOnlyPlan(r.client, "backend", req, resp)
+
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var state, plan Backend
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.Name.Equal(state.Name) {
+		return
+	}
+
+	// vclname is otherwise kept from the state, changing the company replaces the backend
+	vclName := types.StringUnknown()
+	if !plan.Name.IsUnknown() {
+		vclName = types.StringValue("c" + strconv.FormatInt(state.Company.ValueInt64(), 10) + "_" + plan.Name.ValueString())
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("vclname"), vclName)...)
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *backendResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -365,6 +428,31 @@ func (r *backendResource) Configure(_ context.Context, req resource.ConfigureReq
 	r.client = client
 }
 
+// IdentitySchema defines the identity of the resource, to import it with an identity instead of an ID.
+func (*backendResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"company_id": helpers.CompanyIDIdentityAttribute(),
+			"name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Name of the backend.",
+			},
+		},
+	}
+}
+
 func (*backendResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	// Imported with an identity instead of an ID
+	if req.ID == "" {
+		helpers.ImportFromIdentity(ctx, req, resp)
+
+		return
+	}
+
+	importID, ok := helpers.ImportCompanyID(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), importID)...)
 }
@@ -0,0 +1,302 @@
+package autoprovisioning_test
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck/queryfilter"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+func testAccBackendConfig(name string, origin string) string {
+	return `
+resource "transparentedge_backend" "test" {
+  name         = "` + name + `"
+  origin       = "` + origin + `"
+  port         = 443
+  ssl          = true
+  hchost       = "www.example.com"
+  hcpath       = "/favicon.ico"
+  hcstatuscode = 200
+}
+`
+}
+
+func TestAccBackendResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig("origin1", "origin.example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+					resource.TestCheckResourceAttr("transparentedge_backend.test", "company", strconv.Itoa(testserver.DefaultCompanyID)),
+					resource.TestCheckResourceAttr("transparentedge_backend.test", "vclname", "c300_origin1"),
+					resource.TestCheckResourceAttr("transparentedge_backend.test", "origin", "origin.example.com"),
+					resource.TestCheckResourceAttr("transparentedge_backend.test", "hcinterval", "40"),
+					resource.TestCheckResourceAttr("transparentedge_backend.test", "hcdisabled", "false"),
+				),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectIdentity("transparentedge_backend.test", map[string]knownvalue.Check{
+						"company_id": knownvalue.Int64Exact(testserver.DefaultCompanyID),
+						"name":       knownvalue.StringExact("origin1"),
+					}),
+				},
+			},
+			{
+				ResourceName:      "transparentedge_backend.test",
+				ImportState:       true,
+				ImportStateId:     "origin1",
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:    "transparentedge_backend.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			{
+				Config: testAccBackendConfig("origin1", "origin2.example.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_backend.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("transparentedge_backend.test", "origin", "origin2.example.com"),
+			},
+		},
+	})
+}
+
+func TestAccBackendResource_removedOutsideTerraform(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig("origin1", "origin.example.com"),
+				Check:  removeFromAPI(srv, "transparentedge_backend.test"),
+				// The next refresh drops the backend from the state and plans to create it again
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccBackendConfig("origin1", "origin.example.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_backend.test", plancheck.ResourceActionCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccBackendResource_validationError(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_backend" "duplicated" {
+  name         = transparentedge_backend.test.name
+  origin       = "other.example.com"
+  port         = 80
+  ssl          = false
+  hchost       = "www.example.com"
+  hcpath       = "/"
+  hcstatuscode = 200
+}
+`,
+				ExpectError: regexp.MustCompile(`backend with this name\s+already\s+exists`),
+			},
+		},
+	})
+}
+
+func TestAccBackendDataSources(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig("origin1", "origin.example.com") + `
+data "transparentedge_backend" "test" {
+  name = transparentedge_backend.test.name
+}
+
+data "transparentedge_backends" "all" {
+  depends_on = [transparentedge_backend.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.transparentedge_backend.test", "id", "transparentedge_backend.test", "id"),
+					resource.TestCheckResourceAttr("data.transparentedge_backend.test", "vclname", "c300_origin1"),
+					resource.TestCheckResourceAttr("data.transparentedge_backend.test", "origin", "origin.example.com"),
+					resource.TestCheckResourceAttr("data.transparentedge_backends.all", "backends.#", "1"),
+					resource.TestCheckResourceAttr("data.transparentedge_backends.all", "backends.0.name", "origin1"),
+				),
+			},
+			{
+				Config: `
+data "transparentedge_backend" "missing" {
+  name = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`missing`),
+			},
+		},
+	})
+}
+
+func TestAccBackendResource_companyOverride(t *testing.T) {
+	srv := acctest.NewServer(t)
+	srv.AddCompany(301)
+
+	config := `
+resource "transparentedge_backend" "test" {
+  company_id   = 301
+  name         = "origin1"
+  origin       = "origin.example.com"
+  port         = 443
+  ssl          = true
+  hchost       = "www.example.com"
+  hcpath       = "/favicon.ico"
+  hcstatuscode = 200
+}
+
+data "transparentedge_backends" "default" {
+  depends_on = [transparentedge_backend.test]
+}
+
+data "transparentedge_backends" "other" {
+  company_id = 301
+  depends_on = [transparentedge_backend.test]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("transparentedge_backend.test", "company_id", "301"),
+					resource.TestCheckResourceAttr("transparentedge_backend.test", "company", "301"),
+					resource.TestCheckResourceAttr("transparentedge_backend.test", "vclname", "c301_origin1"),
+					resource.TestCheckResourceAttr("data.transparentedge_backends.default", "company_id", strconv.Itoa(testserver.DefaultCompanyID)),
+					resource.TestCheckResourceAttr("data.transparentedge_backends.default", "backends.#", "0"),
+					resource.TestCheckResourceAttr("data.transparentedge_backends.other", "backends.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "transparentedge_backend.test",
+				ImportState:       true,
+				ImportStateId:     "301/origin1",
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:    "transparentedge_backend.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			{
+				ResourceName:  "transparentedge_backend.test",
+				ImportState:   true,
+				ImportStateId: "acme/origin1",
+				ExpectError:   regexp.MustCompile(`must be a number greater than 0`),
+			},
+		},
+	})
+}
+
+func TestAccBackendListResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	config := testAccBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_backend" "other" {
+  name         = "images"
+  origin       = "images.example.com"
+  port         = 80
+  ssl          = false
+  hchost       = "images.example.com"
+  hcpath       = "/"
+  hcstatuscode = 200
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Query:          true,
+				GenerateConfig: true,
+				Config: `
+provider "transparentedge" {}
+
+list "transparentedge_backend" "all" {
+  provider         = transparentedge
+  include_resource = true
+}
+
+list "transparentedge_backend" "origins" {
+  provider = transparentedge
+
+  config {
+    name_regex = "^origin"
+  }
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("transparentedge_backend.all", 2),
+					querycheck.ExpectIdentity("transparentedge_backend.all", map[string]knownvalue.Check{
+						"company_id": knownvalue.Int64Exact(testserver.DefaultCompanyID),
+						"name":       knownvalue.StringExact("images"),
+					}),
+					querycheck.ExpectResourceKnownValues("transparentedge_backend.all", queryfilter.ByDisplayName(knownvalue.StringExact("images")), []querycheck.KnownValueCheck{
+						{
+							Path:       tfjsonpath.New("origin"),
+							KnownValue: knownvalue.StringExact("images.example.com"),
+						},
+					}),
+					querycheck.ExpectLength("transparentedge_backend.origins", 1),
+					querycheck.ExpectIdentity("transparentedge_backend.origins", map[string]knownvalue.Check{
+						"company_id": knownvalue.Int64Exact(testserver.DefaultCompanyID),
+						"name":       knownvalue.StringExact("origin1"),
+					}),
+				},
+			},
+			{
+				Query: true,
+				Config: `
+provider "transparentedge" {}
+
+list "transparentedge_backend" "invalid" {
+  provider = transparentedge
+
+  config {
+    name_regex = "("
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid regular expression`),
+			},
+		},
+	})
+}
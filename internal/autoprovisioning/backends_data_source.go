@@ -2,12 +2,11 @@ package autoprovisioning
 
 import (
 	"context"
-	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -39,6 +38,7 @@ func (*backendsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 		MarkdownDescription: "Backend listing.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("backends"),
 			"backends": schema.ListNestedAttribute{
 				Computed:            true,
 				Description:         "List of all backends.",
@@ -118,10 +118,18 @@ func (*backendsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 }
 
 // Read refreshes the Terraform state with the latest data.
-func (d *backendsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (d *backendsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state Backends
 
-	backends, err := d.client.GetBackends(apiEnv)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	backends, err := d.client.GetBackends(ctx, apiEnv)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to read Backends info",
@@ -133,26 +141,14 @@ func (d *backendsDataSource) Read(ctx context.Context, _ datasource.ReadRequest,
 
 	// Map response body to model
 	for _, backend := range backends {
-		backendState := Backend{
-			ID:           types.Int64Value(int64(backend.ID)),
-			Company:      types.Int64Value(int64(backend.Company)),
-			Name:         types.StringValue(backend.Name),
-			VclName:      types.StringValue("c" + strconv.Itoa(backend.Company) + "_" + backend.Name),
-			Origin:       types.StringValue(backend.Origin),
-			Ssl:          types.BoolValue(backend.Ssl),
-			Port:         types.Int64Value(int64(backend.Port)),
-			Headers:      types.StringValue(backend.Headers),
-			HCHost:       types.StringValue(backend.HCHost),
-			HCPath:       types.StringValue(backend.HCPath),
-			HCStatusCode: types.Int64Value(int64(backend.HCStatusCode)),
-			HCInterval:   types.Int64Value(int64(backend.HCInterval)),
-			HCDisabled:   types.BoolValue(backend.HCDisabled),
-		}
+		var backendState BackendAttributes
+		backendState.fromAPI(&backend)
 
 		state.Backends = append(state.Backends, backendState)
 	}
 
 	// Set state
+	state.CompanyID = helpers.CompanyIDValue(ctx, d.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -0,0 +1,124 @@
+package autoprovisioning
+
+import (
+	"context"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &customCertificateListResource{}
+	_ list.ListResourceWithConfigure = &customCertificateListResource{}
+)
+
+// NewCustomCertificateListResource is a helper function to simplify the provider implementation.
+func NewCustomCertificateListResource() list.ListResource {
+	return &customCertificateListResource{}
+}
+
+// customCertificateListResource lists the custom certificates for 'terraform query'.
+type customCertificateListResource struct {
+	client *teclient.Client
+}
+
+type customCertificateListConfig struct {
+	CompanyID   types.Int64  `tfsdk:"company_id"`
+	DomainRegex types.String `tfsdk:"domain_regex"`
+}
+
+// Metadata returns the type name of the listed resource.
+func (*customCertificateListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_custom_certificate"
+}
+
+// ListResourceConfigSchema defines the filters of the list block.
+func (*customCertificateListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Lists the custom certificates of the company.",
+		MarkdownDescription: "Lists the custom certificates of the company.",
+		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDListAttribute("custom certificates"),
+			"domain_regex": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only list the certificates with a domain (common name or SAN) matching this regular expression.",
+				MarkdownDescription: "Only list the certificates with a domain (common name or SAN) matching this regular expression.",
+			},
+		},
+	}
+}
+
+// List streams the custom certificates matching the filters.
+func (r *customCertificateListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config customCertificateListConfig
+
+	diags := req.Config.Get(ctx, &config)
+
+	domainRegex := helpers.ListRegexp("domain_regex", config.DomainRegex, &diags)
+
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, config.CompanyID)
+
+	certificates, err := r.client.GetCertificates(ctx)
+	if err != nil {
+		diags.AddError("Unable to list the custom certificates", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	var matching []teclient.SSLCertificate
+
+	// Autogenerated certificates cannot be managed with the resource
+	for _, certificate := range certificates {
+		if certificate.Autogenerated || certificate.DNSChallenge {
+			continue
+		}
+
+		if domainRegex == nil || domainRegex.MatchString(certificate.CommonName) || slices.ContainsFunc(certificate.Domains, domainRegex.MatchString) {
+			matching = append(matching, certificate)
+		}
+	}
+
+	companyID := helpers.CompanyIDValue(ctx, r.client)
+
+	stream.Results = helpers.ListResults(ctx, req, matching, func(certificate teclient.SSLCertificate) (helpers.ListItem, diag.Diagnostics) {
+		state := CustomCertificate{CompanyID: companyID}
+		state.fromAPI(&certificate)
+
+		return helpers.ListItem{
+			DisplayName: certificate.CommonName,
+			Identity:    ObjectIdentity{CompanyID: companyID, ID: state.ID},
+			Resource:    &state,
+		}, nil
+	})
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *customCertificateListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*teclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to configure", "error while configuring API client")
+
+		return
+	}
+
+	r.client = client
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -25,8 +26,16 @@ var (
 	_ resource.Resource                = &customCertificateResource{}
 	_ resource.ResourceWithConfigure   = &customCertificateResource{}
 	_ resource.ResourceWithImportState = &customCertificateResource{}
+	_ resource.ResourceWithModifyPlan  = &customCertificateResource{}
+	_ resource.ResourceWithIdentity    = &customCertificateResource{}
 )
 
+// customCertificateAPIFields maps the API fields of a custom certificate to the resource attributes.
+var customCertificateAPIFields = map[string]path.Path{
+	"cert": path.Root("publickey"),
+	"key":  path.Root("privatekey"),
+}
+
 // NewCustomCertificate is a helper function to simplify the provider implementation.
 func NewCustomCertificate() resource.Resource {
 	return &customCertificateResource{}
@@ -49,6 +58,7 @@ func (*customCertificateResource) Schema(_ context.Context, _ resource.SchemaReq
 		MarkdownDescription: "Provides Custom Certificate resource. This allows to create, update and delete custom TLS Certificates.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDResourceAttribute("certificate"),
 			"id": schema.Int64Attribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.Int64{
@@ -98,6 +108,8 @@ func (r *customCertificateResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	tflog.Info(ctx, "Creating Custom Certificate")
 
 	newCustomCertificate := teclient.SSLCustomCertificate{
@@ -107,12 +119,10 @@ func (r *customCertificateResource) Create(ctx context.Context, req resource.Cre
 		PrivateKey:    plan.PrivateKey.ValueString(),
 	}
 
-	customCertificateState, errCreate := r.client.CreateCustomCertificate(newCustomCertificate)
+	customCertificateState, errCreate := r.client.CreateCustomCertificate(ctx, newCustomCertificate)
 	if errCreate != nil {
-		resp.Diagnostics.AddError(
-			"Error creating Custom Certificate",
-			fmt.Sprintf("Could not create the Custom Certificate: %s", errCreate),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error creating Custom Certificate",
+			"Could not create the Custom Certificate", errCreate, customCertificateAPIFields)
 
 		return
 	}
@@ -138,7 +148,9 @@ func (r *customCertificateResource) Create(ctx context.Context, req resource.Cre
 	// plan.PublicKey = types.StringValue(customCertificateState.PublicKey)
 	// plan.PrivateKey = types.StringValue(customCertificateState.PrivateKey)
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: plan.CompanyID, ID: plan.ID})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -152,6 +164,8 @@ func (r *customCertificateResource) Update(ctx context.Context, req resource.Upd
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	tflog.Info(ctx, "Updating Custom Certificate")
 
 	existingCustomCertificate := teclient.SSLCustomCertificate{
@@ -162,12 +176,10 @@ func (r *customCertificateResource) Update(ctx context.Context, req resource.Upd
 		PrivateKey:    plan.PrivateKey.ValueString(),
 	}
 
-	customCertificateState, errCreate := r.client.UpdateCustomCertificate(existingCustomCertificate)
+	customCertificateState, errCreate := r.client.UpdateCustomCertificate(ctx, existingCustomCertificate)
 	if errCreate != nil {
-		resp.Diagnostics.AddError(
-			"Error updating Custom Certificate",
-			fmt.Sprintf("Could not update the Custom Certificate: %s", errCreate),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error updating Custom Certificate",
+			"Could not update the Custom Certificate", errCreate, customCertificateAPIFields)
 
 		return
 	}
@@ -193,7 +205,9 @@ func (r *customCertificateResource) Update(ctx context.Context, req resource.Upd
 	// plan.PublicKey = types.StringValue(customCertificateState.PublicKey)
 	// plan.PrivateKey = types.StringValue(customCertificateState.PrivateKey)
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: plan.CompanyID, ID: plan.ID})...)
 }
 
 // Read resource information.
@@ -208,8 +222,17 @@ func (r *customCertificateResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	// Try to find by ID
-	customCertificate, err := r.client.GetCertificate(int(state.ID.ValueInt64()))
+	customCertificate, err := r.client.GetCertificate(ctx, int(state.ID.ValueInt64()))
+	if teclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Custom Certificate "+state.ID.String()+" no longer exists, removing it from the state")
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading Custom Certificate",
@@ -228,32 +251,39 @@ func (r *customCertificateResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
+	state.fromAPI(customCertificate)
+	state.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: state.CompanyID, ID: state.ID})...)
+}
+
+// fromAPI sets the attributes read from the API, for Read and the list resource.
+// The keys are kept when they only differ in their format from the ones in the state.
+func (c *CustomCertificate) fromAPI(certificate *teclient.SSLCertificate) {
 	// Sort SAN domains
-	slices.Sort(customCertificate.Domains)
-	sanDomains := strings.Join(customCertificate.Domains, ", ")
+	slices.Sort(certificate.Domains)
+	sanDomains := strings.Join(certificate.Domains, ", ")
 
 	// Parse expiration time
-	expiration := customCertificate.Expiration
+	expiration := certificate.Expiration
 
-	exptoint, err := strconv.ParseFloat(customCertificate.Expiration, 64)
+	exptoint, err := strconv.ParseFloat(certificate.Expiration, 64)
 	if err == nil {
 		expiration = time.Unix(int64(exptoint), 0).String()
 	}
 
-	state.ID = types.Int64Value(int64(customCertificate.ID))
-	state.CommonName = types.StringValue(customCertificate.CommonName)
-	state.Domains = types.StringValue(sanDomains)
-	state.Expiration = types.StringValue(expiration)
+	c.ID = types.Int64Value(int64(certificate.ID))
+	c.CommonName = types.StringValue(certificate.CommonName)
+	c.Domains = types.StringValue(sanDomains)
+	c.Expiration = types.StringValue(expiration)
 
-	if helpers.NormalizeStringForComparison(customCertificate.PublicKey) != helpers.NormalizeStringForComparison(state.PublicKey.ValueString()) {
-		state.PublicKey = types.StringValue(customCertificate.PublicKey)
+	if c.PublicKey.IsNull() || helpers.NormalizeStringForComparison(certificate.PublicKey) != helpers.NormalizeStringForComparison(c.PublicKey.ValueString()) {
+		c.PublicKey = types.StringValue(certificate.PublicKey)
 	}
 
-	if helpers.NormalizeStringForComparison(customCertificate.PrivateKey) != helpers.NormalizeStringForComparison(state.PrivateKey.ValueString()) {
-		state.PrivateKey = types.StringValue(customCertificate.PrivateKey)
+	if c.PrivateKey.IsNull() || helpers.NormalizeStringForComparison(certificate.PrivateKey) != helpers.NormalizeStringForComparison(c.PrivateKey.ValueString()) {
+		c.PrivateKey = types.StringValue(certificate.PrivateKey)
 	}
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 // Delete.
@@ -267,10 +297,12 @@ func (r *customCertificateResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	// 204 on successful delete
 	tflog.Info(ctx, "Deleting Custom Certificate with ID: '"+state.ID.String()+"'")
 
-	err := r.client.DeleteCustomCertificate(int(state.ID.ValueInt64()))
+	err := r.client.DeleteCustomCertificate(ctx, int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting a Custom Certificate",
@@ -281,6 +313,11 @@ func (r *customCertificateResource) Delete(ctx context.Context, req resource.Del
 	}
 }
 
+// ModifyPlan refuses any change in read-only mode.
+func (r *customCertificateResource) ModifyPlan(_ context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	helpers.CheckReadOnlyPlan(r.client, "custom certificate", req, resp)
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *customCertificateResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -297,8 +334,33 @@ func (r *customCertificateResource) Configure(_ context.Context, req resource.Co
 	r.client = client
 }
 
+// IdentitySchema defines the identity of the resource, to import it with an identity instead of an ID.
+func (*customCertificateResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"company_id": helpers.CompanyIDIdentityAttribute(),
+			"id": identityschema.Int64Attribute{
+				RequiredForImport: true,
+				Description:       "ID of the certificate.",
+			},
+		},
+	}
+}
+
 func (*customCertificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	certID, err := strconv.Atoi(req.ID)
+	// Imported with an identity instead of an ID
+	if req.ID == "" {
+		helpers.ImportFromIdentity(ctx, req, resp)
+
+		return
+	}
+
+	importID, ok := helpers.ImportCompanyID(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	certID, err := strconv.Atoi(importID)
 	if err != nil {
 		resp.Diagnostics.AddError("Invalid identifier", "Certificate ID must be a valid number.")
 
@@ -0,0 +1,131 @@
+package autoprovisioning_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+)
+
+func testAccCustomCertificateConfig(cert string, key string) string {
+	return `
+resource "transparentedge_custom_certificate" "test" {
+  publickey  = <<-EOT
+` + cert + `
+  EOT
+  privatekey = <<-EOT
+` + key + `
+  EOT
+}
+`
+}
+
+func TestAccCustomCertificateResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	cert, key := acctest.SelfSignedCertificate(t, "www.example.com", "example.com")
+	renewedCert, renewedKey := acctest.SelfSignedCertificate(t, "www.example.com", "example.com", "static.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomCertificateConfig(cert, key),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_custom_certificate.test", "id"),
+					resource.TestCheckResourceAttrSet("transparentedge_custom_certificate.test", "expiration"),
+					resource.TestCheckResourceAttr("transparentedge_custom_certificate.test", "commonname", "www.example.com"),
+					resource.TestCheckResourceAttr("transparentedge_custom_certificate.test", "domains", "example.com, www.example.com"),
+				),
+			},
+			{
+				ResourceName:      "transparentedge_custom_certificate.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:    "transparentedge_custom_certificate.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithResourceIdentity,
+			},
+			{
+				Config: testAccCustomCertificateConfig(renewedCert, renewedKey),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_custom_certificate.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("transparentedge_custom_certificate.test", "domains", "example.com, static.example.com, www.example.com"),
+			},
+		},
+	})
+}
+
+func TestAccCustomCertificateResource_removedOutsideTerraform(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	cert, key := acctest.SelfSignedCertificate(t, "www.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccCustomCertificateConfig(cert, key),
+				Check:              removeFromAPI(srv, "transparentedge_custom_certificate.test"),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccCustomCertificateConfig(cert, key),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_custom_certificate.test", plancheck.ResourceActionCreate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccCustomCertificateResource_invalidCertificate(t *testing.T) {
+	acctest.NewServer(t)
+
+	_, key := acctest.SelfSignedCertificate(t, "www.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCustomCertificateConfig("not a certificate", key),
+				ExpectError: regexp.MustCompile(`not in PEM\s+format`),
+			},
+		},
+	})
+}
+
+func TestAccCertificatesDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	cert, key := acctest.SelfSignedCertificate(t, "www.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomCertificateConfig(cert, key) + `
+data "transparentedge_certificates" "all" {
+  depends_on = [transparentedge_custom_certificate.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transparentedge_certificates.all", "certificates.#", "1"),
+					resource.TestCheckResourceAttrPair("data.transparentedge_certificates.all", "certificates.0.id", "transparentedge_custom_certificate.test", "id"),
+					resource.TestCheckResourceAttr("data.transparentedge_certificates.all", "certificates.0.commonname", "www.example.com"),
+					resource.TestCheckResourceAttr("data.transparentedge_certificates.all", "certificates.0.autogenerated", "false"),
+				),
+			},
+		},
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -42,6 +43,7 @@ func (*certificatesDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 		MarkdownDescription: "Certificate listing.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("certificates"),
 			"certificates": schema.ListNestedAttribute{
 				Computed:    true,
 				Description: "List of all certificates.",
@@ -105,10 +107,18 @@ func (*certificatesDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 }
 
 // Read refreshes the Terraform state with the latest data.
-func (d *certificatesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (d *certificatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state Certificates
 
-	certificates, err := d.client.GetCertificates()
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	certificates, err := d.client.GetCertificates(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to read Certificates info",
@@ -149,6 +159,7 @@ func (d *certificatesDataSource) Read(ctx context.Context, _ datasource.ReadRequ
 	}
 
 	// Set state
+	state.CompanyID = helpers.CompanyIDValue(ctx, d.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
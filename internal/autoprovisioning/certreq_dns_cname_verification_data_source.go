@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -37,6 +38,7 @@ func (*crDNSCNAMEVerifDataSource) Schema(_ context.Context, _ datasource.SchemaR
 		Description:         "Certificate Request DNS CNAME Verification.",
 		MarkdownDescription: "Certificate Request DNS CNAME Verification.",
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("CNAME"),
 			"cname": schema.StringAttribute{
 				Computed:            true,
 				Description:         "The CNAME to configure the _acme-challenge.{domain} record in order to perform a DNS verification by CNAME.",
@@ -52,7 +54,9 @@ func (d *crDNSCNAMEVerifDataSource) Read(ctx context.Context, req datasource.Rea
 	// Read Terraform configuration data into the model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
-	cname, err := d.client.GetDNSCNAMEVerification()
+	ctx = helpers.CompanyContext(ctx, data.CompanyID)
+
+	cname, err := d.client.GetDNSCNAMEVerification(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to retrieve DNS CNAME Verification",
@@ -67,6 +71,7 @@ func (d *crDNSCNAMEVerifDataSource) Read(ctx context.Context, req datasource.Rea
 	}
 
 	// Save data into Terraform state
+	data.CompanyID = helpers.CompanyIDValue(ctx, d.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
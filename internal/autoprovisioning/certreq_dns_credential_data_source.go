@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -39,6 +40,7 @@ func (*crDNSCredentialDataSource) Schema(_ context.Context, _ datasource.SchemaR
 		MarkdownDescription: "DNS Credential data source.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("DNS credential"),
 			"id": schema.Int64Attribute{
 				Required:            true,
 				Description:         "ID of the DNS Credential.",
@@ -71,7 +73,9 @@ func (d *crDNSCredentialDataSource) Read(ctx context.Context, req datasource.Rea
 	// Read Terraform configuration data into the model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
-	dnsCredential, err := d.client.GetCRDNSCredential(int(data.ID.ValueInt64()))
+	ctx = helpers.CompanyContext(ctx, data.CompanyID)
+
+	dnsCredential, err := d.client.GetCRDNSCredential(ctx, int(data.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failure retrieving DNS Credential.",
@@ -106,6 +110,7 @@ func (d *crDNSCredentialDataSource) Read(ctx context.Context, req datasource.Rea
 	data.Parameters = parameters
 	data.DNSProvider = types.StringValue(dnsProvider)
 
+	data.CompanyID = helpers.CompanyIDValue(ctx, d.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -0,0 +1,130 @@
+package autoprovisioning
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &crDNSCredentialListResource{}
+	_ list.ListResourceWithConfigure = &crDNSCredentialListResource{}
+)
+
+// NewCertReqDNSCredentialListResource is a helper function to simplify the provider implementation.
+func NewCertReqDNSCredentialListResource() list.ListResource {
+	return &crDNSCredentialListResource{}
+}
+
+// crDNSCredentialListResource lists the DNS credentials for 'terraform query'.
+type crDNSCredentialListResource struct {
+	client *teclient.Client
+}
+
+type crDNSCredentialListConfig struct {
+	CompanyID   types.Int64  `tfsdk:"company_id"`
+	AliasRegex  types.String `tfsdk:"alias_regex"`
+	DNSProvider types.String `tfsdk:"dns_provider"`
+}
+
+// Metadata returns the type name of the listed resource.
+func (*crDNSCredentialListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certreq_dns_credential"
+}
+
+// ListResourceConfigSchema defines the filters of the list block.
+func (*crDNSCredentialListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Lists the DNS credentials of the company.",
+		MarkdownDescription: "Lists the DNS credentials of the company.",
+		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDListAttribute("DNS credentials"),
+			"alias_regex": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only list the credentials with an alias matching this regular expression.",
+				MarkdownDescription: "Only list the credentials with an alias matching this regular expression.",
+			},
+			"dns_provider": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only list the credentials of this DNS provider, as shown in their 'dns_provider' attribute.",
+				MarkdownDescription: "Only list the credentials of this DNS provider, as shown in their `dns_provider` attribute.",
+			},
+		},
+	}
+}
+
+// List streams the DNS credentials matching the filters.
+func (r *crDNSCredentialListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config crDNSCredentialListConfig
+
+	diags := req.Config.Get(ctx, &config)
+
+	aliasRegex := helpers.ListRegexp("alias_regex", config.AliasRegex, &diags)
+
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, config.CompanyID)
+
+	credentials, err := r.client.GetCRDNSCredentials(ctx)
+	if err != nil {
+		diags.AddError("Unable to list the DNS credentials", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	var matching []teclient.CRDNSCredential
+
+	for _, credential := range credentials {
+		if aliasRegex != nil && !aliasRegex.MatchString(credential.Alias) {
+			continue
+		}
+
+		if !config.DNSProvider.IsNull() && (len(credential.Creds) == 0 || credential.Creds[0].Provider != config.DNSProvider.ValueString()) {
+			continue
+		}
+
+		matching = append(matching, credential)
+	}
+
+	companyID := helpers.CompanyIDValue(ctx, r.client)
+
+	stream.Results = helpers.ListResults(ctx, req, matching, func(credential teclient.CRDNSCredential) (helpers.ListItem, diag.Diagnostics) {
+		state := CertReqDNSCredential{CompanyID: companyID}
+		diags := state.fromAPI(&credential)
+
+		return helpers.ListItem{
+			DisplayName: credential.Alias,
+			Identity:    ObjectIdentity{CompanyID: companyID, ID: state.ID},
+			Resource:    &state,
+		}, diags
+	})
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *crDNSCredentialListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*teclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to configure", "error while configuring API client")
+
+		return
+	}
+
+	r.client = client
+}
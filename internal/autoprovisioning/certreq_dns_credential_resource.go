@@ -5,13 +5,17 @@ import (
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -20,8 +24,16 @@ var (
 	_ resource.Resource                = &crDNSCredentialResource{}
 	_ resource.ResourceWithConfigure   = &crDNSCredentialResource{}
 	_ resource.ResourceWithImportState = &crDNSCredentialResource{}
+	_ resource.ResourceWithModifyPlan  = &crDNSCredentialResource{}
+	_ resource.ResourceWithIdentity    = &crDNSCredentialResource{}
 )
 
+// dnsCredentialAPIFields maps the API fields of a DNS credential to the resource attributes.
+var dnsCredentialAPIFields = map[string]path.Path{
+	"alias": path.Root("alias"),
+	"creds": path.Root("parameters"),
+}
+
 // NewCertReqDNSCredentialResource is a helper function to simplify the provider implementation.
 func NewCertReqDNSCredentialResource() resource.Resource {
 	return &crDNSCredentialResource{}
@@ -44,6 +56,7 @@ func (*crDNSCredentialResource) Schema(_ context.Context, _ resource.SchemaReque
 		MarkdownDescription: "Provides DNS Credential resource. This allows to create, update and delete DNS Credentials used in [DNS Certificate Requests](https://docs.transparentedge.eu/getting-started/dashboard/auto-provisioning/ssl).",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDResourceAttribute("DNS credential"),
 			"id": schema.Int64Attribute{
 				Computed:            true,
 				Description:         "ID of the DNS Credential.",
@@ -85,6 +98,8 @@ func (r *crDNSCredentialResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	// Check if parameters are set
 	if plan.Parameters.IsNull() {
 		resp.Diagnostics.AddError(
@@ -115,12 +130,10 @@ func (r *crDNSCredentialResource) Create(ctx context.Context, req resource.Creat
 		Creds: creds,
 	}
 
-	newState, err := r.client.CreateDNSCredential(newData)
+	newState, err := r.client.CreateDNSCredential(ctx, newData)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating the credential",
-			err.Error(),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error creating the credential",
+			"Could not create the credential", err, dnsCredentialAPIFields)
 
 		return
 	}
@@ -148,7 +161,9 @@ func (r *crDNSCredentialResource) Create(ctx context.Context, req resource.Creat
 	plan.Parameters = newParameters
 	plan.DNSProvider = types.StringValue(dnsProvider)
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: plan.CompanyID, ID: plan.ID})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -163,6 +178,8 @@ func (r *crDNSCredentialResource) Update(ctx context.Context, req resource.Updat
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	// Check if parameters are set
 	if plan.Parameters.IsNull() {
 		resp.Diagnostics.AddError(
@@ -193,12 +210,10 @@ func (r *crDNSCredentialResource) Update(ctx context.Context, req resource.Updat
 		Creds: creds,
 	}
 
-	newState, err := r.client.UpdateDNSCredential(newData, int(plan.ID.ValueInt64()))
+	newState, err := r.client.UpdateDNSCredential(ctx, newData, int(plan.ID.ValueInt64()))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating the credential",
-			err.Error(),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error updating the credential",
+			"Could not update the credential with id: "+plan.ID.String(), err, dnsCredentialAPIFields)
 
 		return
 	}
@@ -226,7 +241,9 @@ func (r *crDNSCredentialResource) Update(ctx context.Context, req resource.Updat
 	plan.Parameters = newParameters
 	plan.DNSProvider = types.StringValue(dnsProvider)
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: plan.CompanyID, ID: plan.ID})...)
 }
 
 // Read resource information.
@@ -241,7 +258,16 @@ func (r *crDNSCredentialResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
-	credential, err := r.client.GetCRDNSCredential(int(state.ID.ValueInt64()))
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	credential, err := r.client.GetCRDNSCredential(ctx, int(state.ID.ValueInt64()))
+	if teclient.IsNotFound(err) {
+		tflog.Warn(ctx, "DNS credential "+state.ID.String()+" no longer exists, removing it from the state")
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failure retrieving the credential from the API",
@@ -251,30 +277,40 @@ func (r *crDNSCredentialResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
+	resp.Diagnostics.Append(state.fromAPI(&credential)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: state.CompanyID, ID: state.ID})...)
+}
+
+// fromAPI sets the attributes read from the API, for Read and the list resource.
+func (c *CertReqDNSCredential) fromAPI(credential *teclient.CRDNSCredential) diag.Diagnostics {
 	// Extract the parameters/keys obtained from the API into a map
-	newKeys := make(map[string]attr.Value)
+	keys := make(map[string]attr.Value)
 	dnsProvider := "Unknown"
 
 	for _, key := range credential.Creds {
-		newKeys[key.KeyName] = types.StringValue(key.KeyValue)
+		keys[key.KeyName] = types.StringValue(key.KeyValue)
 		dnsProvider = key.Provider
 	}
 
 	// Transform the map into a Terraform type
-	newParameters, diags := types.MapValue(types.StringType, newKeys)
-	resp.Diagnostics.Append(diags...)
-
-	if resp.Diagnostics.HasError() {
-		return
+	parameters, diags := types.MapValue(types.StringType, keys)
+	if diags.HasError() {
+		return diags
 	}
 
-	// Set state to fully populated data
-	state.ID = types.Int64Value(int64(credential.ID))
-	state.Alias = types.StringValue(credential.Alias)
-	state.Parameters = newParameters
-	state.DNSProvider = types.StringValue(dnsProvider)
+	c.ID = types.Int64Value(int64(credential.ID))
+	c.Alias = types.StringValue(credential.Alias)
+	c.Parameters = parameters
+	c.DNSProvider = types.StringValue(dnsProvider)
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	return diags
 }
 
 // Delete.
@@ -288,8 +324,10 @@ func (r *crDNSCredentialResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	// 204 on successful delete
-	err := r.client.DeleteCRCredential(int(state.ID.ValueInt64()))
+	err := r.client.DeleteCRCredential(ctx, int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting the credential",
@@ -300,6 +338,11 @@ func (r *crDNSCredentialResource) Delete(ctx context.Context, req resource.Delet
 	}
 }
 
+// ModifyPlan refuses any change in read-only mode.
+func (r *crDNSCredentialResource) ModifyPlan(_ context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	helpers.CheckReadOnlyPlan(r.client, "DNS credential", req, resp)
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *crDNSCredentialResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -316,8 +359,33 @@ func (r *crDNSCredentialResource) Configure(_ context.Context, req resource.Conf
 	r.client = client
 }
 
+// IdentitySchema defines the identity of the resource, to import it with an identity instead of an ID.
+func (*crDNSCredentialResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"company_id": helpers.CompanyIDIdentityAttribute(),
+			"id": identityschema.Int64Attribute{
+				RequiredForImport: true,
+				Description:       "ID of the DNS credential.",
+			},
+		},
+	}
+}
+
 func (*crDNSCredentialResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.Atoi(req.ID)
+	// Imported with an identity instead of an ID
+	if req.ID == "" {
+		helpers.ImportFromIdentity(ctx, req, resp)
+
+		return
+	}
+
+	importID, ok := helpers.ImportCompanyID(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(importID)
 	if err != nil {
 		resp.Diagnostics.AddError("Invalid identifier", "ID must be a valid number.")
 
@@ -0,0 +1,185 @@
+package autoprovisioning_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck/queryfilter"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+func testAccDNSCredentialConfig(alias string, secret string) string {
+	return `
+resource "transparentedge_certreq_dns_credential" "test" {
+  alias = "` + alias + `"
+  parameters = {
+    AWS_ACCESS_KEY_ID     = "AKIAEXAMPLE"
+    AWS_SECRET_ACCESS_KEY = "` + secret + `"
+  }
+}
+`
+}
+
+func TestAccDNSCredentialResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDNSCredentialConfig("route53", "secret1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_certreq_dns_credential.test", "id"),
+					resource.TestCheckResourceAttr("transparentedge_certreq_dns_credential.test", "dns_provider", "AWS (Route53)"),
+					resource.TestCheckResourceAttr("transparentedge_certreq_dns_credential.test", "parameters.%", "2"),
+				),
+			},
+			{
+				ResourceName:      "transparentedge_certreq_dns_credential.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccDNSCredentialConfig("route53-rotated", "secret2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_certreq_dns_credential.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("transparentedge_certreq_dns_credential.test", "alias", "route53-rotated"),
+					resource.TestCheckResourceAttr("transparentedge_certreq_dns_credential.test", "parameters.AWS_SECRET_ACCESS_KEY", "secret2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDNSCredentialResource_mixedProviders(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "transparentedge_certreq_dns_credential" "test" {
+  alias = "mixed"
+  parameters = {
+    AWS_ACCESS_KEY_ID = "AKIAEXAMPLE"
+    CF_Token          = "token"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`same DNS\s+provider`),
+			},
+		},
+	})
+}
+
+func TestAccDNSCredentialDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDNSCredentialConfig("route53", "secret1") + `
+data "transparentedge_certreq_dns_credential" "test" {
+  id = transparentedge_certreq_dns_credential.test.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transparentedge_certreq_dns_credential.test", "alias", "route53"),
+					resource.TestCheckResourceAttr("data.transparentedge_certreq_dns_credential.test", "dns_provider", "AWS (Route53)"),
+					resource.TestCheckResourceAttr("data.transparentedge_certreq_dns_credential.test", "parameters.AWS_ACCESS_KEY_ID", "AKIAEXAMPLE"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDNSProvidersDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "transparentedge_certreq_dns_providers" "all" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transparentedge_certreq_dns_providers.all", "providers.#", "4"),
+					resource.TestCheckResourceAttr("data.transparentedge_certreq_dns_providers.all", "providers.0.dns_provider", "AWS (Route53)"),
+					resource.TestCheckTypeSetElemAttr("data.transparentedge_certreq_dns_providers.all", "providers.0.parameters.*", "AWS_SECRET_ACCESS_KEY"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDNSCNAMEVerificationDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "transparentedge_certreq_dns_cname_verification" "test" {}`,
+				Check:  resource.TestCheckResourceAttr("data.transparentedge_certreq_dns_cname_verification.test", "cname", testserver.DNSCNAMEVerification(testserver.DefaultCompanyID)),
+			},
+		},
+	})
+}
+
+func TestAccDNSCredentialListResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDNSCredentialConfig("route53", "secret1"),
+			},
+			{
+				Query: true,
+				Config: `
+provider "transparentedge" {}
+
+list "transparentedge_certreq_dns_credential" "route53" {
+  provider         = transparentedge
+  include_resource = true
+
+  config {
+    dns_provider = "AWS (Route53)"
+  }
+}
+
+list "transparentedge_certreq_dns_credential" "cloudflare" {
+  provider = transparentedge
+
+  config {
+    alias_regex = "^cloudflare"
+  }
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("transparentedge_certreq_dns_credential.route53", 1),
+					querycheck.ExpectResourceKnownValues("transparentedge_certreq_dns_credential.route53", queryfilter.ByDisplayName(knownvalue.StringExact("route53")), []querycheck.KnownValueCheck{
+						{
+							Path:       tfjsonpath.New("parameters").AtMapKey("AWS_ACCESS_KEY_ID"),
+							KnownValue: knownvalue.StringExact("AKIAEXAMPLE"),
+						},
+					}),
+					querycheck.ExpectLength("transparentedge_certreq_dns_credential.cloudflare", 0),
+				},
+			},
+		},
+	})
+}
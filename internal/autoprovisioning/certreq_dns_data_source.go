@@ -41,6 +41,7 @@ func (*certReqDNSDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 For detailed documentation (not Terraform-specific), please refer to this [link](https://docs.transparentedge.eu/getting-started/dashboard/auto-provisioning/ssl).`,
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("certificate request"),
 			"id": schema.Int64Attribute{
 				Required:            true,
 				Description:         "ID of the DNS Certificate Request.",
@@ -87,7 +88,9 @@ func (d *certReqDNSDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	// Read Terraform configuration data into the model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
-	apiModel, err := d.client.GetCertReqDNS(int(data.ID.ValueInt64()))
+	ctx = helpers.CompanyContext(ctx, data.CompanyID)
+
+	apiModel, err := d.client.GetCertReqDNS(ctx, int(data.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failure retrieving DNS Certificate Request",
@@ -126,6 +129,7 @@ func (d *certReqDNSDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		data.StatusMessage = types.StringValue(helpers.ParseCertReqLogString(*apiModel.Log))
 	}
 
+	data.CompanyID = helpers.CompanyIDValue(ctx, d.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
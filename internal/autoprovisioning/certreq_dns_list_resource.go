@@ -0,0 +1,132 @@
+package autoprovisioning
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &certreqDNSListResource{}
+	_ list.ListResourceWithConfigure = &certreqDNSListResource{}
+)
+
+// NewCertReqDNSListResource is a helper function to simplify the provider implementation.
+func NewCertReqDNSListResource() list.ListResource {
+	return &certreqDNSListResource{}
+}
+
+// certreqDNSListResource lists the DNS certificate requests for 'terraform query'.
+type certreqDNSListResource struct {
+	client *teclient.Client
+}
+
+type certreqDNSListConfig struct {
+	CompanyID   types.Int64  `tfsdk:"company_id"`
+	DomainRegex types.String `tfsdk:"domain_regex"`
+	Credential  types.Int64  `tfsdk:"credential"`
+}
+
+// Metadata returns the type name of the listed resource.
+func (*certreqDNSListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certreq_dns"
+}
+
+// ListResourceConfigSchema defines the filters of the list block.
+func (*certreqDNSListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Lists the DNS certificate requests of the company.",
+		MarkdownDescription: "Lists the DNS certificate requests of the company.",
+		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDListAttribute("DNS certificate requests"),
+			"domain_regex": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only list the requests with a domain matching this regular expression.",
+				MarkdownDescription: "Only list the requests with a domain matching this regular expression.",
+			},
+			"credential": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "Only list the requests using the DNS credential with this ID.",
+				MarkdownDescription: "Only list the requests using the DNS credential with this ID.",
+			},
+		},
+	}
+}
+
+// List streams the DNS certificate requests matching the filters.
+func (r *certreqDNSListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config certreqDNSListConfig
+
+	diags := req.Config.Get(ctx, &config)
+
+	domainRegex := helpers.ListRegexp("domain_regex", config.DomainRegex, &diags)
+
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, config.CompanyID)
+
+	certReqs, err := r.client.GetCertReqsDNS(ctx)
+	if err != nil {
+		diags.AddError("Unable to list the DNS certificate requests", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	var matching []teclient.CertReqDNS
+
+	for _, certReq := range certReqs {
+		if domainRegex != nil && !slices.ContainsFunc(helpers.SplitAndSort(certReq.Domains), domainRegex.MatchString) {
+			continue
+		}
+
+		if !config.Credential.IsNull() && int64(certReq.Credential) != config.Credential.ValueInt64() {
+			continue
+		}
+
+		matching = append(matching, certReq)
+	}
+
+	companyID := helpers.CompanyIDValue(ctx, r.client)
+
+	stream.Results = helpers.ListResults(ctx, req, matching, func(certReq teclient.CertReqDNS) (helpers.ListItem, diag.Diagnostics) {
+		state := CertReqDNS{CompanyID: companyID}
+		diags := state.fromAPI(ctx, &certReq)
+
+		return helpers.ListItem{
+			DisplayName: strings.Join(helpers.SplitAndSort(certReq.Domains), ", "),
+			Identity:    ObjectIdentity{CompanyID: companyID, ID: state.ID},
+			Resource:    &state,
+		}, diags
+	})
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *certreqDNSListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*teclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to configure", "error while configuring API client")
+
+		return
+	}
+
+	r.client = client
+}
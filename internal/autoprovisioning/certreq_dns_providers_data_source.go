@@ -68,7 +68,7 @@ func (d *crDNSProviderDataSource) Read(ctx context.Context, req datasource.ReadR
 	// Read Terraform configuration data into the model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &providers)...)
 
-	respProviders, err := d.client.GetCRDNSProviders()
+	respProviders, err := d.client.GetCRDNSProviders(ctx)
 	if err != nil || respProviders == nil {
 		resp.Diagnostics.AddError(
 			"Unable to retrieve DNS Providers",
@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -35,8 +37,16 @@ var (
 	_ resource.Resource                = &certreqDNSResource{}
 	_ resource.ResourceWithConfigure   = &certreqDNSResource{}
 	_ resource.ResourceWithImportState = &certreqDNSResource{}
+	_ resource.ResourceWithModifyPlan  = &certreqDNSResource{}
+	_ resource.ResourceWithIdentity    = &certreqDNSResource{}
 )
 
+// certReqDNSAPIFields maps the API fields of a DNS Certificate Request to the resource attributes.
+var certReqDNSAPIFields = map[string]path.Path{
+	"domains":    path.Root("domains"),
+	"credential": path.Root("credential"),
+}
+
 func NewCertReqDNSResource() resource.Resource {
 	return &certreqDNSResource{}
 }
@@ -58,6 +68,7 @@ This resource enables the creation of certificate requests using various challen
 For detailed documentation (not Terraform-specific), please refer to this [link](https://docs.transparentedge.eu/getting-started/dashboard/auto-provisioning/ssl).`,
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDResourceAttribute("certificate request"),
 			"id": schema.Int64Attribute{
 				Computed:            true,
 				Description:         "ID of the DNS Certificate Request.",
@@ -119,6 +130,8 @@ func (r *certreqDNSResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	// Create the Certificate Request in the API
 	domains := make([]string, 0, len(plan.Domains.Elements()))
 
@@ -129,16 +142,14 @@ func (r *certreqDNSResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	apiModel, err := r.client.CreateDNSCertReq(map[string]any{
+	apiModel, err := r.client.CreateDNSCertReq(ctx, map[string]any{
 		"domains":               strings.Join(domains, "\n"),
 		"credential":            plan.Credential.ValueInt64(),
 		"certificate_authority": 1, // Let's Encrypt
 	})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating DNS Certificate Request",
-			err.Error(),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error creating DNS Certificate Request",
+			"Could not create the DNS Certificate Request", err, certReqDNSAPIFields)
 
 		return
 	}
@@ -153,7 +164,7 @@ func (r *certreqDNSResource) Create(ctx context.Context, req resource.CreateRequ
 			// An error happened
 
 			// Try to delete the current DNS CR
-			err := r.client.DeleteDNSCertReq(apiModel.ID)
+			err := r.client.DeleteDNSCertReq(ctx, apiModel.ID)
 			if err != nil {
 				tflog.Warn(ctx, fmt.Sprintf("Could not delete the DNS CR with id: %d\n%s", apiModel.ID, err.Error()))
 			}
@@ -198,7 +209,9 @@ func (r *certreqDNSResource) Create(ctx context.Context, req resource.CreateRequ
 		plan.StatusMessage = types.StringValue(helpers.ParseCertReqLogString(*apiModel.Log))
 	}
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: plan.CompanyID, ID: plan.ID})...)
 }
 
 func (r *certreqDNSResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -212,19 +225,19 @@ func (r *certreqDNSResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	// Only the credential can be updated. Modifying the domains requires replace.
-	err := r.client.UpdateDNSCertReq(int(plan.ID.ValueInt64()), int(plan.Credential.ValueInt64()))
+	err := r.client.UpdateDNSCertReq(ctx, int(plan.ID.ValueInt64()), int(plan.Credential.ValueInt64()))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating DNS Certificate Request",
-			err.Error(),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error updating DNS Certificate Request",
+			"Could not update the DNS Certificate Request with id: "+plan.ID.String(), err, certReqDNSAPIFields)
 
 		return
 	}
 
 	// Get current
-	apiModel, err := r.client.GetCertReqDNS(int(plan.ID.ValueInt64()))
+	apiModel, err := r.client.GetCertReqDNS(ctx, int(plan.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failure retrieving DNS Certificate Request",
@@ -265,7 +278,9 @@ func (r *certreqDNSResource) Update(ctx context.Context, req resource.UpdateRequ
 		plan.StatusMessage = types.StringValue(helpers.ParseCertReqLogString(*apiModel.Log))
 	}
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: plan.CompanyID, ID: plan.ID})...)
 }
 
 func (r *certreqDNSResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -279,8 +294,17 @@ func (r *certreqDNSResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	// Get current
-	apiModel, err := r.client.GetCertReqDNS(int(state.ID.ValueInt64()))
+	apiModel, err := r.client.GetCertReqDNS(ctx, int(state.ID.ValueInt64()))
+	if teclient.IsNotFound(err) {
+		tflog.Warn(ctx, "DNS Certificate Request "+state.ID.String()+" no longer exists, removing it from the state")
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failure retrieving DNS Certificate Request",
@@ -290,36 +314,45 @@ func (r *certreqDNSResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	// Generate the list of domains
-	sortedDomains := helpers.SplitAndSort(apiModel.Domains)
-	domains, diags := types.SetValueFrom(ctx, types.StringType, sortedDomains)
-
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(state.fromAPI(ctx, &apiModel)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Set state
-	state.ID = types.Int64Value(int64(apiModel.ID))
-	state.Domains = domains
-	state.Credential = types.Int64Value(int64(apiModel.Credential))
-	state.CreatedAt = types.StringValue(apiModel.CreatedAt)
-	state.UpdatedAt = types.StringValue(apiModel.UpdatedAt)
+	state.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: state.CompanyID, ID: state.ID})...)
+}
 
-	if apiModel.CertificateID == nil {
-		state.CertificateID = types.Int64Null()
+// fromAPI sets the attributes read from the API, for Read and the list resource.
+func (c *CertReqDNS) fromAPI(ctx context.Context, certReq *teclient.CertReqDNS) diag.Diagnostics {
+	// Generate the list of domains
+	sortedDomains := helpers.SplitAndSort(certReq.Domains)
+	domains, diags := types.SetValueFrom(ctx, types.StringType, sortedDomains)
+	if diags.HasError() {
+		return diags
+	}
+
+	c.ID = types.Int64Value(int64(certReq.ID))
+	c.Domains = domains
+	c.Credential = types.Int64Value(int64(certReq.Credential))
+	c.CreatedAt = types.StringValue(certReq.CreatedAt)
+	c.UpdatedAt = types.StringValue(certReq.UpdatedAt)
+
+	if certReq.CertificateID == nil {
+		c.CertificateID = types.Int64Null()
 	} else {
-		state.CertificateID = types.Int64Value(int64(*apiModel.CertificateID))
+		c.CertificateID = types.Int64Value(int64(*certReq.CertificateID))
 	}
 
-	if apiModel.Log == nil {
-		state.StatusMessage = types.StringNull()
+	if certReq.Log == nil {
+		c.StatusMessage = types.StringNull()
 	} else {
-		state.StatusMessage = types.StringValue(helpers.ParseCertReqLogString(*apiModel.Log))
+		c.StatusMessage = types.StringValue(helpers.ParseCertReqLogString(*certReq.Log))
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	return diags
 }
 
 func (r *certreqDNSResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -333,8 +366,10 @@ func (r *certreqDNSResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	// Delete the resource
-	err := r.client.DeleteDNSCertReq(int(state.ID.ValueInt64()))
+	err := r.client.DeleteDNSCertReq(ctx, int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting the DNS Certificate Request",
@@ -345,6 +380,11 @@ func (r *certreqDNSResource) Delete(ctx context.Context, req resource.DeleteRequ
 	}
 }
 
+// ModifyPlan refuses any change in read-only mode.
+func (r *certreqDNSResource) ModifyPlan(_ context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	helpers.CheckReadOnlyPlan(r.client, "DNS certificate request", req, resp)
+}
+
 func (r *certreqDNSResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -360,8 +400,33 @@ func (r *certreqDNSResource) Configure(_ context.Context, req resource.Configure
 	r.client = client
 }
 
+// IdentitySchema defines the identity of the resource, to import it with an identity instead of an ID.
+func (*certreqDNSResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"company_id": helpers.CompanyIDIdentityAttribute(),
+			"id": identityschema.Int64Attribute{
+				RequiredForImport: true,
+				Description:       "ID of the DNS certificate request.",
+			},
+		},
+	}
+}
+
 func (*certreqDNSResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.Atoi(req.ID)
+	// Imported with an identity instead of an ID
+	if req.ID == "" {
+		helpers.ImportFromIdentity(ctx, req, resp)
+
+		return
+	}
+
+	importID, ok := helpers.ImportCompanyID(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(importID)
 	if err != nil {
 		resp.Diagnostics.AddError("Invalid identifier", "ID must be a valid number.")
 
@@ -378,23 +443,26 @@ func (*certreqDNSResource) ImportState(ctx context.Context, req resource.ImportS
 }
 
 func (r *certreqDNSResource) WaitCompleteDNSCertReq(ctx context.Context, certreq *teclient.CertReqDNS) *teclient.CertReqDNS {
-	// Read the state of the DNS Certificate Request until it's processed or timeout
-	remainingTime := certreqDNSCreateTimeout.Seconds()
+	// Read the state of the DNS Certificate Request until it's processed, timeout or cancellation
+	deadline := time.Now().Add(certreqDNSCreateTimeout)
 
 	for {
-		apiModel, err := r.client.GetCertReqDNS(certreq.ID)
+		apiModel, err := r.client.GetCertReqDNS(ctx, certreq.ID)
 		if err == nil {
 			if apiModel.CertificateID != nil || apiModel.Log != nil {
 				return &apiModel
 			}
 		}
 
-		if remainingTime <= 0 {
+		if time.Until(deadline) <= 0 {
 			break
 		}
 
-		time.Sleep(certreqDNSCreateRetry)
-		remainingTime -= (certreqDNSCreateRetry.Seconds() + 1)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(certreqDNSCreateRetry):
+		}
 
 		tflog.Info(ctx, fmt.Sprintf("Waiting for the DNS Certificate Request %d to be completed.", certreq.ID))
 	}
@@ -0,0 +1,91 @@
+package autoprovisioning_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+)
+
+func testAccCertReqDNSConfig(domains string) string {
+	return testAccDNSCredentialConfig("route53", "secret1") + `
+resource "transparentedge_certreq_dns" "test" {
+  credential = transparentedge_certreq_dns_credential.test.id
+  domains    = [` + domains + `]
+}
+`
+}
+
+func TestAccCertReqDNSResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCertReqDNSConfig(`"example.com", "*.example.com"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_certreq_dns.test", "id"),
+					resource.TestCheckResourceAttrSet("transparentedge_certreq_dns.test", "certificate_id"),
+					resource.TestCheckResourceAttrPair("transparentedge_certreq_dns.test", "credential", "transparentedge_certreq_dns_credential.test", "id"),
+					resource.TestCheckResourceAttr("transparentedge_certreq_dns.test", "domains.#", "2"),
+					resource.TestCheckTypeSetElemAttr("transparentedge_certreq_dns.test", "domains.*", "*.example.com"),
+				),
+			},
+			{
+				ResourceName:      "transparentedge_certreq_dns.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccCertReqDNSConfig(`"example.com"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_certreq_dns.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("transparentedge_certreq_dns.test", "domains.#", "1"),
+			},
+		},
+	})
+}
+
+func TestAccCertReqDNSResource_failure(t *testing.T) {
+	srv := acctest.NewServer(t)
+	srv.FailCertificateRequests("broken.example.com", `{"status": "DNS challenge failed", "en": "TXT record not found"}`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCertReqDNSConfig(`"broken.example.com"`),
+				ExpectError: regexp.MustCompile(`DNS challenge failed: TXT record not found`),
+			},
+		},
+	})
+}
+
+func TestAccCertReqDNSDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCertReqDNSConfig(`"example.com"`) + `
+data "transparentedge_certreq_dns" "test" {
+  id = transparentedge_certreq_dns.test.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.transparentedge_certreq_dns.test", "certificate_id", "transparentedge_certreq_dns.test", "certificate_id"),
+					resource.TestCheckResourceAttrPair("data.transparentedge_certreq_dns.test", "credential", "transparentedge_certreq_dns.test", "credential"),
+					resource.TestCheckTypeSetElemAttr("data.transparentedge_certreq_dns.test", "domains.*", "example.com"),
+				),
+			},
+		},
+	})
+}
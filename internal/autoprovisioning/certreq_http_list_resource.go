@@ -0,0 +1,120 @@
+package autoprovisioning
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &certreqHTTPListResource{}
+	_ list.ListResourceWithConfigure = &certreqHTTPListResource{}
+)
+
+// NewCertReqHTTPListResource is a helper function to simplify the provider implementation.
+func NewCertReqHTTPListResource() list.ListResource {
+	return &certreqHTTPListResource{}
+}
+
+// certreqHTTPListResource lists the HTTP certificate requests for 'terraform query'.
+type certreqHTTPListResource struct {
+	client *teclient.Client
+}
+
+type certreqHTTPListConfig struct {
+	CompanyID   types.Int64  `tfsdk:"company_id"`
+	DomainRegex types.String `tfsdk:"domain_regex"`
+}
+
+// Metadata returns the type name of the listed resource.
+func (*certreqHTTPListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_certreq_http"
+}
+
+// ListResourceConfigSchema defines the filters of the list block.
+func (*certreqHTTPListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Lists the HTTP certificate requests of the company.",
+		MarkdownDescription: "Lists the HTTP certificate requests of the company.",
+		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDListAttribute("HTTP certificate requests"),
+			"domain_regex": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only list the requests with a domain matching this regular expression.",
+				MarkdownDescription: "Only list the requests with a domain matching this regular expression.",
+			},
+		},
+	}
+}
+
+// List streams the HTTP certificate requests matching the filters.
+func (r *certreqHTTPListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config certreqHTTPListConfig
+
+	diags := req.Config.Get(ctx, &config)
+
+	domainRegex := helpers.ListRegexp("domain_regex", config.DomainRegex, &diags)
+
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, config.CompanyID)
+
+	certReqs, err := r.client.GetCertReqsHTTP(ctx)
+	if err != nil {
+		diags.AddError("Unable to list the HTTP certificate requests", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	var matching []teclient.CertReqHTTP
+
+	for _, certReq := range certReqs {
+		if domainRegex == nil || slices.ContainsFunc(helpers.SplitAndSort(certReq.CommonName+"\n"+certReq.SAN), domainRegex.MatchString) {
+			matching = append(matching, certReq)
+		}
+	}
+
+	companyID := helpers.CompanyIDValue(ctx, r.client)
+
+	stream.Results = helpers.ListResults(ctx, req, matching, func(certReq teclient.CertReqHTTP) (helpers.ListItem, diag.Diagnostics) {
+		state := CertReqHTTP{CompanyID: companyID}
+		diags := state.fromAPI(ctx, &certReq)
+
+		return helpers.ListItem{
+			DisplayName: strings.Join(helpers.SplitAndSort(certReq.CommonName+"\n"+certReq.SAN), ", "),
+			Identity:    ObjectIdentity{CompanyID: companyID, ID: state.ID},
+			Resource:    &state,
+		}, diags
+	})
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *certreqHTTPListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*teclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to configure", "error while configuring API client")
+
+		return
+	}
+
+	r.client = client
+}
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
@@ -36,8 +38,15 @@ var (
 	_ resource.ResourceWithConfigure   = &certreqHTTPResource{}
 	_ resource.ResourceWithImportState = &certreqHTTPResource{}
 	_ resource.ResourceWithModifyPlan  = &certreqHTTPResource{}
+	_ resource.ResourceWithIdentity    = &certreqHTTPResource{}
 )
 
+// certReqHTTPAPIFields maps the API fields of an HTTP Certificate Request to the resource attributes.
+var certReqHTTPAPIFields = map[string]path.Path{
+	"domains":    path.Root("domains"),
+	"standalone": path.Root("standalone"),
+}
+
 func NewCertReqHTTPResource() resource.Resource {
 	return &certreqHTTPResource{}
 }
@@ -56,6 +65,7 @@ This resource enables the creation of certificate requests using the HTTP challe
 For detailed documentation (not Terraform-specific), please refer to this [link](https://docs.transparentedge.eu/getting-started/dashboard/auto-provisioning/ssl).`,
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDResourceAttribute("certificate request"),
 			"id": schema.Int64Attribute{
 				Computed:            true,
 				Description:         "ID of the HTTP Certificate Request.",
@@ -121,6 +131,8 @@ func (r *certreqHTTPResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	// Create the Certificate Request in the API
 	domains := make([]string, 0, len(plan.Domains.Elements()))
 	diags = plan.Domains.ElementsAs(ctx, &domains, false)
@@ -133,15 +145,13 @@ func (r *certreqHTTPResource) Create(ctx context.Context, req resource.CreateReq
 
 	standalone := !plan.Standalone.IsNull() && plan.Standalone.ValueBool()
 
-	apiModel, err := r.client.CreateHTTPCertReq(map[string]any{
+	apiModel, err := r.client.CreateHTTPCertReq(ctx, map[string]any{
 		"domains":    domains,
 		"standalone": standalone,
 	})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating HTTP Certificate Request",
-			err.Error(),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error creating HTTP Certificate Request",
+			"Could not create the HTTP Certificate Request", err, certReqHTTPAPIFields)
 
 		return
 	}
@@ -194,7 +204,9 @@ func (r *certreqHTTPResource) Create(ctx context.Context, req resource.CreateReq
 		plan.StatusMessage = types.StringValue(helpers.ParseCertReqLogString(*apiModel.Log))
 	}
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: plan.CompanyID, ID: plan.ID})...)
 }
 
 func (r *certreqHTTPResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -208,8 +220,17 @@ func (r *certreqHTTPResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	// Get current
-	apiModel, err := r.client.GetCertReqHTTP(int(state.ID.ValueInt64()))
+	apiModel, err := r.client.GetCertReqHTTP(ctx, int(state.ID.ValueInt64()))
+	if teclient.IsNotFound(err) {
+		tflog.Warn(ctx, "HTTP Certificate Request "+state.ID.String()+" no longer exists, removing it from the state")
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failure retrieving HTTP Certificate Request",
@@ -219,36 +240,45 @@ func (r *certreqHTTPResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
-	// Generate the list of domains
-	sortedDomains := helpers.SplitAndSort(apiModel.CommonName + "\n" + apiModel.SAN)
-	domains, diags := types.SetValueFrom(ctx, types.StringType, sortedDomains)
-
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(state.fromAPI(ctx, &apiModel)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Set state
-	state.ID = types.Int64Value(int64(apiModel.ID))
-	state.Domains = domains
-	state.Standalone = types.BoolValue(apiModel.Standalone)
-	state.CreatedAt = types.StringValue(apiModel.CreatedAt)
-	state.UpdatedAt = types.StringValue(apiModel.UpdatedAt)
+	state.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, ObjectIdentity{CompanyID: state.CompanyID, ID: state.ID})...)
+}
 
-	if apiModel.CertificateID == nil {
-		state.CertificateID = types.Int64Null()
+// fromAPI sets the attributes read from the API, for Read and the list resource.
+func (c *CertReqHTTP) fromAPI(ctx context.Context, certReq *teclient.CertReqHTTP) diag.Diagnostics {
+	// Generate the list of domains
+	sortedDomains := helpers.SplitAndSort(certReq.CommonName + "\n" + certReq.SAN)
+	domains, diags := types.SetValueFrom(ctx, types.StringType, sortedDomains)
+	if diags.HasError() {
+		return diags
+	}
+
+	c.ID = types.Int64Value(int64(certReq.ID))
+	c.Domains = domains
+	c.Standalone = types.BoolValue(certReq.Standalone)
+	c.CreatedAt = types.StringValue(certReq.CreatedAt)
+	c.UpdatedAt = types.StringValue(certReq.UpdatedAt)
+
+	if certReq.CertificateID == nil {
+		c.CertificateID = types.Int64Null()
 	} else {
-		state.CertificateID = types.Int64Value(int64(*apiModel.CertificateID))
+		c.CertificateID = types.Int64Value(int64(*certReq.CertificateID))
 	}
 
-	if apiModel.Log == nil {
-		state.StatusMessage = types.StringNull()
+	if certReq.Log == nil {
+		c.StatusMessage = types.StringNull()
 	} else {
-		state.StatusMessage = types.StringValue(helpers.ParseCertReqLogString(*apiModel.Log))
+		c.StatusMessage = types.StringValue(helpers.ParseCertReqLogString(*certReq.Log))
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	return diags
 }
 
 func (*certreqHTTPResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
@@ -257,7 +287,13 @@ func (*certreqHTTPResource) Update(_ context.Context, _ resource.UpdateRequest,
 func (*certreqHTTPResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
 }
 
-func (*certreqHTTPResource) ModifyPlan(_ context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+func (r *certreqHTTPResource) ModifyPlan(_ context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	helpers.CheckReadOnlyPlan(r.client, "HTTP certificate request", req, resp)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// If the entire plan is null, the resource is planned for destruction.
 	if req.Plan.Raw.IsNull() {
 		resp.Diagnostics.AddWarning(
@@ -283,8 +319,33 @@ func (r *certreqHTTPResource) Configure(_ context.Context, req resource.Configur
 	r.client = client
 }
 
+// IdentitySchema defines the identity of the resource, to import it with an identity instead of an ID.
+func (*certreqHTTPResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"company_id": helpers.CompanyIDIdentityAttribute(),
+			"id": identityschema.Int64Attribute{
+				RequiredForImport: true,
+				Description:       "ID of the HTTP certificate request.",
+			},
+		},
+	}
+}
+
 func (*certreqHTTPResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	id, err := strconv.Atoi(req.ID)
+	// Imported with an identity instead of an ID
+	if req.ID == "" {
+		helpers.ImportFromIdentity(ctx, req, resp)
+
+		return
+	}
+
+	importID, ok := helpers.ImportCompanyID(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(importID)
 	if err != nil {
 		resp.Diagnostics.AddError("Invalid identifier", "ID must be a valid number.")
 
@@ -301,23 +362,26 @@ func (*certreqHTTPResource) ImportState(ctx context.Context, req resource.Import
 }
 
 func (r *certreqHTTPResource) WaitCompleteHTTPCertReq(ctx context.Context, certreq *teclient.CertReqHTTP) *teclient.CertReqHTTP {
-	// Read the state of the HTTP Certificate Request until it's processed or timeout
-	remainingTime := certreqHTTPCreateTimeout.Seconds()
+	// Read the state of the HTTP Certificate Request until it's processed, timeout or cancellation
+	deadline := time.Now().Add(certreqHTTPCreateTimeout)
 
 	for {
-		apiModel, err := r.client.GetCertReqHTTP(certreq.ID)
+		apiModel, err := r.client.GetCertReqHTTP(ctx, certreq.ID)
 		if err == nil {
 			if apiModel.CertificateID != nil || (apiModel.Log != nil && *apiModel.Log != "") {
 				return &apiModel
 			}
 		}
 
-		if remainingTime <= 0 {
+		if time.Until(deadline) <= 0 {
 			break
 		}
 
-		time.Sleep(certreqHTTPCreateRetry)
-		remainingTime -= (certreqHTTPCreateRetry.Seconds() + 1)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(certreqHTTPCreateRetry):
+		}
 
 		tflog.Info(ctx, fmt.Sprintf("Waiting for the HTTP Certificate Request %d to be completed.", certreq.ID))
 	}
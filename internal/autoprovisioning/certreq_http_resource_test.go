@@ -0,0 +1,86 @@
+package autoprovisioning_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+)
+
+func testAccCertReqHTTPConfig(domains string) string {
+	return `
+resource "transparentedge_certreq_http" "test" {
+  domains = [` + domains + `]
+}
+`
+}
+
+func TestAccCertReqHTTPResource(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCertReqHTTPConfig(`"www.example.com", "example.com"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_certreq_http.test", "id"),
+					resource.TestCheckResourceAttrSet("transparentedge_certreq_http.test", "certificate_id"),
+					resource.TestCheckResourceAttr("transparentedge_certreq_http.test", "standalone", "false"),
+					resource.TestCheckResourceAttr("transparentedge_certreq_http.test", "domains.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "transparentedge_certreq_http.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccCertReqHTTPConfig(`"www.example.com", "example.com"`),
+				Check:              removeFromAPI(srv, "transparentedge_certreq_http.test"),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccCertReqHTTPConfig(`"www.example.com", "example.com"`),
+				Check:  resource.TestCheckResourceAttrSet("transparentedge_certreq_http.test", "id"),
+			},
+		},
+	})
+}
+
+func TestAccCertReqHTTPResource_wildcard(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCertReqHTTPConfig(`"*.example.com"`),
+				ExpectError: regexp.MustCompile(`Wildcard domains require a DNS`),
+			},
+		},
+	})
+}
+
+func TestAccCertReqHTTPDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCertReqHTTPConfig(`"www.example.com"`) + `
+data "transparentedge_certreq_http" "test" {
+  id = transparentedge_certreq_http.test.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.transparentedge_certreq_http.test", "certificate_id", "transparentedge_certreq_http.test", "certificate_id"),
+					resource.TestCheckTypeSetElemAttr("data.transparentedge_certreq_http.test", "domains.*", "www.example.com"),
+				),
+			},
+		},
+	})
+}
@@ -12,10 +12,11 @@ import (
 const apiEnv = teclient.ProdEnv
 
 type Site struct {
-	Timeouts timeouts.Value `tfsdk:"timeouts"`
-	ID       types.Int64    `tfsdk:"id"`
-	Domain   types.String   `tfsdk:"domain"`
-	Active   types.Bool     `tfsdk:"active"`
+	CompanyID types.Int64    `tfsdk:"company_id"`
+	Timeouts  timeouts.Value `tfsdk:"timeouts"`
+	ID        types.Int64    `tfsdk:"id"`
+	Domain    types.String   `tfsdk:"domain"`
+	Active    types.Bool     `tfsdk:"active"`
 }
 
 type SiteDataSourceModel struct {
@@ -27,15 +28,18 @@ type SiteDataSourceModel struct {
 }
 
 type Sites struct {
-	Sites []SiteDataSourceModel `tfsdk:"sites"`
+	CompanyID types.Int64           `tfsdk:"company_id"`
+	Sites     []SiteDataSourceModel `tfsdk:"sites"`
 }
 
 type SiteVerify struct {
+	CompanyID           types.Int64  `tfsdk:"company_id"`
 	Domain              types.String `tfsdk:"domain"`
 	VerificantionString types.String `tfsdk:"verification_string"`
 }
 
-type Backend struct {
+// BackendAttributes are the attributes of a backend, shared by the backends listing.
+type BackendAttributes struct {
 	ID           types.Int64  `tfsdk:"id"`
 	Company      types.Int64  `tfsdk:"company"`
 	Name         types.String `tfsdk:"name"`
@@ -51,11 +55,35 @@ type Backend struct {
 	HCDisabled   types.Bool   `tfsdk:"hcdisabled"`
 }
 
+type Backend struct {
+	BackendAttributes
+
+	CompanyID types.Int64 `tfsdk:"company_id"`
+}
+
 type Backends struct {
-	Backends []Backend `tfsdk:"backends"`
+	CompanyID types.Int64         `tfsdk:"company_id"`
+	Backends  []BackendAttributes `tfsdk:"backends"`
 }
 
 type VCLConf struct {
+	CompanyID          types.Int64              `tfsdk:"company_id"`
+	ID                 types.Int64              `tfsdk:"id"`
+	Company            types.Int64              `tfsdk:"company"`
+	VCLCode            customtypes.VCLCodeValue `tfsdk:"vclcode"`
+	UploadDate         types.String             `tfsdk:"uploaddate"`
+	ProductionDate     types.String             `tfsdk:"productiondate"`
+	User               types.String             `tfsdk:"user"`
+	Comment            types.String             `tfsdk:"comment"`
+	ReferencedBackends types.Set                `tfsdk:"referenced_backends"`
+	DiffContextLines   types.Int64              `tfsdk:"diff_context_lines"`
+	Snippets           types.List               `tfsdk:"snippets"`
+	Timeouts           timeouts.Value           `tfsdk:"timeouts"`
+}
+
+// ActiveVCLConf is the VCLConf without timeouts, for the data source.
+type ActiveVCLConf struct {
+	CompanyID      types.Int64              `tfsdk:"company_id"`
 	ID             types.Int64              `tfsdk:"id"`
 	Company        types.Int64              `tfsdk:"company"`
 	VCLCode        customtypes.VCLCodeValue `tfsdk:"vclcode"`
@@ -63,10 +91,10 @@ type VCLConf struct {
 	ProductionDate types.String             `tfsdk:"productiondate"`
 	User           types.String             `tfsdk:"user"`
 	Comment        types.String             `tfsdk:"comment"`
-	Timeouts       timeouts.Value           `tfsdk:"timeouts"`
 }
 
 type Certificates struct {
+	CompanyID    types.Int64   `tfsdk:"company_id"`
 	Certificates []Certificate `tfsdk:"certificates"`
 }
 
@@ -84,6 +112,7 @@ type Certificate struct {
 }
 
 type CustomCertificate struct {
+	CompanyID  types.Int64  `tfsdk:"company_id"`
 	ID         types.Int64  `tfsdk:"id"`
 	CommonName types.String `tfsdk:"commonname"`
 	Domains    types.String `tfsdk:"domains"`
@@ -103,10 +132,12 @@ type CertReqDNSProviders struct {
 }
 
 type CertReqCNAMEVerify struct {
-	CNAME types.String `tfsdk:"cname"`
+	CompanyID types.Int64  `tfsdk:"company_id"`
+	CNAME     types.String `tfsdk:"cname"`
 }
 
 type CertReqDNSCredential struct {
+	CompanyID   types.Int64  `tfsdk:"company_id"`
 	ID          types.Int64  `tfsdk:"id"`
 	Alias       types.String `tfsdk:"alias"`
 	DNSProvider types.String `tfsdk:"dns_provider"`
@@ -114,6 +145,7 @@ type CertReqDNSCredential struct {
 }
 
 type CertReqDNS struct {
+	CompanyID     types.Int64  `tfsdk:"company_id"`
 	ID            types.Int64  `tfsdk:"id"`
 	Domains       types.Set    `tfsdk:"domains"`
 	Credential    types.Int64  `tfsdk:"credential"`
@@ -125,6 +157,7 @@ type CertReqDNS struct {
 
 // CertReqHTTP for HTTP Certificate requests.
 type CertReqHTTP struct {
+	CompanyID     types.Int64  `tfsdk:"company_id"`
 	ID            types.Int64  `tfsdk:"id"`
 	Domains       types.Set    `tfsdk:"domains"`
 	Standalone    types.Bool   `tfsdk:"standalone"`
@@ -133,3 +166,26 @@ type CertReqHTTP struct {
 	UpdatedAt     types.String `tfsdk:"updated_at"`
 	StatusMessage types.String `tfsdk:"status_message"`
 }
+
+// BackendIdentity identifies a backend by its company and name.
+type BackendIdentity struct {
+	CompanyID types.Int64  `tfsdk:"company_id"`
+	Name      types.String `tfsdk:"name"`
+}
+
+// SiteIdentity identifies a site by its company and domain.
+type SiteIdentity struct {
+	CompanyID types.Int64  `tfsdk:"company_id"`
+	Domain    types.String `tfsdk:"domain"`
+}
+
+// ObjectIdentity identifies the objects known by a numeric ID: certificates, DNS credentials and certificate requests.
+type ObjectIdentity struct {
+	CompanyID types.Int64 `tfsdk:"company_id"`
+	ID        types.Int64 `tfsdk:"id"`
+}
+
+// VCLConfIdentity identifies the active VCL configuration, a single one per company.
+type VCLConfIdentity struct {
+	CompanyID types.Int64 `tfsdk:"company_id"`
+}
@@ -0,0 +1,126 @@
+package autoprovisioning
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ list.ListResource              = &siteListResource{}
+	_ list.ListResourceWithConfigure = &siteListResource{}
+)
+
+// NewSiteListResource is a helper function to simplify the provider implementation.
+func NewSiteListResource() list.ListResource {
+	return &siteListResource{}
+}
+
+// siteListResource lists the sites for 'terraform query'.
+type siteListResource struct {
+	client *teclient.Client
+}
+
+type siteListConfig struct {
+	CompanyID   types.Int64  `tfsdk:"company_id"`
+	DomainRegex types.String `tfsdk:"domain_regex"`
+}
+
+// Metadata returns the type name of the listed resource.
+func (*siteListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_site"
+}
+
+// ListResourceConfigSchema defines the filters of the list block.
+func (*siteListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Lists the sites of the company.",
+		MarkdownDescription: "Lists the sites of the company.",
+		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDListAttribute("sites"),
+			"domain_regex": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only list the sites with a domain matching this regular expression.",
+				MarkdownDescription: "Only list the sites with a domain matching this regular expression.",
+			},
+		},
+	}
+}
+
+// List streams the sites matching the filters.
+func (r *siteListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config siteListConfig
+
+	diags := req.Config.Get(ctx, &config)
+
+	domainRegex := helpers.ListRegexp("domain_regex", config.DomainRegex, &diags)
+
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, config.CompanyID)
+
+	sites, err := r.client.GetSites(ctx)
+	if err != nil {
+		diags.AddError("Unable to list the sites", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+
+		return
+	}
+
+	var matching []teclient.SiteAPIModel
+
+	// Inactive sites are not served by the CDN, like the resource they are left out
+	for _, site := range sites {
+		if site.Active && (domainRegex == nil || domainRegex.MatchString(site.URL)) {
+			matching = append(matching, site)
+		}
+	}
+
+	companyID := helpers.CompanyIDValue(ctx, r.client)
+
+	stream.Results = helpers.ListResults(ctx, req, matching, func(site teclient.SiteAPIModel) (helpers.ListItem, diag.Diagnostics) {
+		state := Site{
+			CompanyID: companyID,
+			Timeouts: timeouts.Value{
+				Object: types.ObjectNull(map[string]attr.Type{"create": types.StringType}),
+			},
+		}
+		state.fromAPI(&site)
+
+		return helpers.ListItem{
+			DisplayName: site.URL,
+			Identity:    SiteIdentity{CompanyID: companyID, Domain: state.Domain},
+			Resource:    &state,
+		}, nil
+	})
+}
+
+// Configure adds the provider configured client to the list resource.
+func (r *siteListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*teclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to configure", "error while configuring API client")
+
+		return
+	}
+
+	r.client = client
+}
@@ -4,18 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -29,8 +30,15 @@ var (
 	_ resource.Resource                = &siteResource{}
 	_ resource.ResourceWithConfigure   = &siteResource{}
 	_ resource.ResourceWithImportState = &siteResource{}
+	_ resource.ResourceWithModifyPlan  = &siteResource{}
+	_ resource.ResourceWithIdentity    = &siteResource{}
 )
 
+// siteAPIFields maps the API fields of a site to the resource attributes.
+var siteAPIFields = map[string]path.Path{
+	"url": path.Root("domain"),
+}
+
 // NewSiteResource is a helper function to simplify the provider implementation.
 func NewSiteResource() resource.Resource {
 	return &siteResource{}
@@ -53,6 +61,7 @@ func (*siteResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp
 		MarkdownDescription: "Provides Site (domain) resource. This allows to create and delete domains.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDResourceAttribute("site"),
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create:            true,
 				CreateDescription: "Site validation retries continue until the create timeout period ends. The value of create must consist of numbers and unit suffixes, such as '30s' or '2h45m'. Valid time units are 's' (seconds), 'm' (minutes), 'h' (hours).",
@@ -91,6 +100,8 @@ func (r *siteResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	maxTimeout, err := plan.Timeouts.Create(ctx, defaultCreateTimeout)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -105,10 +116,8 @@ func (r *siteResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	siteState, errCreate := r.HelperCreateSite(ctx, plan.Domain.ValueString(), maxTimeout)
 	if errCreate != nil {
-		resp.Diagnostics.AddError(
-			"Error creating site",
-			fmt.Sprintf("Could not create the site '%s': %s", plan.Domain.ValueString(), errCreate),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error creating site",
+			fmt.Sprintf("Could not create the site '%s'", plan.Domain.ValueString()), errCreate, siteAPIFields)
 
 		return
 	}
@@ -117,7 +126,9 @@ func (r *siteResource) Create(ctx context.Context, req resource.CreateRequest, r
 	plan.ID = siteState.ID
 	plan.Domain = siteState.Domain
 	plan.Active = siteState.Active
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, SiteIdentity{CompanyID: plan.CompanyID, Domain: plan.Domain})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -131,15 +142,19 @@ func (r *siteResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	// Try to find by ID
 	if !plan.ID.IsNull() {
-		siteAPI, err := r.client.GetSite(int(plan.ID.ValueInt64()))
+		siteAPI, err := r.client.GetSite(ctx, int(plan.ID.ValueInt64()))
 		if err == nil {
 			if siteAPI.Active {
 				plan.ID = types.Int64Value(int64(siteAPI.ID))
 				plan.Domain = types.StringValue(siteAPI.URL)
 				plan.Active = types.BoolValue(siteAPI.Active)
+				plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 				resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+				resp.Diagnostics.Append(resp.Identity.Set(ctx, SiteIdentity{CompanyID: plan.CompanyID, Domain: plan.Domain})...)
 
 				return
 			}
@@ -147,14 +162,16 @@ func (r *siteResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	}
 
 	// Try to find by Domain
-	sites, err := r.client.GetSites()
+	sites, err := r.client.GetSites(ctx)
 	if err == nil {
 		for _, siteAPI := range sites {
 			if siteAPI.URL == plan.Domain.ValueString() && siteAPI.Active {
 				plan.ID = types.Int64Value(int64(siteAPI.ID))
 				plan.Domain = types.StringValue(siteAPI.URL)
 				plan.Active = types.BoolValue(siteAPI.Active)
+				plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 				resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+				resp.Diagnostics.Append(resp.Identity.Set(ctx, SiteIdentity{CompanyID: plan.CompanyID, Domain: plan.Domain})...)
 
 				return
 			}
@@ -176,42 +193,65 @@ func (r *siteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	var siteAPI *teclient.SiteAPIModel
+
 	// Try to find by ID
 	if !state.ID.IsNull() {
-		siteAPI, err := r.client.GetSite(int(state.ID.ValueInt64()))
-		if err == nil {
-			if siteAPI.Active {
-				state.ID = types.Int64Value(int64(siteAPI.ID))
-				state.Domain = types.StringValue(siteAPI.URL)
-				state.Active = types.BoolValue(siteAPI.Active)
-				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-
-				return
-			}
+		site, err := r.client.GetSite(ctx, int(state.ID.ValueInt64()))
+		if err != nil && !teclient.IsNotFound(err) {
+			resp.Diagnostics.AddError(
+				"Error reading site",
+				fmt.Sprintf("Could not read the site '%s': %s", state.Domain.ValueString(), err),
+			)
+
+			return
 		}
+
+		siteAPI = site
 	}
 
-	// Try to find by Domain
-	sites, err := r.client.GetSites()
-	if err == nil {
-		for _, siteAPI := range sites {
-			if siteAPI.URL == state.Domain.ValueString() && siteAPI.Active {
-				state.ID = types.Int64Value(int64(siteAPI.ID))
-				state.Domain = types.StringValue(siteAPI.URL)
-				state.Active = types.BoolValue(siteAPI.Active)
-				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	// Try to find by Domain, imports only know the domain,
+	// and a site disabled and added again has a new ID
+	if siteAPI == nil || !siteAPI.Active {
+		sites, err := r.client.GetSites(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading site",
+				fmt.Sprintf("Could not read the site '%s': %s", state.Domain.ValueString(), err),
+			)
+
+			return
+		}
 
-				return
+		for _, site := range sites {
+			// The active site is preferred over the disabled ones of the same domain
+			if site.URL == state.Domain.ValueString() && (siteAPI == nil || !siteAPI.Active) {
+				siteAPI = &site
 			}
 		}
 	}
 
-	// Not found or inactive
-	if !strings.Contains(state.Domain.ValueString(), "<inactive>") {
-		state.Domain = types.StringValue(state.Domain.ValueString() + " <inactive>")
+	// Not found or inactive, disabled sites are not served by the CDN
+	if siteAPI == nil || !siteAPI.Active {
+		tflog.Warn(ctx, "Site '"+state.Domain.ValueString()+"' no longer exists or is inactive, removing it from the state")
+		resp.State.RemoveResource(ctx)
+
+		return
 	}
 
+	state.fromAPI(siteAPI)
+	state.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, SiteIdentity{CompanyID: state.CompanyID, Domain: state.Domain})...)
+}
+
+// fromAPI sets the attributes read from the API, for Read and the list resource.
+func (s *Site) fromAPI(site *teclient.SiteAPIModel) {
+	s.ID = types.Int64Value(int64(site.ID))
+	s.Domain = types.StringValue(site.URL)
+	s.Active = types.BoolValue(site.Active)
 }
 
 // Delete deletes the site and removes the terraform plan on success.
@@ -225,10 +265,12 @@ func (r *siteResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	// 204 on successful delete
 	tflog.Info(ctx, "Deleting site: "+state.Domain.ValueString()+" with id: "+state.ID.String())
 
-	err := r.client.DeleteSite(int(state.ID.ValueInt64()))
+	err := r.client.DeleteSite(ctx, int(state.ID.ValueInt64()))
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting a site",
@@ -248,6 +290,11 @@ func (r *siteResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	)
 }
 
+// ModifyPlan refuses any change in read-only mode.
+func (r *siteResource) ModifyPlan(_ context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	helpers.CheckReadOnlyPlan(r.client, "site", req, resp)
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *siteResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -264,9 +311,34 @@ func (r *siteResource) Configure(_ context.Context, req resource.ConfigureReques
 	r.client = client
 }
 
+// IdentitySchema defines the identity of the resource, to import it with an identity instead of an ID.
+func (*siteResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"company_id": helpers.CompanyIDIdentityAttribute(),
+			"domain": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Domain of the site.",
+			},
+		},
+	}
+}
+
 func (*siteResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Imported with an identity instead of an ID
+	if req.ID == "" {
+		helpers.ImportFromIdentity(ctx, req, resp)
+
+		return
+	}
+
 	// Import without a default create timeout
-	resource.ImportStatePassthroughID(ctx, path.Root("domain"), req, resp)
+	importID, ok := helpers.ImportCompanyID(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), importID)...)
 
 	/*
 		// Import with a default create timeout
@@ -291,12 +363,12 @@ func (*siteResource) ImportState(ctx context.Context, req resource.ImportStateRe
 
 // HelperCreateSite.
 func (r *siteResource) HelperCreateSite(ctx context.Context, domain string, maxTimeout time.Duration) (*Site, error) {
-	remainingTimeForVerification := maxTimeout.Seconds()
+	deadline := time.Now().Add(maxTimeout)
 	siteCreate := teclient.SiteNewAPIModel{URL: domain}
 	siteState := Site{}
 
 	for {
-		site, verifyError, err := r.client.CreateSite(siteCreate)
+		site, verifyError, err := r.client.CreateSite(ctx, siteCreate)
 		if err == nil {
 			siteState.ID = types.Int64Value(int64(site.ID))
 			siteState.Domain = types.StringValue(site.URL)
@@ -309,12 +381,17 @@ func (r *siteResource) HelperCreateSite(ctx context.Context, domain string, maxT
 			return nil, err
 		}
 
-		if remainingTimeForVerification <= 0 {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
 			return nil, errors.New("timeout")
 		}
 
-		time.Sleep(delayBetweenCreateRetry)
-		remainingTimeForVerification -= (delayBetweenCreateRetry.Seconds() + 5)
+		// Do not sleep past the deadline, the last attempt is made right before it
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("site verification interrupted: %w", ctx.Err())
+		case <-time.After(min(delayBetweenCreateRetry, remaining)):
+		}
 
 		tflog.Info(ctx, "Retry site verification for "+domain)
 	}
@@ -0,0 +1,255 @@
+package autoprovisioning_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck/queryfilter"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+func testAccSiteConfig(domain string) string {
+	return `
+resource "transparentedge_site" "test" {
+  domain = "` + domain + `"
+
+  timeouts = {
+    create = "1s"
+  }
+}
+`
+}
+
+func TestAccSiteResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig("www.example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_site.test", "id"),
+					resource.TestCheckResourceAttr("transparentedge_site.test", "domain", "www.example.com"),
+					resource.TestCheckResourceAttr("transparentedge_site.test", "active", "true"),
+				),
+			},
+			{
+				ResourceName:                         "transparentedge_site.test",
+				ImportState:                          true,
+				ImportStateId:                        "www.example.com",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "domain",
+				ImportStateVerifyIgnore:              []string{"timeouts"},
+			},
+			{
+				Config: testAccSiteConfig("www2.example.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_site.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("transparentedge_site.test", "domain", "www2.example.com"),
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_deactivatedOutsideTerraform(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccSiteConfig("www.example.com"),
+				Check:              removeFromAPI(srv, "transparentedge_site.test"),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccSiteConfig("www.example.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_site.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("transparentedge_site.test", "active", "true"),
+			},
+		},
+	})
+}
+
+// A site disabled and added again outside Terraform has a new ID, it's adopted by its domain.
+func TestAccSiteResource_recreatedOutsideTerraform(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	var recreatedID int
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig("www.example.com"),
+				Check: func(s *terraform.State) error {
+					id, err := strconv.Atoi(s.RootModule().Resources["transparentedge_site.test"].Primary.Attributes["id"])
+					if err != nil {
+						return err
+					}
+
+					recreatedID = srv.RecreateSite(id)
+					if recreatedID == 0 {
+						return fmt.Errorf("site %d not found in the API", id)
+					}
+
+					return nil
+				},
+			},
+			{
+				Config: testAccSiteConfig("www.example.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_site.test", plancheck.ResourceActionNoop),
+					},
+				},
+				Check: func(s *terraform.State) error {
+					if id := s.RootModule().Resources["transparentedge_site.test"].Primary.Attributes["id"]; id != strconv.Itoa(recreatedID) {
+						return fmt.Errorf("expected the site to be adopted with the ID %d, got %s", recreatedID, id)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_verificationFailure(t *testing.T) {
+	srv := acctest.NewServer(t)
+	srv.FailSiteVerification("www.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccSiteConfig("www.example.com"),
+				ExpectError: regexp.MustCompile(`timeout`),
+			},
+			{
+				PreConfig: func() { srv.PassSiteVerification("www.example.com") },
+				Config:    testAccSiteConfig("www.example.com"),
+				Check:     resource.TestCheckResourceAttr("transparentedge_site.test", "active", "true"),
+			},
+		},
+	})
+}
+
+func TestAccSiteResource_foreignDomain(t *testing.T) {
+	srv := acctest.NewServer(t)
+	srv.SetForeignDomain("www.example.com")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccSiteConfig("www.example.com"),
+				ExpectError: regexp.MustCompile(`Site ownership denied`),
+			},
+		},
+	})
+}
+
+func TestAccSitesDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig("www.example.com") + `
+data "transparentedge_sites" "all" {
+  depends_on = [transparentedge_site.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transparentedge_sites.all", "sites.#", "1"),
+					resource.TestCheckResourceAttrPair("data.transparentedge_sites.all", "sites.0.id", "transparentedge_site.test", "id"),
+					resource.TestCheckResourceAttr("data.transparentedge_sites.all", "sites.0.domain", "www.example.com"),
+					resource.TestCheckResourceAttr("data.transparentedge_sites.all", "sites.0.active", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSiteVerifyDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "transparentedge_siteverify" "test" {
+  domain = "www.example.com"
+}
+`,
+				Check: resource.TestCheckResourceAttr("data.transparentedge_siteverify.test", "verification_string", testserver.VerificationString("www.example.com")),
+			},
+		},
+	})
+}
+
+func TestAccSiteListResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSiteConfig("www.example.com") + `
+resource "transparentedge_site" "static" {
+  domain = "static.example.org"
+}
+`,
+			},
+			{
+				Query: true,
+				Config: `
+provider "transparentedge" {}
+
+list "transparentedge_site" "example_com" {
+  provider         = transparentedge
+  include_resource = true
+
+  config {
+    domain_regex = "\\.example\\.com$"
+  }
+}
+`,
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLength("transparentedge_site.example_com", 1),
+					querycheck.ExpectIdentity("transparentedge_site.example_com", map[string]knownvalue.Check{
+						"company_id": knownvalue.Int64Exact(testserver.DefaultCompanyID),
+						"domain":     knownvalue.StringExact("www.example.com"),
+					}),
+					querycheck.ExpectResourceKnownValues("transparentedge_site.example_com", queryfilter.ByDisplayName(knownvalue.StringExact("www.example.com")), []querycheck.KnownValueCheck{
+						{
+							Path:       tfjsonpath.New("active"),
+							KnownValue: knownvalue.Bool(true),
+						},
+					}),
+				},
+			},
+		},
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -38,6 +39,7 @@ func (*sitesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 		Description:         "Sites listing.",
 		MarkdownDescription: "Sites listing.",
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("sites"),
 			"sites": schema.ListNestedAttribute{
 				Computed:    true,
 				Description: "List of all active sites.",
@@ -76,10 +78,18 @@ func (*sitesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, re
 }
 
 // Read refreshes the Terraform state with the latest data.
-func (d *sitesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (d *sitesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state Sites
 
-	sites, err := d.client.GetSites()
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	sites, err := d.client.GetSites(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading sites",
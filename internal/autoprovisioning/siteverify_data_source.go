@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -37,6 +38,7 @@ func (*siteVerifyDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 		Description:         "Shows the verification string of sites.",
 		MarkdownDescription: "Shows the verification string of sites.",
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("verification string"),
 			"domain": schema.StringAttribute{
 				Required:            true,
 				Description:         "Domain to verify.",
@@ -57,9 +59,11 @@ func (d *siteVerifyDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	// Read Terraform configuration data into the model
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
+	ctx = helpers.CompanyContext(ctx, data.CompanyID)
+
 	domain := data.Domain.ValueString()
 
-	verifyString := d.client.GetSiteVerifyString(domain)
+	verifyString := d.client.GetSiteVerifyString(ctx, domain)
 	if verifyString == "" {
 		resp.Diagnostics.AddError(
 			"Unable to retrieve Site Verification string",
@@ -75,6 +79,7 @@ func (d *siteVerifyDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	}
 
 	// Save data into Terraform state
+	data.CompanyID = helpers.CompanyIDValue(ctx, d.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
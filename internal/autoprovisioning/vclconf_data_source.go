@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/customtypes"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -39,6 +40,7 @@ func (*vclconfDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 		MarkdownDescription: "VCL Configuration listing.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("VCL configuration"),
 			"id": schema.Int64Attribute{
 				Computed:            true,
 				Description:         "ID of the VCL Config.",
@@ -80,10 +82,18 @@ func (*vclconfDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 }
 
 // Read refreshes the Terraform state with the latest data.
-func (d *vclconfDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var state VCLConf
+func (d *vclconfDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ActiveVCLConf
 
-	apiResp, err := d.client.GetActiveVCLConf(apiEnv)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	apiResp, err := d.client.GetActiveVCLConf(ctx, apiEnv)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to read VclConf info",
@@ -94,13 +104,14 @@ func (d *vclconfDataSource) Read(ctx context.Context, _ datasource.ReadRequest,
 	}
 
 	state.ID = types.Int64Value(int64(apiResp.ID))
-	state.Company = types.Int64Value(int64(apiResp.ID))
+	state.Company = types.Int64Value(int64(apiResp.Company))
 	state.VCLCode = customtypes.NewVCLCodeValue(apiResp.VCLCode)
 	state.UploadDate = types.StringValue(apiResp.UploadDate)
 	state.ProductionDate = types.StringValue(apiResp.ProductionDate)
 	state.User = types.StringValue(apiResp.CreatorUser.FirstName + " " + apiResp.CreatorUser.LastName + " <" + apiResp.CreatorUser.Email + ">")
 	state.Comment = types.StringValue(apiResp.Comment)
 
+	state.CompanyID = helpers.CompanyIDValue(ctx, d.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -0,0 +1,74 @@
+package autoprovisioning
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &vclconfHistoryDataSource{}
+	_ datasource.DataSourceWithConfigure = &vclconfHistoryDataSource{}
+)
+
+// NewVclconfHistoryDataSource is a helper function to simplify the provider implementation.
+func NewVclconfHistoryDataSource() datasource.DataSource {
+	return &vclconfHistoryDataSource{}
+}
+
+// data source implementation.
+type vclconfHistoryDataSource struct {
+	client *teclient.Client
+}
+
+// Metadata returns the data source type name.
+func (*vclconfHistoryDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vclconf_history"
+}
+
+// Schema defines the schema for the data source.
+func (*vclconfHistoryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = helpers.VCLConfHistorySchema("production")
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *vclconfHistoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state helpers.VCLConfHistory
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	helpers.ReadVCLConfHistory(ctx, d.client, apiEnv, &state, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.CompanyID = helpers.CompanyIDValue(ctx, d.client)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *vclconfHistoryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*teclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to configure", "error while configuring API client")
+
+		return
+	}
+
+	d.client = client
+}
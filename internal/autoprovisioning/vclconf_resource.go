@@ -2,6 +2,7 @@ package autoprovisioning
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -24,12 +26,20 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &vclconfResource{}
-	_ resource.ResourceWithConfigure   = &vclconfResource{}
-	_ resource.ResourceWithImportState = &vclconfResource{}
-	_ resource.ResourceWithModifyPlan  = &vclconfResource{}
+	_ resource.Resource                   = &vclconfResource{}
+	_ resource.ResourceWithConfigure      = &vclconfResource{}
+	_ resource.ResourceWithImportState    = &vclconfResource{}
+	_ resource.ResourceWithModifyPlan     = &vclconfResource{}
+	_ resource.ResourceWithValidateConfig = &vclconfResource{}
+	_ resource.ResourceWithIdentity       = &vclconfResource{}
 )
 
+// vclconfAPIFields maps the API fields of a VCL configuration to the resource attributes.
+var vclconfAPIFields = map[string]path.Path{
+	"config_body": path.Root("vclcode"),
+	"comment":     path.Root("comment"),
+}
+
 // NewVclconfResource is a helper function to simplify the provider implementation.
 func NewVclconfResource() resource.Resource {
 	return &vclconfResource{}
@@ -51,9 +61,12 @@ func (*vclconfResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 		Description: "Manages VCL Configuration.",
 		MarkdownDescription: "Provides VCL Configuration resource. This allows to generate a new VCL configuration that replaces the current one." +
 			" Changing `vclcode` or `comment` uploads a new configuration version in place (no destroy/recreate)." +
-			" Destroying the resource uploads an empty VCL configuration so that any backends referenced by the current code can be removed afterwards.",
+			" Destroying the resource uploads an empty VCL configuration so that any backends referenced by the current code can be removed afterwards." +
+			" The code is checked for backends of the company that don't exist: the plan only warns about them, since they may be created in the same apply," +
+			" and the upload fails if they still don't exist then. Use the `vclname` attribute of those backends, or add them to `depends_on`, so they're created first.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDResourceAttribute("VCL configuration"),
 			"id": schema.Int64Attribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.Int64{
@@ -71,14 +84,19 @@ func (*vclconfResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 				MarkdownDescription: "Company ID that owns this VCL config.",
 			},
 			"vclcode": schema.StringAttribute{
-				Required:   true,
+				Optional:   true,
+				Computed:   true,
 				CustomType: customtypes.VCLCodeType{},
 				Description: "Verbatim of the VCL (Varnish Configuration Language) code configuration to apply." +
 					" After a successful code upload, it may take between 5 and 10 minutes for the new configuration to be fully applied." +
-					" You can know if a configuration is already in production by running 'terraform plan' and checking the 'productiondate' field.",
+					" You can know if a configuration is already in production by running 'terraform plan' and checking the 'productiondate' field." +
+					" The syntax of the code is checked by 'terraform validate', the API compiles it when it's uploaded." +
+					" Either this or 'snippets' must be set.",
 				MarkdownDescription: "Verbatim of the VCL (_Varnish Configuration Language_) code configuration to apply." +
 					" After a successful code upload, it may take between 5 and 10 minutes for the new configuration to be fully replicated in all the CDN edge nodes." +
-					" You can check if a configuration is already in production by running `terraform plan` and checking the `productiondate` field.",
+					" You can check if a configuration is already in production by running `terraform plan` and checking the `productiondate` field." +
+					" The syntax of the code is checked by `terraform validate`, the API compiles it when it's uploaded." +
+					" Either this or `snippets` must be set.",
 			},
 			"uploaddate": schema.StringAttribute{
 				Computed: true,
@@ -111,6 +129,8 @@ func (*vclconfResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 				Description:         "Optional comment describing the changes introduced by this configuration.",
 				MarkdownDescription: "Optional comment describing the changes introduced by this configuration.",
 			},
+			"referenced_backends": helpers.ReferencedBackendsAttribute(),
+			"diff_context_lines":  helpers.DiffContextLinesAttribute(),
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 				CreateDescription: "If set, the provider will wait until the VCL configuration is fully deployed " +
@@ -118,6 +138,9 @@ func (*vclconfResource) Schema(ctx context.Context, _ resource.SchemaRequest, re
 					"greater than 5m, since propagation typically takes between 5 and 10 minutes (e.g. \"15m\").",
 			}),
 		},
+		Blocks: map[string]schema.Block{
+			"snippets": helpers.SnippetsBlock(),
+		},
 	}
 }
 
@@ -133,6 +156,8 @@ func (r *vclconfResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	tflog.Info(ctx, "Creating new VCL configuration")
 
 	r.pushVCLConf(ctx, &plan, &resp.Diagnostics)
@@ -141,7 +166,10 @@ func (r *vclconfResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	plan.ReferencedBackends = helpers.ReferencedBackendsValue(plan.VCLCode.StringValue)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, VCLConfIdentity{CompanyID: plan.CompanyID})...)
 }
 
 // Update pushes a new VCL configuration version whenever vclcode or comment actually
@@ -156,9 +184,14 @@ func (r *vclconfResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	if helpers.VCLSemanticEquals(state.VCLCode.ValueString(), plan.VCLCode.ValueString()) &&
 		state.Comment.ValueString() == plan.Comment.ValueString() {
+		plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+		plan.ReferencedBackends = helpers.ReferencedBackendsValue(plan.VCLCode.StringValue)
 		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, VCLConfIdentity{CompanyID: plan.CompanyID})...)
 
 		return
 	}
@@ -171,7 +204,10 @@ func (r *vclconfResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	plan.ReferencedBackends = helpers.ReferencedBackendsValue(plan.VCLCode.StringValue)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, VCLConfIdentity{CompanyID: plan.CompanyID})...)
 }
 
 // Read resource information.
@@ -186,7 +222,9 @@ func (r *vclconfResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	apiResp, err := r.client.GetActiveVCLConf(apiEnv)
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	apiResp, err := r.client.GetActiveVCLConf(ctx, apiEnv)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to read VclConf info",
@@ -206,7 +244,10 @@ func (r *vclconfResource) Read(ctx context.Context, req resource.ReadRequest, re
 	// by the API client, so syncing it here always matches, including right after an Import.
 	state.Comment = types.StringValue(apiResp.Comment)
 
+	state.ReferencedBackends = helpers.ReferencedBackendsValue(state.VCLCode.StringValue)
+	state.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, VCLConfIdentity{CompanyID: state.CompanyID})...)
 }
 
 // Delete uploads an empty VCL configuration so any backends referenced by the
@@ -221,6 +262,8 @@ func (r *vclconfResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	tflog.Info(ctx, "Emptying VCL configuration so any referenced backends can be deleted")
 
 	emptyConf := teclient.NewVCLConfAPIModel{
@@ -228,7 +271,7 @@ func (r *vclconfResource) Delete(ctx context.Context, req resource.DeleteRequest
 		Comment: "Emptied by 'terraform destroy'",
 	}
 
-	_, errCreate := r.client.CreateVclconf(emptyConf, apiEnv)
+	_, errCreate := r.client.CreateVclconf(ctx, emptyConf, apiEnv)
 	if errCreate != nil {
 		resp.Diagnostics.AddError(
 			"Error emptying VCL Configuration",
@@ -237,10 +280,30 @@ func (r *vclconfResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 }
 
+// ValidateConfig checks that the VCL code is set either in vclcode or in snippets.
+func (*vclconfResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config VCLConf
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	helpers.ValidateVCLSource(ctx, config.VCLCode.StringValue, config.Snippets, &resp.Diagnostics)
+}
+
 // ModifyPlan marks the computed attributes as unknown whenever a new VCL configuration
 // version is going to be uploaded (i.e. vclcode or comment change), since the API always
-// assigns fresh values (id, dates, ...) to every uploaded version.
-func (*vclconfResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+// assigns fresh values (id, dates, ...) to every uploaded version, and reports the changes of
+// the code as a unified diff.
+func (r *vclconfResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	helpers.CheckReadOnlyPlan(r.client, "VCL configuration", req, resp)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// If the entire plan is null, the resource is planned for destruction.
 	if req.Plan.Raw.IsNull() {
 		resp.Diagnostics.AddWarning(
@@ -253,15 +316,32 @@ func (*vclconfResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanR
 		return
 	}
 
+	var plan VCLConf
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// vclcode is assembled from the snippets when they're set
+	if plan.Snippets.IsUnknown() || len(plan.Snippets.Elements()) > 0 {
+		plan.VCLCode = customtypes.VCLCodeValue{StringValue: helpers.AssembleSnippets(ctx, plan.Snippets, &resp.Diagnostics)}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("vclcode"), plan.VCLCode)...)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("referenced_backends"), helpers.ReferencedBackendsValue(plan.VCLCode.StringValue))...)
+
 	// Nothing to compare against yet, this is a resource creation.
 	if req.State.Raw.IsNull() {
+		r.checkReferencedBackends(ctx, plan, &resp.Diagnostics)
+
 		return
 	}
 
-	var state, plan VCLConf
+	var state VCLConf
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -284,6 +364,22 @@ func (*vclconfResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanR
 	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("uploaddate"), types.StringUnknown())...)
 	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("productiondate"), types.StringUnknown())...)
 	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("user"), types.StringUnknown())...)
+
+	if !plan.VCLCode.IsUnknown() {
+		helpers.AddVCLDiffWarning(&resp.Diagnostics, state.VCLCode.ValueString(), plan.VCLCode.ValueString(), plan.DiffContextLines)
+	}
+
+	r.checkReferencedBackends(ctx, plan, &resp.Diagnostics)
+}
+
+// checkReferencedBackends warns about the backends used by the planned code that don't exist yet.
+func (r *vclconfResource) checkReferencedBackends(ctx context.Context, plan VCLConf, diags *diag.Diagnostics) {
+	// The code is unknown when it uses the vclname of a backend created or changed in the same plan
+	if plan.VCLCode.IsUnknown() {
+		return
+	}
+
+	helpers.WarnReferencedBackends(helpers.CompanyContext(ctx, plan.CompanyID), r.client, apiEnv, plan.VCLCode.ValueString(), diags)
 }
 
 // Configure adds the provider configured client to the resource.
@@ -302,10 +398,31 @@ func (r *vclconfResource) Configure(_ context.Context, req resource.ConfigureReq
 	r.client = client
 }
 
+// IdentitySchema defines the identity of the resource, to import it with an identity instead of an ID.
+func (*vclconfResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"company_id": helpers.CompanyIDIdentityAttribute(),
+		},
+	}
+}
+
 func (*vclconfResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Imported with an identity instead of an ID
+	if req.ID == "" {
+		helpers.ImportFromIdentity(ctx, req, resp)
+
+		return
+	}
+
 	// path.Root here is ignored
 	// VCL Configs can be imported without issues, but they won't match perfectly
 	// the configuration because of newlines and spaces
+	// The ID value doesn't matter, but a 'company_id/' prefix selects the company
+	if _, ok := helpers.ImportCompanyID(ctx, req, resp); !ok {
+		return
+	}
+
 	resource.ImportStatePassthroughID(ctx, path.Root("user"), req, resp)
 }
 
@@ -313,17 +430,28 @@ func (*vclconfResource) ImportState(ctx context.Context, req resource.ImportStat
 // populates the computed attributes with the API response. Used by both Create and
 // Update, since every upload produces a brand new history entry in the API.
 func (r *vclconfResource) pushVCLConf(ctx context.Context, plan *VCLConf, diags *diag.Diagnostics) {
+	// The backends created in the same apply exist by now, the ones still missing would fail to compile
+	helpers.CheckReferencedBackends(ctx, r.client, apiEnv, plan.VCLCode.ValueString(), diags)
+
+	if diags.HasError() {
+		return
+	}
+
 	newConf := teclient.NewVCLConfAPIModel{
 		VCLCode: plan.VCLCode.ValueString(),
 		Comment: plan.Comment.ValueString(),
 	}
 
-	apiResp, errCreate := r.client.CreateVclconf(newConf, apiEnv)
+	apiResp, errCreate := r.client.CreateVclconf(ctx, newConf, apiEnv)
 	if errCreate != nil {
-		diags.AddError(
-			"Error uploading Production VCL Configuration",
-			fmt.Sprintf("Could not upload the vclconf: %s", errCreate),
-		)
+		var compileErr *teclient.VCLCompilationError
+		if errors.As(errCreate, &compileErr) {
+			diags.AddAttributeError(path.Root("vclcode"), "Error uploading Production VCL Configuration", compileErr.Error())
+
+			return
+		}
+
+		helpers.AddAPIError(diags, "Error uploading Production VCL Configuration", "Could not upload the vclconf", errCreate, vclconfAPIFields)
 
 		return
 	}
@@ -359,7 +487,7 @@ poll:
 			break poll
 
 		case <-time.After(10 * time.Second):
-			vclconf, err := r.client.GetVCLConfByID(apiEnv, apiResp.ID)
+			vclconf, err := r.client.GetVCLConfByID(pollCtx, apiEnv, apiResp.ID)
 			if err != nil {
 				continue
 			}
@@ -0,0 +1,614 @@
+package autoprovisioning_test
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+// testAccVCLConfConfig creates the backend used by testAccVCLCode along with the configuration.
+func testAccVCLConfConfig(vclcode string, comment string) string {
+	return testAccBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_vclconf" "test" {
+  vclcode = <<-EOT
+` + vclcode + `
+  EOT
+  comment = "` + comment + `"
+}
+`
+}
+
+// testAccVCLCode uses the vclname of the backend, so Terraform creates it before uploading the code.
+const testAccVCLCode = `    sub vcl_recv {
+      if (req.http.host == "www.example.com") {
+        set req.backend_hint = ${transparentedge_backend.test.vclname}.backend();
+      }
+    }`
+
+func TestAccVCLConfResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVCLConfConfig(testAccVCLCode, "first version"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_vclconf.test", "id"),
+					resource.TestCheckResourceAttrSet("transparentedge_vclconf.test", "uploaddate"),
+					resource.TestCheckResourceAttrSet("transparentedge_vclconf.test", "productiondate"),
+					resource.TestCheckResourceAttr("transparentedge_vclconf.test", "comment", "first version"),
+					resource.TestCheckResourceAttr("transparentedge_vclconf.test", "referenced_backends.#", "1"),
+					resource.TestCheckTypeSetElemAttr("transparentedge_vclconf.test", "referenced_backends.*", "c300_origin1"),
+					resource.TestCheckResourceAttr("transparentedge_vclconf.test", "user", "Acceptance Tests <acctest@example.com>"),
+				),
+			},
+			{
+				ResourceName:      "transparentedge_vclconf.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccVCLConfConfig(testAccVCLCode, "second version"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_vclconf.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue("transparentedge_vclconf.test", tfjsonpath.New("id")),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("transparentedge_vclconf.test", "comment", "second version"),
+			},
+		},
+	})
+}
+
+func TestAccVCLConfResource_compilationError(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccVCLConfConfig("    sub vcl_recv { "+testserver.CompilationErrorMarker+" }", ""),
+				ExpectError: regexp.MustCompile(`VCL COMPILATION ERROR`),
+			},
+		},
+	})
+}
+
+// Syntax errors are reported by 'terraform validate', before any configuration is uploaded.
+func TestAccVCLConfResource_syntaxError(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccVCLConfConfig("    sub vcl_recv {\n      set req.http.X-Test \"true\";\n    }", ""),
+				ExpectError: regexp.MustCompile(`(?s)Invalid VCL code.*line 2, column 23.*Expected '=' after the\s+variable`),
+			},
+			{
+				Config:      testAccVCLConfConfig("    sub vcl_recieve {\n    }", ""),
+				ExpectError: regexp.MustCompile(`Unknown subroutine\s+'vcl_recieve'`),
+			},
+		},
+	})
+
+	if n := srv.CountRequests("POST", "/v1/autoprovisioning/300/config"); n != 0 {
+		t.Errorf("expected no uploads, got %d", n)
+	}
+}
+
+// The backends used by the code must exist, or be created before the configuration is uploaded.
+func TestAccVCLConfResource_unknownBackend(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "transparentedge_vclconf" "test" {
+  vclcode = <<-EOT
+    sub vcl_recv {
+      set req.backend_hint = c300_origni1.backend();
+    }
+  EOT
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Unknown backend.*The backend 'c300_origni1' used at line 2, column 26`),
+			},
+			{
+				// The vclname of a backend created in the same plan is only known once it exists
+				Config: testAccBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_vclconf" "test" {
+  vclcode = <<-EOT
+    sub vcl_recv {
+      set req.backend_hint = ${transparentedge_backend.test.vclname}.backend();
+    }
+  EOT
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue("transparentedge_vclconf.test", tfjsonpath.New("referenced_backends")),
+					},
+				},
+				Check: resource.TestCheckTypeSetElemAttr("transparentedge_vclconf.test", "referenced_backends.*", "c300_origin1"),
+			},
+		},
+	})
+
+	if n := srv.CountRequests("POST", "/v1/autoprovisioning/300/config"); n != 2 {
+		t.Errorf("expected the upload of the second step and the one emptying the configuration, got %d", n)
+	}
+}
+
+// A backend created in the same apply can be used by its name, the plan only warns that it doesn't exist yet.
+func TestAccVCLConfResource_literalBackend(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_vclconf" "test" {
+  vclcode = <<-EOT
+    sub vcl_recv {
+      set req.backend_hint = c300_origin1.backend();
+    }
+  EOT
+
+  depends_on = [transparentedge_backend.test]
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue("transparentedge_vclconf.test", tfjsonpath.New("referenced_backends"), knownvalue.SetExact([]knownvalue.Check{
+							knownvalue.StringExact("c300_origin1"),
+						})),
+					},
+				},
+				Check: resource.TestCheckResourceAttrSet("transparentedge_vclconf.test", "id"),
+			},
+		},
+	})
+}
+
+// Updating a backend keeps its vclname known, so the configuration using it isn't uploaded again,
+// and renaming it plans the new vclname.
+func TestAccVCLConfResource_backendUpdate(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVCLConfConfig(testAccVCLCode, ""),
+			},
+			{
+				Config: strings.Replace(testAccVCLConfConfig(testAccVCLCode, ""), "origin.example.com", "other.example.com", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_backend.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("transparentedge_backend.test", tfjsonpath.New("vclname"), knownvalue.StringExact("c300_origin1")),
+						plancheck.ExpectResourceAction("transparentedge_vclconf.test", plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			{
+				Config: strings.Replace(testAccVCLConfConfig(testAccVCLCode, ""), `"origin1"`, `"origin2"`, 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_backend.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("transparentedge_backend.test", tfjsonpath.New("vclname"), knownvalue.StringExact("c300_origin2")),
+						plancheck.ExpectKnownValue("transparentedge_vclconf.test", tfjsonpath.New("referenced_backends"), knownvalue.SetExact([]knownvalue.Check{
+							knownvalue.StringExact("c300_origin2"),
+						})),
+					},
+				},
+				Check: resource.TestCheckTypeSetElemAttr("transparentedge_vclconf.test", "referenced_backends.*", "c300_origin2"),
+			},
+		},
+	})
+}
+
+// Changing diff_context_lines only changes the diff reported by the plan, no configuration is uploaded.
+func TestAccVCLConfResource_diffContextLines(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	config := func(vclcode string, contextLines string) string {
+		return testAccBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_vclconf" "test" {
+  vclcode = <<-EOT
+` + vclcode + `
+  EOT
+  diff_context_lines = ` + contextLines + `
+}
+`
+	}
+
+	changedCode := strings.Replace(testAccVCLCode, "www.example.com", "static.example.com", 1)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(testAccVCLCode, "-2"),
+				ExpectError: regexp.MustCompile(`Attribute diff_context_lines value must be at least -1`),
+			},
+			{
+				Config: config(testAccVCLCode, "1"),
+				Check:  resource.TestCheckResourceAttr("transparentedge_vclconf.test", "diff_context_lines", "1"),
+			},
+			{
+				Config: config(changedCode, "1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue("transparentedge_vclconf.test", tfjsonpath.New("id")),
+					},
+				},
+			},
+			{
+				Config: config(changedCode, "-1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_vclconf.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectKnownValue("transparentedge_vclconf.test", tfjsonpath.New("id"), knownvalue.NotNull()),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("transparentedge_vclconf.test", "diff_context_lines", "-1"),
+			},
+		},
+	})
+
+	if n := srv.CountRequests("POST", "/v1/autoprovisioning/300/config"); n != 3 {
+		t.Errorf("expected the uploads of the first and second versions and the one emptying the configuration, got %d", n)
+	}
+}
+
+// The snippets are assembled into vclcode, sorted by subroutine and priority.
+func TestAccVCLConfResource_snippets(t *testing.T) {
+	acctest.NewServer(t)
+
+	config := func(firstPriority int) string {
+		return testAccBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_vclconf" "test" {
+  snippets {
+    name       = "first"
+    subroutine = "vcl_recv"
+    priority   = ` + strconv.Itoa(firstPriority) + `
+    content    = "set req.http.X-First = \"1\";"
+  }
+
+  snippets {
+    name       = "backend"
+    subroutine = "vcl_recv"
+    priority   = 10
+    content    = <<-EOT
+      if (req.http.host == "www.example.com") {
+        set req.backend_hint = ${transparentedge_backend.test.vclname}.backend();
+      }
+    EOT
+  }
+
+  snippets {
+    content = "import std;"
+  }
+}
+`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("transparentedge_vclconf.test", "vclcode",
+						regexp.MustCompile(`(?s)# BEGIN snippet, priority 0\nimport std;.*sub vcl_recv \{\n    # BEGIN snippet first, priority 0\n.*# BEGIN snippet backend, priority 10\n`)),
+					resource.TestCheckResourceAttr("transparentedge_vclconf.test", "snippets.2.priority", "0"),
+					resource.TestCheckTypeSetElemAttr("transparentedge_vclconf.test", "referenced_backends.*", "c300_origin1"),
+				),
+			},
+			{
+				Config: config(20),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_vclconf.test", plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue("transparentedge_vclconf.test", tfjsonpath.New("id")),
+					},
+				},
+				Check: resource.TestMatchResourceAttr("transparentedge_vclconf.test", "vclcode",
+					regexp.MustCompile(`(?s)# BEGIN snippet backend, priority 10\n.*# BEGIN snippet first, priority 20\n`)),
+			},
+			{
+				// Back to verbatim code
+				Config: testAccVCLConfConfig(testAccVCLCode, ""),
+				Check:  resource.TestCheckResourceAttr("transparentedge_vclconf.test", "snippets.#", "0"),
+			},
+		},
+	})
+}
+
+func TestAccVCLConfResource_snippetsErrors(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "transparentedge_vclconf" "test" {
+  comment = "no code"
+}
+`,
+				ExpectError: regexp.MustCompile(`Either 'vclcode' or 'snippets' must be set`),
+			},
+			{
+				Config: `
+resource "transparentedge_vclconf" "test" {
+  vclcode = "sub vcl_recv {}"
+
+  snippets {
+    subroutine = "vcl_recv"
+    content    = "return (pass);"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Only one of 'vclcode' or 'snippets' can be set`),
+			},
+			{
+				Config: `
+resource "transparentedge_vclconf" "test" {
+  snippets {
+    subroutine = "vcl_recv"
+    content    = "return (pass);"
+  }
+
+  snippets {
+    subroutine = "vcl_deliver"
+    content    = "unset resp.http.Via;\nset resp.http.X-Test \"true\";"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Syntax error at line 2, column 22 of the\s+snippet: Expected '=' after the\s+variable`),
+			},
+			{
+				Config: `
+resource "transparentedge_vclconf" "test" {
+  snippets {
+    subroutine = "vcl_recieve"
+    content    = "return (pass);"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Unknown subroutine\s+'vcl_recieve'`),
+			},
+			{
+				Config: `
+resource "transparentedge_vclconf" "test" {
+  snippets {
+    subroutine = "vcl recv"
+    content    = "return (pass);"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`must be the name of a subroutine`),
+			},
+			{
+				Config: `
+resource "transparentedge_vclconf" "test" {
+  snippets {
+    name       = "pass"
+    subroutine = "vcl_recv"
+    content    = "return (pass);"
+  }
+
+  snippets {
+    name       = "pass"
+    subroutine = "vcl_pass"
+    content    = "return (fetch);"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Duplicate snippet name.*The name 'pass' is already used by\s+snippets\[0\]`),
+			},
+		},
+	})
+
+	if n := srv.CountRequests("POST", "/v1/autoprovisioning/300/config"); n != 0 {
+		t.Errorf("expected no uploads, got %d", n)
+	}
+}
+
+// The backend can only be deleted after the VCL configuration referencing it is emptied.
+func TestAccVCLConfResource_withBackend(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_vclconf" "test" {
+  vclcode = <<-EOT
+` + testAccVCLCode + `
+  EOT
+}
+`,
+				Check: resource.TestCheckResourceAttrSet("transparentedge_vclconf.test", "id"),
+			},
+		},
+	})
+}
+
+func TestAccVCLConfDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVCLConfConfig(testAccVCLCode, "from data source") + `
+data "transparentedge_vclconf" "active" {
+  depends_on = [transparentedge_vclconf.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.transparentedge_vclconf.active", "id", "transparentedge_vclconf.test", "id"),
+					resource.TestCheckResourceAttrPair("data.transparentedge_vclconf.active", "vclcode", "transparentedge_vclconf.test", "vclcode"),
+					resource.TestCheckResourceAttr("data.transparentedge_vclconf.active", "comment", "from data source"),
+					resource.TestCheckResourceAttr("data.transparentedge_vclconf.active", "company", strconv.Itoa(testserver.DefaultCompanyID)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccVCLConfHistoryDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	steps := []resource.TestStep{}
+	for _, comment := range []string{"first", "second", "third"} {
+		steps = append(steps, resource.TestStep{Config: testAccVCLConfConfig(testAccVCLCode, comment)})
+	}
+
+	steps = append(steps, resource.TestStep{
+		Config: testAccVCLConfConfig(testAccVCLCode, "third") + `
+data "transparentedge_vclconf_history" "all" {
+  depends_on = [transparentedge_vclconf.test]
+}
+
+data "transparentedge_vclconf_history" "last" {
+  limit        = 1
+  include_code = true
+
+  depends_on = [transparentedge_vclconf.test]
+}
+
+data "transparentedge_vclconf_history" "filtered" {
+  since         = "2000-01-01"
+  uploaded_by   = "ACCTEST@example.com"
+  comment_regex = "^(first|third)$"
+
+  depends_on = [transparentedge_vclconf.test]
+}
+
+data "transparentedge_vclconf_history" "future" {
+  since = "2999-01-01T00:00:00Z"
+
+  depends_on = [transparentedge_vclconf.test]
+}
+
+data "transparentedge_vclconf_history" "other_user" {
+  uploaded_by = "someone"
+
+  depends_on = [transparentedge_vclconf.test]
+}
+`,
+		Check: resource.ComposeAggregateTestCheckFunc(
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.#", "3"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.0.comment", "third"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.0.active", "true"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.1.active", "false"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.2.comment", "first"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.2.creator_user.username", "acctest"),
+			resource.TestCheckNoResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.0.vclcode"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "company_id", strconv.Itoa(testserver.DefaultCompanyID)),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.last", "vclconfs.#", "1"),
+			resource.TestCheckResourceAttrPair("data.transparentedge_vclconf_history.last", "vclconfs.0.id", "transparentedge_vclconf.test", "id"),
+			resource.TestCheckResourceAttrPair("data.transparentedge_vclconf_history.last", "vclconfs.0.vclcode", "transparentedge_vclconf.test", "vclcode"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.filtered", "vclconfs.#", "2"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.filtered", "vclconfs.1.comment", "first"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.future", "vclconfs.#", "0"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.other_user", "vclconfs.#", "0"),
+		),
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps:                    steps,
+	})
+}
+
+func TestAccVCLConfHistoryDataSource_uploadDates(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	ids := []int{}
+	saveID := resource.TestCheckResourceAttrWith("transparentedge_vclconf.test", "id", func(value string) error {
+		id, err := strconv.Atoi(value)
+		ids = append(ids, id)
+
+		return err
+	})
+
+	steps := []resource.TestStep{}
+	for _, comment := range []string{"first", "second", "third"} {
+		steps = append(steps, resource.TestStep{Config: testAccVCLConfConfig(testAccVCLCode, comment), Check: saveID})
+	}
+
+	steps = append(steps, resource.TestStep{
+		PreConfig: func() {
+			// The first one is uploaded the day before in UTC, the second one has a date in an unknown format
+			srv.SetUploadDate(ids[0], "2024-01-31T00:30:00.250000+01:00")
+			srv.SetUploadDate(ids[1], "31/01/2024 10:00")
+			srv.SetUploadDate(ids[2], "2024-01-31T10:00:00.123456Z")
+		},
+		Config: testAccVCLConfConfig(testAccVCLCode, "third") + `
+data "transparentedge_vclconf_history" "all" {
+  depends_on = [transparentedge_vclconf.test]
+}
+
+data "transparentedge_vclconf_history" "since" {
+  since = "2024-01-31"
+
+  depends_on = [transparentedge_vclconf.test]
+}
+`,
+		Check: resource.ComposeAggregateTestCheckFunc(
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.#", "3"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.0.uploaddate", "2024-01-31T10:00:00.123456Z"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.all", "vclconfs.1.uploaddate", "31/01/2024 10:00"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.since", "vclconfs.#", "1"),
+			resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.since", "vclconfs.0.comment", "third"),
+		),
+	})
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps:                    steps,
+	})
+}
+
+func TestAccVCLConfHistoryDataSource_invalidFilters(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      `data "transparentedge_vclconf_history" "test" { since = "yesterday" }`,
+				ExpectError: regexp.MustCompile(`The 'since' filter 'yesterday' is not a date`),
+			},
+			{
+				Config:      `data "transparentedge_vclconf_history" "test" { comment_regex = "(" }`,
+				ExpectError: regexp.MustCompile(`Invalid regular expression`),
+			},
+			{
+				Config:      `data "transparentedge_vclconf_history" "test" { limit = 0 }`,
+				ExpectError: regexp.MustCompile(`Attribute limit value must be at least 1`),
+			},
+		},
+	})
+}
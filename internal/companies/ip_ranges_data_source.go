@@ -55,7 +55,7 @@ func (*ipRangesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 func (d *ipRangesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state IPRanges
 
-	cidrRanges, err := d.client.GetIPRanges()
+	cidrRanges, err := d.client.GetIPRanges(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading IP Ranges",
@@ -0,0 +1,28 @@
+package companies_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+)
+
+func TestAccIPRangesDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "transparentedge_ip_ranges" "all" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transparentedge_ip_ranges.all", "ipv4_cidr_blocks.#", "2"),
+					resource.TestCheckResourceAttr("data.transparentedge_ip_ranges.all", "ipv4_cidr_blocks.0", "192.0.2.0/24"),
+					resource.TestCheckResourceAttr("data.transparentedge_ip_ranges.all", "ipv6_cidr_blocks.#", "1"),
+					resource.TestCheckResourceAttr("data.transparentedge_ip_ranges.all", "ipv6_cidr_blocks.0", "2001:db8::/32"),
+				),
+			},
+		},
+	})
+}
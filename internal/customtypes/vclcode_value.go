@@ -2,12 +2,15 @@ package customtypes
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/vcl"
 )
 
 type VCLCodeValue struct {
@@ -17,6 +20,7 @@ type VCLCodeValue struct {
 var (
 	_ basetypes.StringValuable                   = VCLCodeValue{}
 	_ basetypes.StringValuableWithSemanticEquals = VCLCodeValue{}
+	_ xattr.ValidateableAttribute                = VCLCodeValue{}
 )
 
 func NewVCLCodeValue(value string) VCLCodeValue {
@@ -64,3 +68,19 @@ func (v VCLCodeValue) StringSemanticEquals(_ context.Context, newValuable basety
 
 	return eq, diags
 }
+
+// ValidateAttribute reports the syntax errors of the VCL code, so 'terraform validate' and 'terraform plan'
+// catch them before the API rejects the configuration in the middle of an apply.
+func (v VCLCodeValue) ValidateAttribute(_ context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	for _, err := range vcl.Validate(v.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid VCL code",
+			fmt.Sprintf("Syntax error at %s of the VCL code: %s", err.Pos, err.Message),
+		)
+	}
+}
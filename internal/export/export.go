@@ -0,0 +1,539 @@
+// Package export writes the Terraform configuration of the objects of a company, with the
+// import blocks that bring them under Terraform management. It backs the tcdn-export command.
+//
+// The VCL code of the active configurations is written to vcl/*.vcl, with the vclnames of the
+// backends replaced by references to the backend resources. The certificates are written to
+// certificates/, and the parameters of the DNS credentials to secrets.auto.tfvars.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// ErrExists is returned when a file to write already exists and Options.Overwrite is not set.
+var ErrExists = errors.New("already exists")
+
+// secretsFile holds the values of the sensitive variables.
+const secretsFile = "secrets.auto.tfvars"
+
+var (
+	// notIdentifierRe matches the characters that are not valid in a resource name.
+	notIdentifierRe = regexp.MustCompile(`[^a-z0-9_]+`)
+	// vclWordRe matches the words of the VCL code that may be a backend vclname.
+	vclWordRe = regexp.MustCompile(`[A-Za-z0-9_]+`)
+)
+
+// Options of Export.
+type Options struct {
+	// Dir is the directory the files are written to, it is created if missing.
+	Dir string
+	// Overwrite replaces the existing files, otherwise Export fails before writing anything.
+	Overwrite bool
+}
+
+// Summary is the number of objects exported of each resource type.
+type Summary map[string]int
+
+// file is a file to write, relative to Options.Dir.
+type file struct {
+	content []byte
+	perm    os.FileMode
+}
+
+type exporter struct {
+	client    *teclient.Client
+	companyID int
+	files     map[string]file
+	summary   Summary
+	// names are the resource names in use for each resource type.
+	names map[string]map[string]bool
+	// backends maps the vclnames of the backends of each environment to their resource address.
+	backends map[teclient.APIEnvironment]map[string]hcl.Traversal
+	// credentials maps the IDs of the DNS credentials to their resource address.
+	credentials map[int]hcl.Traversal
+	secrets     *hclwrite.File
+}
+
+// Export reads the objects of the company of client and writes their configuration to opts.Dir.
+// Nothing is written if reading any of the objects fails.
+func Export(ctx context.Context, client *teclient.Client, opts Options) (Summary, error) {
+	e := &exporter{
+		client:      client,
+		companyID:   client.CompanyIDFor(ctx),
+		files:       map[string]file{},
+		summary:     Summary{},
+		names:       map[string]map[string]bool{},
+		backends:    map[teclient.APIEnvironment]map[string]hcl.Traversal{},
+		credentials: map[int]hcl.Traversal{},
+		secrets:     hclwrite.NewEmptyFile(),
+	}
+
+	steps := []func(context.Context) error{
+		e.exportSites,
+		e.exportBackends,
+		e.exportVCLConfs,
+		e.exportCertificates,
+		e.exportDNSCredentials,
+		e.exportCertReqs,
+	}
+
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	e.exportProvider()
+
+	if len(e.secrets.Body().Attributes()) > 0 {
+		e.files[secretsFile] = file{
+			content: append([]byte("# Secrets of the DNS credentials, keep this file out of version control.\n\n"), e.secrets.Bytes()...),
+			perm:    0o600,
+		}
+	}
+
+	return e.summary, e.write(opts)
+}
+
+func (e *exporter) exportProvider() {
+	f := hclwrite.NewEmptyFile()
+
+	provider := f.Body().AppendNewBlock("terraform", nil).Body().AppendNewBlock("required_providers", nil).Body()
+	provider.SetAttributeRaw("transparentedge", hclwrite.TokensForObject([]hclwrite.ObjectAttrTokens{{
+		Name:  hclwrite.TokensForIdentifier("source"),
+		Value: hclwrite.TokensForValue(cty.StringVal("TransparentEdge/transparentedge")),
+	}}))
+
+	f.Body().AppendNewline()
+	f.Body().AppendNewBlock("provider", []string{"transparentedge"}).Body().SetAttributeValue("company_id", cty.NumberIntVal(int64(e.companyID)))
+
+	e.addConfigFile("provider.tf", f)
+}
+
+func (e *exporter) exportSites(ctx context.Context) error {
+	const rtype = "transparentedge_site"
+
+	sites, err := e.client.GetSites(ctx)
+	if err != nil {
+		return fmt.Errorf("reading the sites: %w", err)
+	}
+
+	f := hclwrite.NewEmptyFile()
+
+	for _, site := range sites {
+		// Disabled sites are not served by the CDN, the resource ignores them
+		if !site.Active {
+			continue
+		}
+
+		name := e.resourceName(rtype, site.URL)
+		body := e.addResource(f, rtype, name)
+		body.SetAttributeValue("domain", cty.StringVal(site.URL))
+		addImport(f, rtype, name, site.URL)
+	}
+
+	e.addConfigFile("sites.tf", f)
+
+	return nil
+}
+
+func (e *exporter) exportBackends(ctx context.Context) error {
+	environments := []struct {
+		env   teclient.APIEnvironment
+		rtype string
+		file  string
+	}{
+		{teclient.ProdEnv, "transparentedge_backend", "backends.tf"},
+		{teclient.StagingEnv, "transparentedge_staging_backend", "staging_backends.tf"},
+	}
+
+	for _, environment := range environments {
+		backends, err := e.client.GetBackends(ctx, environment.env)
+		if err != nil {
+			return fmt.Errorf("reading the backends: %w", err)
+		}
+
+		f := hclwrite.NewEmptyFile()
+		e.backends[environment.env] = map[string]hcl.Traversal{}
+
+		for _, backend := range backends {
+			name := e.resourceName(environment.rtype, backend.Name)
+			body := e.addResource(f, environment.rtype, name)
+			body.SetAttributeValue("name", cty.StringVal(backend.Name))
+			body.SetAttributeValue("origin", cty.StringVal(backend.Origin))
+			body.SetAttributeValue("port", cty.NumberIntVal(int64(backend.Port)))
+			body.SetAttributeValue("ssl", cty.BoolVal(backend.Ssl))
+
+			if backend.Headers != "" {
+				body.SetAttributeValue("headers", cty.StringVal(backend.Headers))
+			}
+
+			body.SetAttributeValue("hchost", cty.StringVal(backend.HCHost))
+			body.SetAttributeValue("hcpath", cty.StringVal(backend.HCPath))
+			body.SetAttributeValue("hcstatuscode", cty.NumberIntVal(int64(backend.HCStatusCode)))
+			body.SetAttributeValue("hcinterval", cty.NumberIntVal(int64(backend.HCInterval)))
+			body.SetAttributeValue("hcdisabled", cty.BoolVal(backend.HCDisabled))
+			addImport(f, environment.rtype, name, backend.Name)
+
+			vclName := "c" + strconv.Itoa(backend.Company) + "_" + backend.Name
+			e.backends[environment.env][vclName] = reference(environment.rtype, name, "vclname")
+		}
+
+		e.addConfigFile(environment.file, f)
+	}
+
+	return nil
+}
+
+func (e *exporter) exportVCLConfs(ctx context.Context) error {
+	environments := []struct {
+		env   teclient.APIEnvironment
+		rtype string
+		name  string
+	}{
+		{teclient.ProdEnv, "transparentedge_vclconf", "production"},
+		{teclient.StagingEnv, "transparentedge_staging_vclconf", "staging"},
+	}
+
+	f := hclwrite.NewEmptyFile()
+
+	for _, environment := range environments {
+		confs, err := e.client.ListVCLConfs(ctx, environment.env, 1)
+		if err != nil {
+			return fmt.Errorf("reading the %s VCL configuration: %w", environment.name, err)
+		}
+
+		if len(confs) == 0 {
+			continue
+		}
+
+		code, variables := vclTemplate(confs[0].VCLCode, e.backends[environment.env])
+		vclFile := "vcl/" + environment.name + ".vcl"
+		e.files[vclFile] = file{content: []byte(code), perm: 0o644}
+
+		body := e.addResource(f, environment.rtype, environment.name)
+		body.SetAttributeRaw("vclcode", templateFileCall(vclFile, variables))
+
+		if confs[0].Comment != "" {
+			body.SetAttributeValue("comment", cty.StringVal(confs[0].Comment))
+		}
+
+		// The ID of the active configuration is not needed to import it
+		addImport(f, environment.rtype, environment.name, "0")
+	}
+
+	e.addConfigFile("vclconfs.tf", f)
+
+	return nil
+}
+
+func (e *exporter) exportCertificates(ctx context.Context) error {
+	const rtype = "transparentedge_custom_certificate"
+
+	certificates, err := e.client.GetCertificates(ctx)
+	if err != nil {
+		return fmt.Errorf("reading the certificates: %w", err)
+	}
+
+	f := hclwrite.NewEmptyFile()
+
+	for _, certificate := range certificates {
+		// Autogenerated certificates are managed with certificate requests
+		if certificate.Autogenerated || certificate.DNSChallenge {
+			continue
+		}
+
+		name := e.resourceName(rtype, certificate.CommonName)
+		publicKey := "certificates/" + name + ".crt"
+		privateKey := "certificates/" + name + ".key"
+		e.files[publicKey] = file{content: []byte(certificate.PublicKey), perm: 0o644}
+		e.files[privateKey] = file{content: []byte(certificate.PrivateKey), perm: 0o600}
+
+		body := e.addResource(f, rtype, name)
+		body.SetAttributeRaw("publickey", templateFileCall(publicKey, nil))
+		body.SetAttributeRaw("privatekey", templateFileCall(privateKey, nil))
+		addImport(f, rtype, name, strconv.Itoa(certificate.ID))
+	}
+
+	e.addConfigFile("certificates.tf", f)
+
+	return nil
+}
+
+func (e *exporter) exportDNSCredentials(ctx context.Context) error {
+	const rtype = "transparentedge_certreq_dns_credential"
+
+	credentials, err := e.client.GetCRDNSCredentials(ctx)
+	if err != nil {
+		return fmt.Errorf("reading the DNS credentials: %w", err)
+	}
+
+	f := hclwrite.NewEmptyFile()
+
+	for _, credential := range credentials {
+		name := e.resourceName(rtype, credential.Alias)
+		variable := "dns_credential_" + name
+
+		// The parameters are secrets, they are kept out of the configuration
+		parameters := map[string]cty.Value{}
+		for _, key := range credential.Creds {
+			parameters[key.KeyName] = cty.StringVal(key.KeyValue)
+		}
+
+		if len(parameters) == 0 {
+			e.secrets.Body().SetAttributeValue(variable, cty.MapValEmpty(cty.String))
+		} else {
+			e.secrets.Body().SetAttributeValue(variable, cty.MapVal(parameters))
+		}
+
+		if len(f.Body().Blocks()) > 0 {
+			f.Body().AppendNewline()
+		}
+
+		variableBody := f.Body().AppendNewBlock("variable", []string{variable}).Body()
+		variableBody.SetAttributeValue("description", cty.StringVal("Parameters of the DNS credential "+strconv.Quote(credential.Alias)+"."))
+		variableBody.SetAttributeRaw("type", hclwrite.TokensForFunctionCall("map", hclwrite.TokensForIdentifier("string")))
+		variableBody.SetAttributeValue("sensitive", cty.True)
+
+		body := e.addResource(f, rtype, name)
+		body.SetAttributeValue("alias", cty.StringVal(credential.Alias))
+		body.SetAttributeTraversal("parameters", hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: variable}})
+		addImport(f, rtype, name, strconv.Itoa(credential.ID))
+
+		e.credentials[credential.ID] = reference(rtype, name, "id")
+	}
+
+	e.addConfigFile("dns_credentials.tf", f)
+
+	return nil
+}
+
+func (e *exporter) exportCertReqs(ctx context.Context) error {
+	const (
+		dnsType  = "transparentedge_certreq_dns"
+		httpType = "transparentedge_certreq_http"
+	)
+
+	dnsCertReqs, err := e.client.GetCertReqsDNS(ctx)
+	if err != nil {
+		return fmt.Errorf("reading the DNS certificate requests: %w", err)
+	}
+
+	httpCertReqs, err := e.client.GetCertReqsHTTP(ctx)
+	if err != nil {
+		return fmt.Errorf("reading the HTTP certificate requests: %w", err)
+	}
+
+	f := hclwrite.NewEmptyFile()
+
+	for _, certReq := range dnsCertReqs {
+		domains := helpers.SplitAndSort(certReq.Domains)
+		name := e.resourceName(dnsType, firstOr(domains, "certreq_dns"))
+
+		body := e.addResource(f, dnsType, name)
+		body.SetAttributeValue("domains", stringSet(domains))
+
+		if credential, found := e.credentials[certReq.Credential]; found {
+			body.SetAttributeTraversal("credential", credential)
+		} else {
+			body.SetAttributeValue("credential", cty.NumberIntVal(int64(certReq.Credential)))
+		}
+
+		addImport(f, dnsType, name, strconv.Itoa(certReq.ID))
+	}
+
+	for _, certReq := range httpCertReqs {
+		domains := helpers.SplitAndSort(certReq.CommonName + "\n" + certReq.SAN)
+		name := e.resourceName(httpType, firstOr(domains, "certreq_http"))
+
+		body := e.addResource(f, httpType, name)
+		body.SetAttributeValue("domains", stringSet(domains))
+		body.SetAttributeValue("standalone", cty.BoolVal(certReq.Standalone))
+		addImport(f, httpType, name, strconv.Itoa(certReq.ID))
+	}
+
+	e.addConfigFile("certificate_requests.tf", f)
+
+	return nil
+}
+
+// resourceName returns a unique name for a resource of rtype, derived from value: "www.example.com" is "www_example_com".
+func (e *exporter) resourceName(rtype string, value string) string {
+	base := strings.ReplaceAll(strings.ToLower(value), "*", "wildcard")
+	base = strings.Trim(notIdentifierRe.ReplaceAllString(base, "_"), "_")
+
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "_" + base
+	}
+
+	if e.names[rtype] == nil {
+		e.names[rtype] = map[string]bool{}
+	}
+
+	name := base
+	for n := 2; e.names[rtype][name]; n++ {
+		name = base + "_" + strconv.Itoa(n)
+	}
+
+	e.names[rtype][name] = true
+	e.summary[rtype]++
+
+	return name
+}
+
+// addResource appends a resource block to f and returns its body.
+func (*exporter) addResource(f *hclwrite.File, rtype string, name string) *hclwrite.Body {
+	if len(f.Body().Blocks()) > 0 {
+		f.Body().AppendNewline()
+	}
+
+	return f.Body().AppendNewBlock("resource", []string{rtype, name}).Body()
+}
+
+// addConfigFile adds f to the files to write, unless it is empty.
+func (e *exporter) addConfigFile(name string, f *hclwrite.File) {
+	if len(f.Body().Blocks()) == 0 {
+		return
+	}
+
+	header := fmt.Sprintf("# Generated by tcdn-export from the company %d.\n\n", e.companyID)
+	e.files[name] = file{content: append([]byte(header), hclwrite.Format(f.Bytes())...), perm: 0o644}
+}
+
+// write writes the files to opts.Dir, checking that none exists beforehand unless opts.Overwrite is set.
+func (e *exporter) write(opts Options) error {
+	names := slices.Sorted(maps.Keys(e.files))
+
+	if !opts.Overwrite {
+		for _, name := range names {
+			path := filepath.Join(opts.Dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("%s %w", path, ErrExists)
+			}
+		}
+	}
+
+	for _, name := range names {
+		path := filepath.Join(opts.Dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, e.files[name].content, e.files[name].perm); err != nil {
+			return err
+		}
+
+		// WriteFile keeps the permissions of the files overwritten
+		if err := os.Chmod(path, e.files[name].perm); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addImport appends to f the import block of the resource rtype.name.
+func addImport(f *hclwrite.File, rtype string, name string, id string) {
+	f.Body().AppendNewline()
+
+	body := f.Body().AppendNewBlock("import", nil).Body()
+	body.SetAttributeTraversal("to", hcl.Traversal{hcl.TraverseRoot{Name: rtype}, hcl.TraverseAttr{Name: name}})
+	body.SetAttributeValue("id", cty.StringVal(id))
+}
+
+// reference returns the traversal to the attribute of the resource rtype.name.
+func reference(rtype string, name string, attribute string) hcl.Traversal {
+	return hcl.Traversal{hcl.TraverseRoot{Name: rtype}, hcl.TraverseAttr{Name: name}, hcl.TraverseAttr{Name: attribute}}
+}
+
+// vclTemplate turns the VCL code into a template for templatefile(), replacing the vclnames of backends
+// with template variables. It returns the template and the values of the variables used,
+// or the code unchanged when it doesn't use any backend.
+func vclTemplate(code string, backends map[string]hcl.Traversal) (string, map[string]hcl.Traversal) {
+	// The template sequences of the VCL code are escaped first
+	template := strings.ReplaceAll(code, "${", "$${")
+	template = strings.ReplaceAll(template, "%{", "%%{")
+
+	variables := map[string]hcl.Traversal{}
+
+	template = vclWordRe.ReplaceAllStringFunc(template, func(word string) string {
+		backend, found := backends[word]
+		if !found {
+			return word
+		}
+
+		// The variables are named after the backend resource
+		variable := backend[1].(hcl.TraverseAttr).Name
+		variables[variable] = backend
+
+		return "${" + variable + "}"
+	})
+
+	if len(variables) == 0 {
+		return code, nil
+	}
+
+	return template, variables
+}
+
+// templateFileCall returns a file() call reading name from the module directory,
+// or a templatefile() call when there are variables.
+func templateFileCall(name string, variables map[string]hcl.Traversal) hclwrite.Tokens {
+	// "${path.module}" cannot be generated from a cty value, it would be escaped
+	src, _ := hclwrite.ParseConfig([]byte("path = \"${path.module}/"+name+"\"\n"), "", hcl.InitialPos)
+	path := src.Body().GetAttribute("path").Expr().BuildTokens(nil)
+
+	if len(variables) == 0 {
+		return hclwrite.TokensForFunctionCall("file", path)
+	}
+
+	attrs := make([]hclwrite.ObjectAttrTokens, 0, len(variables))
+	for _, variable := range slices.Sorted(maps.Keys(variables)) {
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{
+			Name:  hclwrite.TokensForIdentifier(variable),
+			Value: hclwrite.TokensForTraversal(variables[variable]),
+		})
+	}
+
+	return hclwrite.TokensForFunctionCall("templatefile", path, hclwrite.TokensForObject(attrs))
+}
+
+// stringSet returns the set of strings for a set attribute.
+func stringSet(values []string) cty.Value {
+	if len(values) == 0 {
+		return cty.SetValEmpty(cty.String)
+	}
+
+	set := make([]cty.Value, 0, len(values))
+	for _, value := range values {
+		set = append(set, cty.StringVal(value))
+	}
+
+	return cty.SetVal(set)
+}
+
+// firstOr returns the first value, or fallback if there are none.
+func firstOr(values []string, fallback string) string {
+	if len(values) == 0 {
+		return fallback
+	}
+
+	return values[0]
+}
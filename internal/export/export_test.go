@@ -0,0 +1,275 @@
+package export_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/config"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/export"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+func newTestClient(t *testing.T, srv *testserver.Server) *teclient.Client {
+	t.Helper()
+
+	host := srv.URL
+	companyID := testserver.DefaultCompanyID
+	clientID := testserver.ClientID
+	clientSecret := testserver.ClientSecret
+	insecure := false
+	auth := true
+	version := "test"
+
+	client, err := teclient.NewClient(t.Context(), &host, &companyID, &clientID, &clientSecret, &insecure, &auth, &version, teclient.ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+
+	return client
+}
+
+func testBackend(name string) teclient.NewBackendAPIModel {
+	return teclient.NewBackendAPIModel{
+		Name:         name,
+		Origin:       "origin.example.com",
+		Port:         443,
+		Ssl:          true,
+		HCHost:       "www.example.com",
+		HCPath:       "/",
+		HCStatusCode: 200,
+		HCInterval:   40,
+	}
+}
+
+// seed creates one object of each exported type.
+func seed(t *testing.T, client *teclient.Client) {
+	t.Helper()
+
+	ctx := t.Context()
+	vclName := "c300_origin"
+
+	if _, _, err := client.CreateSite(ctx, teclient.SiteNewAPIModel{URL: "www.example.com"}); err != nil {
+		t.Fatalf("CreateSite: %s", err)
+	}
+
+	for _, env := range []teclient.APIEnvironment{teclient.ProdEnv, teclient.StagingEnv} {
+		if _, err := client.CreateBackend(ctx, testBackend("origin"), env); err != nil {
+			t.Fatalf("CreateBackend: %s", err)
+		}
+	}
+
+	vclconfs := map[teclient.APIEnvironment]string{
+		teclient.ProdEnv:    "sub vcl_recv {\n  set req.backend_hint = " + vclName + ".backend();\n  set req.http.X-Origin = \"" + vclName + "_old ${host}\";\n}\n",
+		teclient.StagingEnv: "sub vcl_recv {\n  return (pass);\n}\n",
+	}
+
+	for env, code := range vclconfs {
+		if _, err := client.CreateVclconf(ctx, teclient.NewVCLConfAPIModel{VCLCode: code, Comment: "seed"}, env); err != nil {
+			t.Fatalf("CreateVclconf: %s", err)
+		}
+	}
+
+	cert, key := acctest.SelfSignedCertificate(t, "static.example.com")
+	if _, err := client.CreateCustomCertificate(ctx, teclient.SSLCustomCertificate{PublicKey: cert, PrivateKey: key}); err != nil {
+		t.Fatalf("CreateCustomCertificate: %s", err)
+	}
+
+	credential, err := client.CreateDNSCredential(ctx, teclient.NewCRDNSCredential{
+		Alias: "Route 53",
+		Creds: []teclient.NewCRDNSCreds{
+			{KeyName: "AWS_ACCESS_KEY_ID", KeyValue: "AKIAEXAMPLE"},
+			{KeyName: "AWS_SECRET_ACCESS_KEY", KeyValue: "secret"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateDNSCredential: %s", err)
+	}
+
+	if _, err := client.CreateDNSCertReq(ctx, map[string]any{"domains": "*.example.com", "credential": credential.ID, "certificate_authority": 1}); err != nil {
+		t.Fatalf("CreateDNSCertReq: %s", err)
+	}
+
+	if _, err := client.CreateHTTPCertReq(ctx, map[string]any{"domains": []string{"www.example.com"}, "standalone": false}); err != nil {
+		t.Fatalf("CreateHTTPCertReq: %s", err)
+	}
+}
+
+func readFile(t *testing.T, dir string, name string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("reading %s: %s", name, err)
+	}
+
+	return string(content)
+}
+
+func TestExport(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv)
+	seed(t, client)
+
+	dir := t.TempDir()
+
+	summary, err := export.Export(t.Context(), client, export.Options{Dir: dir})
+	if err != nil {
+		t.Fatalf("Export: %s", err)
+	}
+
+	expectedSummary := export.Summary{
+		"transparentedge_site":                   1,
+		"transparentedge_backend":                1,
+		"transparentedge_staging_backend":        1,
+		"transparentedge_custom_certificate":     1,
+		"transparentedge_certreq_dns_credential": 1,
+		"transparentedge_certreq_dns":            1,
+		"transparentedge_certreq_http":           1,
+	}
+
+	for rtype, count := range expectedSummary {
+		if summary[rtype] != count {
+			t.Errorf("summary[%s] = %d, expected %d", rtype, summary[rtype], count)
+		}
+	}
+
+	contains := map[string][]string{
+		"provider.tf":         {`source = "TransparentEdge/transparentedge"`, "company_id = 300"},
+		"sites.tf":            {`resource "transparentedge_site" "www_example_com"`, "to = transparentedge_site.www_example_com", `id = "www.example.com"`},
+		"backends.tf":         {`resource "transparentedge_backend" "origin"`, `origin       = "origin.example.com"`, "ssl          = true", `id = "origin"`},
+		"staging_backends.tf": {`resource "transparentedge_staging_backend" "origin"`},
+		"vclconfs.tf": {
+			`vclcode = templatefile("${path.module}/vcl/production.vcl", {`,
+			"origin = transparentedge_backend.origin.vclname",
+			`vclcode = file("${path.module}/vcl/staging.vcl")`,
+			`comment = "seed"`,
+			"to = transparentedge_staging_vclconf.staging",
+		},
+		"vcl/production.vcl": {"req.backend_hint = ${origin}.backend();", `"c300_origin_old $${host}"`},
+		"vcl/staging.vcl":    {"return (pass);"},
+		"certificates.tf": {
+			`publickey  = file("${path.module}/certificates/static_example_com.crt")`,
+			`privatekey = file("${path.module}/certificates/static_example_com.key")`,
+		},
+		"certificates/static_example_com.crt": {"BEGIN CERTIFICATE"},
+		"dns_credentials.tf": {
+			`variable "dns_credential_route_53"`,
+			"sensitive   = true",
+			`alias      = "Route 53"`,
+			"parameters = var.dns_credential_route_53",
+		},
+		"secrets.auto.tfvars": {`AWS_SECRET_ACCESS_KEY = "secret"`},
+		"certificate_requests.tf": {
+			`resource "transparentedge_certreq_dns" "wildcard_example_com"`,
+			"credential = transparentedge_certreq_dns_credential.route_53.id",
+			`resource "transparentedge_certreq_http" "www_example_com"`,
+			"standalone = false",
+		},
+	}
+
+	for name, fragments := range contains {
+		content := readFile(t, dir, name)
+
+		for _, fragment := range fragments {
+			if !strings.Contains(content, fragment) {
+				t.Errorf("%s does not contain %q:\n%s", name, fragment, content)
+			}
+		}
+	}
+
+	// The certificates issued for the requests are managed by them
+	if certificates := readFile(t, dir, "certificates.tf"); strings.Count(certificates, "resource ") != 1 {
+		t.Errorf("certificates.tf has more than the custom certificate:\n%s", certificates)
+	}
+
+	for _, name := range []string{"secrets.auto.tfvars", "certificates/static_example_com.key"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("stat %s: %s", name, err)
+		}
+
+		if info.Mode().Perm() != 0o600 {
+			t.Errorf("%s permissions are %s, expected -rw-------", name, info.Mode().Perm())
+		}
+	}
+}
+
+func TestExport_existingFiles(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv)
+	seed(t, client)
+
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "backends.tf"), []byte("# mine\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := export.Export(t.Context(), client, export.Options{Dir: dir})
+	if !errors.Is(err, export.ErrExists) {
+		t.Fatalf("Export error = %v, expected ErrExists", err)
+	}
+
+	// Nothing is written when a file exists
+	if _, err := os.Stat(filepath.Join(dir, "sites.tf")); !os.IsNotExist(err) {
+		t.Errorf("sites.tf was written: %v", err)
+	}
+
+	if _, err := export.Export(t.Context(), client, export.Options{Dir: dir, Overwrite: true}); err != nil {
+		t.Fatalf("Export with Overwrite: %s", err)
+	}
+
+	if backends := readFile(t, dir, "backends.tf"); !strings.Contains(backends, "transparentedge_backend") {
+		t.Errorf("backends.tf was not overwritten:\n%s", backends)
+	}
+}
+
+// TestAccExport applies the exported configuration, importing every object without changing it.
+func TestAccExport(t *testing.T) {
+	srv := acctest.NewServer(t)
+	client := newTestClient(t, srv)
+	seed(t, client)
+
+	dir := t.TempDir()
+
+	if _, err := export.Export(t.Context(), client, export.Options{Dir: dir}); err != nil {
+		t.Fatalf("Export: %s", err)
+	}
+
+	// The tests run the provider in-process instead of the one of the registry
+	if err := os.Remove(filepath.Join(dir, "provider.tf")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The configuration is copied without its subdirectories, the VCL and certificate files are read from dir
+	for _, name := range []string{"vclconfs.tf", "certificates.tf"} {
+		content := strings.ReplaceAll(readFile(t, dir, name), "${path.module}", dir)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				ConfigDirectory: config.StaticDirectory(dir),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_backend.origin", plancheck.ResourceActionNoop),
+						plancheck.ExpectResourceAction("transparentedge_vclconf.production", plancheck.ResourceActionNoop),
+						plancheck.ExpectResourceAction("transparentedge_certreq_dns.wildcard_example_com", plancheck.ResourceActionNoop),
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/vcl"
+)
+
+// ReferencedBackendsAttribute returns the referenced_backends attribute of the VCL configuration resources.
+func ReferencedBackendsAttribute() rschema.SetAttribute {
+	return rschema.SetAttribute{
+		Computed:            true,
+		ElementType:         types.StringType,
+		Description:         "Names of the backends used by 'vclcode', as in their 'vclname' attribute: 'c{company_id}_{name}'.",
+		MarkdownDescription: "Names of the backends used by `vclcode`, as in their `vclname` attribute: `c{company_id}_{name}`.",
+	}
+}
+
+// ReferencedBackendsValue returns the names of the backends used by the VCL code, unknown until the code is known.
+func ReferencedBackendsValue(code basetypes.StringValue) types.Set {
+	if code.IsUnknown() {
+		return types.SetUnknown(types.StringType)
+	}
+
+	references := vcl.BackendReferences(code.ValueString())
+
+	names := make([]attr.Value, 0, len(references))
+	for _, reference := range references {
+		names = append(names, types.StringValue(reference.VCLName))
+	}
+
+	return types.SetValueMust(types.StringType, names)
+}
+
+// CheckReferencedBackends adds an error on vclcode for each backend of the company that code uses,
+// and that doesn't exist in environment. It's meant to run right before uploading the code,
+// when the backends it depends on were already created.
+func CheckReferencedBackends(ctx context.Context, client *teclient.Client, environment teclient.APIEnvironment, code string, diags *diag.Diagnostics) {
+	reportMissingBackends(ctx, client, environment, code, diags, diags.AddAttributeError)
+}
+
+// WarnReferencedBackends is CheckReferencedBackends while planning: the backends created in the same apply
+// don't exist yet, so the missing ones are only warnings. The ones destroyed in the same plan still pass it.
+func WarnReferencedBackends(ctx context.Context, client *teclient.Client, environment teclient.APIEnvironment, code string, diags *diag.Diagnostics) {
+	reportMissingBackends(ctx, client, environment, code, diags, diags.AddAttributeWarning)
+}
+
+// reportMissingBackends calls report for each backend of the company that code uses and that doesn't exist in environment.
+func reportMissingBackends(
+	ctx context.Context, client *teclient.Client, environment teclient.APIEnvironment, code string,
+	diags *diag.Diagnostics, report func(path.Path, string, string),
+) {
+	if client == nil {
+		return
+	}
+
+	companyID := client.CompanyIDFor(ctx)
+
+	var references []vcl.BackendReference
+
+	// The backends of other companies can't be listed
+	for _, reference := range vcl.BackendReferences(code) {
+		if reference.CompanyID == companyID {
+			references = append(references, reference)
+		}
+	}
+
+	if len(references) == 0 {
+		return
+	}
+
+	backends, err := client.GetBackends(ctx, environment)
+	if err != nil {
+		diags.AddError("Unable to check the backends used by the VCL code", err.Error())
+
+		return
+	}
+
+	existing := make(map[string]bool, len(backends))
+	for _, backend := range backends {
+		existing["c"+strconv.Itoa(backend.Company)+"_"+backend.Name] = true
+	}
+
+	envName, resourceType := "production", "transparentedge_backend"
+	if environment == teclient.StagingEnv {
+		envName, resourceType = "staging", "transparentedge_staging_backend"
+	}
+
+	for _, reference := range references {
+		if existing[reference.VCLName] {
+			continue
+		}
+
+		report(
+			path.Root("vclcode"),
+			"Unknown backend",
+			fmt.Sprintf("The backend '%s' used at %s of the VCL code doesn't exist in %s.", reference.VCLName, reference.Pos, envName)+
+				fmt.Sprintf(" If it's created in the same apply, use the 'vclname' attribute of its resource, i.e: ${%s.example.vclname},", resourceType)+
+				" or add the resource to 'depends_on', so Terraform creates the backend before uploading the code.",
+		)
+	}
+}
@@ -0,0 +1,89 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	dschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// CompanyIDResourceAttribute returns the company_id attribute of the resources, object names what the resource manages.
+func CompanyIDResourceAttribute(object string) rschema.Int64Attribute {
+	return rschema.Int64Attribute{
+		Optional: true,
+		Computed: true,
+		PlanModifiers: []planmodifier.Int64{
+			int64planmodifier.UseStateForUnknown(),
+			int64planmodifier.RequiresReplace(),
+		},
+		Description:         "ID of the company that owns the " + object + ", defaults to the provider 'company_id'. Changing it forces a new resource.",
+		MarkdownDescription: "ID of the company that owns the " + object + ", defaults to the provider `company_id`. Changing it forces a new resource.",
+	}
+}
+
+// CompanyIDDataSourceAttribute returns the company_id attribute of the data sources, object names what the data source reads.
+func CompanyIDDataSourceAttribute(object string) dschema.Int64Attribute {
+	return dschema.Int64Attribute{
+		Optional:            true,
+		Computed:            true,
+		Description:         "ID of the company to read the " + object + " from, defaults to the provider 'company_id'.",
+		MarkdownDescription: "ID of the company to read the " + object + " from, defaults to the provider `company_id`.",
+	}
+}
+
+// CompanyContext returns ctx targeting the company of companyID, or the provider company when it is not set.
+func CompanyContext(ctx context.Context, companyID types.Int64) context.Context {
+	if companyID.IsNull() || companyID.IsUnknown() {
+		return ctx
+	}
+
+	return teclient.WithCompanyID(ctx, int(companyID.ValueInt64()))
+}
+
+// SplitImportID splits an import ID in the form "company_id/id" in its company and the rest of the ID.
+// The company is 0 when the ID has no company prefix.
+func SplitImportID(importID string) (int, string, error) {
+	prefix, id, found := strings.Cut(importID, "/")
+	if !found {
+		return 0, importID, nil
+	}
+
+	companyID, err := strconv.Atoi(prefix)
+	if err != nil || companyID < 1 {
+		return 0, "", fmt.Errorf("the company in %q must be a number greater than 0, use 'company_id/id'", importID)
+	}
+
+	return companyID, id, nil
+}
+
+// ImportCompanyID sets company_id in the imported state when the import ID has a company prefix,
+// and returns the ID without it. It returns false after adding an error to the diagnostics.
+func ImportCompanyID(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) (string, bool) {
+	companyID, id, err := SplitImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid identifier", err.Error())
+
+		return "", false
+	}
+
+	if companyID > 0 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("company_id"), int64(companyID))...)
+	}
+
+	return id, !resp.Diagnostics.HasError()
+}
+
+// CompanyIDValue returns the company targeted by ctx, to store it in the state.
+func CompanyIDValue(ctx context.Context, client *teclient.Client) types.Int64 {
+	return types.Int64Value(int64(client.CompanyIDFor(ctx)))
+}
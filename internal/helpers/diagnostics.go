@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// AddAPIError reports err in diags.
+// Validation errors of the API fields listed in attributes are attached to the matching attribute path,
+// so Terraform points at the offending argument. Everything else is reported as a general error
+// whose detail starts with detail.
+func AddAPIError(diags *diag.Diagnostics, summary string, detail string, err error, attributes map[string]path.Path) {
+	apiErr, ok := teclient.AsAPIError(err)
+	if !ok || len(apiErr.FieldErrors) == 0 {
+		diags.AddError(summary, detail+": "+err.Error())
+
+		return
+	}
+
+	unmapped := false
+
+	for _, field := range slices.Sorted(maps.Keys(apiErr.FieldErrors)) {
+		attrPath, found := attributes[field]
+		if !found {
+			// nested errors, i.e: "creds.0.hook_key_value", are reported on the top level attribute
+			attrPath, found = attributes[strings.SplitN(field, ".", 2)[0]]
+		}
+
+		if !found {
+			unmapped = true
+
+			continue
+		}
+
+		diags.AddAttributeError(attrPath, summary, detail+": "+strings.Join(apiErr.FieldErrors[field], " "))
+	}
+
+	if unmapped || apiErr.Message != "" || apiErr.Detail != "" {
+		diags.AddError(summary, detail+": "+err.Error())
+	}
+}
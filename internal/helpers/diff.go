@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/vcl"
+)
+
+// DefaultDiffContextLines is the number of unchanged lines around each change of the VCL diff,
+// when diff_context_lines is not set.
+const DefaultDiffContextLines = 3
+
+// DiffContextLinesAttribute returns the diff_context_lines attribute of the VCL configuration resources.
+func DiffContextLinesAttribute() rschema.Int64Attribute {
+	return rschema.Int64Attribute{
+		Optional: true,
+		Validators: []validator.Int64{
+			int64validator.AtLeast(-1),
+		},
+		Description: "Number of unchanged lines shown around each change of 'vclcode' in the unified diff that 'terraform plan' reports as a warning." +
+			" Defaults to 3, set it to -1 to disable the warning.",
+		MarkdownDescription: "Number of unchanged lines shown around each change of `vclcode` in the unified diff that `terraform plan` reports as a warning." +
+			" Defaults to `3`, set it to `-1` to disable the warning.",
+	}
+}
+
+// AddVCLDiffWarning adds a warning on vclcode with the unified diff from the current to the planned code,
+// once both are normalized as in VCLSemanticEquals. Nothing is added when contextLines is -1 or the codes are equal.
+func AddVCLDiffWarning(diags *diag.Diagnostics, current, planned string, contextLines types.Int64) {
+	lines := DefaultDiffContextLines
+	if !contextLines.IsNull() && !contextLines.IsUnknown() {
+		lines = int(contextLines.ValueInt64())
+	}
+
+	if lines < 0 {
+		return
+	}
+
+	diff := vcl.Diff("vclcode (current)", "vclcode (planned)", normalizeVCL(current)+"\n", normalizeVCL(planned)+"\n", lines)
+	if diff == "" {
+		return
+	}
+
+	// Terraform doesn't wrap the indented lines of the diagnostics
+	var detail strings.Builder
+	for line := range strings.Lines(diff) {
+		detail.WriteString("  " + line)
+	}
+
+	diags.AddAttributeWarning(
+		path.Root("vclcode"),
+		"VCL code changes",
+		"Applying this plan uploads a new VCL configuration with these changes:\n\n"+detail.String()+
+			"\nSet 'diff_context_lines' to -1 to disable this warning.",
+	)
+}
@@ -37,6 +37,15 @@ func GetEnvBool(key string, fallback bool) (bool, error) {
 	return v, nil
 }
 
+// ReadPEMOrFile returns value itself when it holds PEM encoded data, otherwise the content of the file it points to.
+func ReadPEMOrFile(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN ") {
+		return []byte(value), nil
+	}
+
+	return os.ReadFile(value)
+}
+
 func SplitAndSort(input string) []string {
 	// Split the input string
 	words := strings.FieldsFunc(input, func(r rune) bool {
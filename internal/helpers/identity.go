@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+)
+
+// CompanyIDIdentityAttribute returns the company_id attribute of the resource identities.
+// It may be left out when importing, the provider company is used then.
+func CompanyIDIdentityAttribute() identityschema.Int64Attribute {
+	return identityschema.Int64Attribute{
+		OptionalForImport: true,
+		Description:       "ID of the company that owns the object, defaults to the provider 'company_id' when importing.",
+	}
+}
+
+// ImportFromIdentity copies every attribute of the identity given in an import block
+// to the attribute with the same name of the imported state.
+func ImportFromIdentity(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	for name := range req.Identity.Schema.GetAttributes() {
+		var value attr.Value
+
+		resp.Diagnostics.Append(req.Identity.GetAttribute(ctx, path.Root(name), &value)...)
+
+		if value != nil && !value.IsNull() {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(name), value)...)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	lschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ListItem is an object found by a list resource.
+type ListItem struct {
+	// DisplayName is shown by 'terraform query' next to the identity.
+	DisplayName string
+	// Identity and Resource are set to the identity and the state of the result.
+	Identity any
+	Resource any
+}
+
+// CompanyIDListAttribute returns the company_id attribute of the list resources.
+func CompanyIDListAttribute(object string) lschema.Int64Attribute {
+	return lschema.Int64Attribute{
+		Optional:            true,
+		Description:         fmt.Sprintf("ID of the company that owns the %s, defaults to the provider 'company_id'.", object),
+		MarkdownDescription: fmt.Sprintf("ID of the company that owns the %s, defaults to the provider `company_id`.", object),
+	}
+}
+
+// ListRegexp compiles the optional regular expression of the list filter attribute,
+// it returns nil when the filter is not set.
+func ListRegexp(attribute string, value types.String, diags *diag.Diagnostics) *regexp.Regexp {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	re, err := regexp.Compile(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Invalid regular expression",
+			fmt.Sprintf("The '%s' filter is not a valid regular expression: %s", attribute, err),
+		)
+	}
+
+	return re
+}
+
+// ListResults returns the results of a list request, stopping at the limit set by Terraform.
+// The resource state of each item is only included when Terraform asks for it.
+func ListResults[T any](ctx context.Context, req list.ListRequest, items []T, toListItem func(T) (ListItem, diag.Diagnostics)) iter.Seq[list.ListResult] {
+	return func(push func(list.ListResult) bool) {
+		for n, item := range items {
+			if req.Limit > 0 && int64(n) >= req.Limit {
+				return
+			}
+
+			result := req.NewListResult(ctx)
+
+			listItem, diags := toListItem(item)
+			result.Diagnostics.Append(diags...)
+
+			if !result.Diagnostics.HasError() {
+				result.DisplayName = listItem.DisplayName
+				result.Diagnostics.Append(result.Identity.Set(ctx, listItem.Identity)...)
+
+				if req.IncludeResource {
+					result.Diagnostics.Append(result.Resource.Set(ctx, listItem.Resource)...)
+				}
+			}
+
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
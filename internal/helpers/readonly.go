@@ -0,0 +1,36 @@
+package helpers
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// CheckReadOnlyPlan adds an error to the diagnostics when the client is in read-only mode and the plan
+// creates, updates or deletes the resource, so the change is refused at plan time instead of failing the apply.
+// object names what the resource manages.
+func CheckReadOnlyPlan(client *teclient.Client, object string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// The client is nil while the provider configuration is unknown
+	if client == nil || !client.ReadOnly() {
+		return
+	}
+
+	var action string
+
+	switch {
+	case req.Plan.Raw.IsNull():
+		action = "deleted"
+	case req.State.Raw.IsNull():
+		action = "created"
+	case !req.Plan.Raw.Equal(req.State.Raw):
+		action = "updated"
+	default:
+		return
+	}
+
+	resp.Diagnostics.AddError(
+		"Change refused in read-only mode",
+		"The "+object+" would be "+action+", but the provider is configured with 'read_only' (or TCDN_READ_ONLY) "+
+			"and must not change the CDN configuration. Disable the read-only mode to apply this change.",
+	)
+}
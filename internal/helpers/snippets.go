@@ -0,0 +1,184 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/vcl"
+)
+
+// VCLSnippet is a snippet of the VCL configuration resources.
+type VCLSnippet struct {
+	Name       types.String `tfsdk:"name"`
+	Subroutine types.String `tfsdk:"subroutine"`
+	Priority   types.Int64  `tfsdk:"priority"`
+	Content    types.String `tfsdk:"content"`
+}
+
+// SnippetsBlock returns the snippets block of the VCL configuration resources.
+func SnippetsBlock() rschema.ListNestedBlock {
+	return rschema.ListNestedBlock{
+		Description: "Pieces of VCL code assembled into 'vclcode', instead of setting it." +
+			" The snippets outside subroutines go first, then the custom subroutines sorted by their name, and the builtin subroutines in the order Varnish calls them." +
+			" Marker comments with the name of the snippet, if set, surround each snippet in the assembled code.",
+		MarkdownDescription: "Pieces of VCL code assembled into `vclcode`, instead of setting it." +
+			" The snippets outside subroutines go first, then the custom subroutines sorted by their name, and the builtin subroutines in the order Varnish calls them." +
+			" Marker comments with the `name` of the snippet, if set, surround each snippet in the assembled code.",
+		NestedObject: rschema.NestedBlockObject{
+			Attributes: map[string]rschema.Attribute{
+				"name": rschema.StringAttribute{
+					Optional: true,
+					Validators: []validator.String{
+						stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9_.-]+$`), "must only contain letters, digits, '_', '.' and '-'"),
+					},
+					Description:         "Unique name of the snippet, shown in the marker comments of the assembled code.",
+					MarkdownDescription: "Unique name of the snippet, shown in the marker comments of the assembled code.",
+				},
+				"subroutine": rschema.StringAttribute{
+					Optional: true,
+					Validators: []validator.String{
+						stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`), "must be the name of a subroutine"),
+					},
+					Description: "Subroutine the snippet is added to, i.e: vcl_recv, vcl_backend_fetch, vcl_backend_response or vcl_deliver." +
+						" Other names declare custom subroutines. Leave it unset for the code outside subroutines, i.e: imports or ACLs.",
+					MarkdownDescription: "Subroutine the snippet is added to, i.e: `vcl_recv`, `vcl_backend_fetch`, `vcl_backend_response` or `vcl_deliver`." +
+						" Other names declare custom subroutines. Leave it unset for the code outside subroutines, i.e: imports or ACLs.",
+				},
+				"priority": rschema.Int64Attribute{
+					Optional:            true,
+					Computed:            true,
+					Default:             int64default.StaticInt64(0),
+					Description:         "Order of the snippet in its subroutine, lower first. The snippets with the same priority keep their order in the list. Defaults to 0.",
+					MarkdownDescription: "Order of the snippet in its subroutine, lower first. The snippets with the same priority keep their order in the list. Defaults to `0`.",
+				},
+				"content": rschema.StringAttribute{
+					Required:            true,
+					Description:         "VCL code of the snippet, without the declaration of its subroutine.",
+					MarkdownDescription: "VCL code of the snippet, without the declaration of its subroutine.",
+				},
+			},
+		},
+	}
+}
+
+// VCLSnippets returns the snippets of the list, false while any of them is unknown.
+func VCLSnippets(ctx context.Context, list types.List, diags *diag.Diagnostics) ([]vcl.Snippet, bool) {
+	if list.IsUnknown() {
+		return nil, false
+	}
+
+	var models []VCLSnippet
+
+	diags.Append(list.ElementsAs(ctx, &models, false)...)
+
+	snippets := make([]vcl.Snippet, 0, len(models))
+
+	for _, model := range models {
+		if model.Name.IsUnknown() || model.Subroutine.IsUnknown() || model.Priority.IsUnknown() || model.Content.IsUnknown() {
+			return nil, false
+		}
+
+		snippets = append(snippets, vcl.Snippet{
+			Name:       model.Name.ValueString(),
+			Subroutine: model.Subroutine.ValueString(),
+			Priority:   model.Priority.ValueInt64(),
+			Content:    model.Content.ValueString(),
+		})
+	}
+
+	return snippets, !diags.HasError()
+}
+
+// AssembleSnippets returns the VCL code assembled from the snippets, unknown until all of them are known.
+func AssembleSnippets(ctx context.Context, list types.List, diags *diag.Diagnostics) basetypes.StringValue {
+	snippets, known := VCLSnippets(ctx, list, diags)
+	if !known {
+		return types.StringUnknown()
+	}
+
+	return types.StringValue(vcl.Assemble(snippets).Code)
+}
+
+// ValidateVCLSource checks that exactly one of vclcode or snippets is set, that the names of the snippets are unique,
+// and reports the syntax errors of the assembled snippets on the snippet with the error.
+func ValidateVCLSource(ctx context.Context, code basetypes.StringValue, list types.List, diags *diag.Diagnostics) {
+	if list.IsUnknown() {
+		return
+	}
+
+	// A list block without blocks is empty, not null
+	hasSnippets := len(list.Elements()) > 0
+
+	switch {
+	case code.IsNull() && !hasSnippets:
+		diags.AddAttributeError(
+			path.Root("vclcode"),
+			"Missing VCL code",
+			"Either 'vclcode' or 'snippets' must be set.",
+		)
+
+		return
+	case !code.IsNull() && hasSnippets:
+		diags.AddAttributeError(
+			path.Root("vclcode"),
+			"Conflicting VCL code",
+			"Only one of 'vclcode' or 'snippets' can be set, 'vclcode' is assembled from the snippets when they're set.",
+		)
+
+		return
+	case !hasSnippets:
+		return
+	}
+
+	snippets, known := VCLSnippets(ctx, list, diags)
+	if !known {
+		return
+	}
+
+	names := make(map[string]int)
+
+	for n, snippet := range snippets {
+		if snippet.Name == "" {
+			continue
+		}
+
+		if first, found := names[snippet.Name]; found {
+			diags.AddAttributeError(path.Root("snippets").AtListIndex(n).AtName("name"), "Duplicate snippet name",
+				fmt.Sprintf("The name '%s' is already used by snippets[%d].", snippet.Name, first))
+
+			continue
+		}
+
+		names[snippet.Name] = n
+	}
+
+	assembly := vcl.Assemble(snippets)
+
+	for _, err := range vcl.Validate(assembly.Code) {
+		located, found := assembly.Locate(err.Pos)
+
+		switch {
+		case !found:
+			diags.AddAttributeError(path.Root("snippets"), "Invalid VCL code",
+				fmt.Sprintf("Syntax error at %s of the code assembled from the snippets: %s", err.Pos, err.Message))
+		case located.Declaration:
+			diags.AddAttributeError(path.Root("snippets").AtListIndex(located.Snippet).AtName("subroutine"), "Invalid VCL code", err.Message)
+		case located.Pos.Line == 0:
+			diags.AddAttributeError(path.Root("snippets").AtListIndex(located.Snippet).AtName("content"), "Invalid VCL code",
+				"Syntax error at the end of the snippet: "+err.Message)
+		default:
+			diags.AddAttributeError(path.Root("snippets").AtListIndex(located.Snippet).AtName("content"), "Invalid VCL code",
+				fmt.Sprintf("Syntax error at %s of the snippet: %s", located.Pos, err.Message))
+		}
+	}
+}
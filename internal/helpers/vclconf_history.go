@@ -0,0 +1,261 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	dschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// VCLConfHistory maps the schema of the VCL configuration history data sources.
+type VCLConfHistory struct {
+	CompanyID    types.Int64           `tfsdk:"company_id"`
+	Limit        types.Int64           `tfsdk:"limit"`
+	Since        types.String          `tfsdk:"since"`
+	UploadedBy   types.String          `tfsdk:"uploaded_by"`
+	CommentRegex types.String          `tfsdk:"comment_regex"`
+	IncludeCode  types.Bool            `tfsdk:"include_code"`
+	VCLConfs     []VCLConfHistoryEntry `tfsdk:"vclconfs"`
+}
+
+// VCLConfHistoryEntry is a version of the VCL configuration history.
+type VCLConfHistoryEntry struct {
+	ID             types.Int64    `tfsdk:"id"`
+	Company        types.Int64    `tfsdk:"company"`
+	UploadDate     types.String   `tfsdk:"uploaddate"`
+	ProductionDate types.String   `tfsdk:"productiondate"`
+	CreatorUser    VCLConfCreator `tfsdk:"creator_user"`
+	Comment        types.String   `tfsdk:"comment"`
+	Validated      types.Bool     `tfsdk:"validated"`
+	Active         types.Bool     `tfsdk:"active"`
+	Deployed       types.Bool     `tfsdk:"deployed"`
+	VCLCode        types.String   `tfsdk:"vclcode"`
+}
+
+// VCLConfCreator is the user that uploaded a VCL configuration.
+type VCLConfCreator struct {
+	ID        types.Int64  `tfsdk:"id"`
+	Username  types.String `tfsdk:"username"`
+	Email     types.String `tfsdk:"email"`
+	FirstName types.String `tfsdk:"first_name"`
+	LastName  types.String `tfsdk:"last_name"`
+}
+
+// VCLConfHistorySchema returns the schema of the VCL configuration history data source of environment, i.e: "staging".
+func VCLConfHistorySchema(environment string) dschema.Schema {
+	computedString := func(description string) dschema.StringAttribute {
+		return dschema.StringAttribute{Computed: true, Description: description, MarkdownDescription: description}
+	}
+
+	return dschema.Schema{
+		Description: fmt.Sprintf("History of the %s VCL configurations, newest first.", environment),
+		MarkdownDescription: fmt.Sprintf("History of the %s VCL configurations, newest first.", environment) +
+			" The filters are applied while the history is fetched, so the pages older than `since` or past `limit` are not requested.",
+
+		Attributes: map[string]dschema.Attribute{
+			"company_id": CompanyIDDataSourceAttribute("VCL configuration history"),
+			"limit": dschema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				Description:         "Maximum number of configurations to return, after applying the other filters. The whole history is returned when it's not set.",
+				MarkdownDescription: "Maximum number of configurations to return, after applying the other filters. The whole history is returned when it's not set.",
+			},
+			"since": dschema.StringAttribute{
+				Optional:            true,
+				Description:         "Only return the configurations uploaded at or after this date, in RFC 3339 format (i.e: 2024-01-31T10:00:00Z) or as a day (i.e: 2024-01-31). The configurations whose upload date is not valid are skipped.",
+				MarkdownDescription: "Only return the configurations uploaded at or after this date, in RFC 3339 format (i.e: `2024-01-31T10:00:00Z`) or as a day (i.e: `2024-01-31`). The configurations whose upload date is not valid are skipped.",
+			},
+			"uploaded_by": dschema.StringAttribute{
+				Optional:            true,
+				Description:         "Only return the configurations uploaded by the user with this email or username, ignoring the case.",
+				MarkdownDescription: "Only return the configurations uploaded by the user with this email or username, ignoring the case.",
+			},
+			"comment_regex": dschema.StringAttribute{
+				Optional:            true,
+				Description:         "Only return the configurations whose comment matches this regular expression (RE2 syntax).",
+				MarkdownDescription: "Only return the configurations whose comment matches this regular expression ([RE2 syntax](https://github.com/google/re2/wiki/Syntax)).",
+			},
+			"include_code": dschema.BoolAttribute{
+				Optional:            true,
+				Description:         "Whether to return the VCL code of each configuration in 'vclcode', defaults to false.",
+				MarkdownDescription: "Whether to return the VCL code of each configuration in `vclcode`, defaults to `false`.",
+			},
+			"vclconfs": dschema.ListNestedAttribute{
+				Computed:            true,
+				Description:         "Configurations of the history matching the filters, newest first.",
+				MarkdownDescription: "Configurations of the history matching the filters, newest first.",
+				NestedObject: dschema.NestedAttributeObject{
+					Attributes: map[string]dschema.Attribute{
+						"id": dschema.Int64Attribute{
+							Computed:            true,
+							Description:         "ID of the VCL Config.",
+							MarkdownDescription: "ID of the VCL Config.",
+						},
+						"company": dschema.Int64Attribute{
+							Computed:            true,
+							Description:         "Company ID that owns this VCL config.",
+							MarkdownDescription: "Company ID that owns this VCL config.",
+						},
+						"uploaddate":     computedString("Date when the configuration was uploaded."),
+						"productiondate": computedString("Date when the configuration was fully applied in the CDN, empty until then."),
+						"creator_user": dschema.SingleNestedAttribute{
+							Computed:            true,
+							Description:         "User that uploaded the configuration.",
+							MarkdownDescription: "User that uploaded the configuration.",
+							Attributes: map[string]dschema.Attribute{
+								"id": dschema.Int64Attribute{
+									Computed:            true,
+									Description:         "ID of the user.",
+									MarkdownDescription: "ID of the user.",
+								},
+								"username":   computedString("Username of the user."),
+								"email":      computedString("Email of the user."),
+								"first_name": computedString("First name of the user."),
+								"last_name":  computedString("Last name of the user."),
+							},
+						},
+						"comment": computedString("Comment describing the changes introduced by this configuration."),
+						"validated": dschema.BoolAttribute{
+							Computed:            true,
+							Description:         "Whether the configuration compiled.",
+							MarkdownDescription: "Whether the configuration compiled.",
+						},
+						"active": dschema.BoolAttribute{
+							Computed:            true,
+							Description:         "Whether this is the current configuration.",
+							MarkdownDescription: "Whether this is the current configuration.",
+						},
+						"deployed": dschema.BoolAttribute{
+							Computed:            true,
+							Description:         "Whether the configuration reached the CDN edge nodes.",
+							MarkdownDescription: "Whether the configuration reached the CDN edge nodes.",
+						},
+						"vclcode": dschema.StringAttribute{
+							Computed:            true,
+							Description:         "Verbatim of the VCL code, only set when 'include_code' is true.",
+							MarkdownDescription: "Verbatim of the VCL code, only set when `include_code` is true.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ReadVCLConfHistory fills the configurations of history with the ones of environment matching its filters.
+// The history is walked newest first, so it stops at the first configuration older than since.
+// The configurations whose upload date can't be parsed are skipped when since is set.
+func ReadVCLConfHistory(ctx context.Context, client *teclient.Client, environment teclient.APIEnvironment, history *VCLConfHistory, diags *diag.Diagnostics) {
+	since, filterSince := parseSince(history.Since, diags)
+	commentRe := ListRegexp("comment_regex", history.CommentRegex, diags)
+
+	if diags.HasError() {
+		return
+	}
+
+	uploadedBy := strings.ToLower(history.UploadedBy.ValueString())
+	limit := int(history.Limit.ValueInt64())
+
+	history.VCLConfs = []VCLConfHistoryEntry{}
+
+	var skipped []string
+
+	for conf, err := range client.IterVCLConfs(ctx, environment, 0) {
+		if err != nil {
+			diags.AddError("Unable to read the VCL configuration history", err.Error())
+
+			return
+		}
+
+		if filterSince {
+			uploaded, err := time.Parse(time.RFC3339, conf.UploadDate)
+			if err != nil {
+				// Without a date it can't be told whether the configuration is older than since,
+				// but it doesn't end the history either
+				skipped = append(skipped, fmt.Sprintf("%d (%s)", conf.ID, conf.UploadDate))
+
+				continue
+			}
+
+			if uploaded.Before(since) {
+				break
+			}
+		}
+
+		if uploadedBy != "" && uploadedBy != strings.ToLower(conf.CreatorUser.Email) && uploadedBy != strings.ToLower(conf.CreatorUser.Username) {
+			continue
+		}
+
+		if commentRe != nil && !commentRe.MatchString(conf.Comment) {
+			continue
+		}
+
+		entry := VCLConfHistoryEntry{
+			ID:             types.Int64Value(int64(conf.ID)),
+			Company:        types.Int64Value(int64(conf.Company)),
+			UploadDate:     types.StringValue(conf.UploadDate),
+			ProductionDate: types.StringValue(conf.ProductionDate),
+			CreatorUser: VCLConfCreator{
+				ID:        types.Int64Value(int64(conf.CreatorUser.ID)),
+				Username:  types.StringValue(conf.CreatorUser.Username),
+				Email:     types.StringValue(conf.CreatorUser.Email),
+				FirstName: types.StringValue(conf.CreatorUser.FirstName),
+				LastName:  types.StringValue(conf.CreatorUser.LastName),
+			},
+			Comment:   types.StringValue(conf.Comment),
+			Validated: types.BoolValue(conf.Validated),
+			Active:    types.BoolValue(conf.Active),
+			Deployed:  types.BoolValue(conf.Deployed),
+			VCLCode:   types.StringNull(),
+		}
+
+		if history.IncludeCode.ValueBool() {
+			entry.VCLCode = types.StringValue(conf.VCLCode)
+		}
+
+		history.VCLConfs = append(history.VCLConfs, entry)
+
+		if limit > 0 && len(history.VCLConfs) >= limit {
+			break
+		}
+	}
+
+	if len(skipped) > 0 {
+		diags.AddWarning(
+			"Configurations with an invalid upload date",
+			fmt.Sprintf("The 'since' filter skipped the configurations %s, their upload date is not in RFC 3339 format.", strings.Join(skipped, ", ")),
+		)
+	}
+}
+
+// parseSince parses the since filter, it returns false when the filter is not set.
+func parseSince(value types.String, diags *diag.Diagnostics) (time.Time, bool) {
+	if value.IsNull() || value.IsUnknown() {
+		return time.Time{}, false
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if since, err := time.Parse(layout, value.ValueString()); err == nil {
+			return since, true
+		}
+	}
+
+	diags.AddAttributeError(
+		path.Root("since"),
+		"Invalid date",
+		fmt.Sprintf("The 'since' filter '%s' is not a date in RFC 3339 format, i.e: 2024-01-31T10:00:00Z, or a day, i.e: 2024-01-31.", value.ValueString()),
+	)
+
+	return time.Time{}, false
+}
@@ -0,0 +1,79 @@
+package profiles
+
+// Keys of the credentials, in the profiles and in the provider configuration.
+const (
+	ClientIDKey          = "client_id"
+	ClientSecretKey      = "client_secret"
+	AccessTokenKey       = "access_token"
+	CredentialProcessKey = "credential_process"
+)
+
+// Credentials are the authentication settings read from a source: the provider configuration,
+// the environment or a profile. An empty value is not set.
+type Credentials struct {
+	ClientID          string
+	ClientSecret      string
+	AccessToken       string
+	CredentialProcess string
+}
+
+// TokenAuth reports whether the credentials use an access token or a credential process
+// instead of the client credentials.
+func (c Credentials) TokenAuth() bool {
+	return c.AccessToken != "" || c.CredentialProcess != ""
+}
+
+// IsZero reports whether no credential is set.
+func (c Credentials) IsZero() bool {
+	return c == Credentials{}
+}
+
+// Credentials returns the credentials set in the profile, none for a nil profile.
+func (p *Profile) Credentials() Credentials {
+	if p == nil {
+		return Credentials{}
+	}
+
+	return Credentials{
+		ClientID:          p.values[ClientIDKey],
+		ClientSecret:      p.values[ClientSecretKey],
+		AccessToken:       p.values[AccessTokenKey],
+		CredentialProcess: p.values[CredentialProcessKey],
+	}
+}
+
+// ResolveCredentials merges the credentials of sources, sorted from the highest precedence.
+// The first source setting any credential chooses the authentication mode:
+// with an access token or a credential process, both are taken from that source only;
+// with client credentials, the ones it lacks are completed from the next sources and their tokens are ignored.
+// It also returns the index in sources of each credential set, by key.
+func ResolveCredentials(sources ...Credentials) (Credentials, map[string]int) {
+	var resolved Credentials
+
+	from := map[string]int{}
+
+	set := func(key string, value *string, source int, sourceValue string) {
+		if *value == "" && sourceValue != "" {
+			*value = sourceValue
+			from[key] = source
+		}
+	}
+
+	for n, source := range sources {
+		if source.IsZero() {
+			continue
+		}
+
+		if source.TokenAuth() && len(from) == 0 {
+			set(AccessTokenKey, &resolved.AccessToken, n, source.AccessToken)
+			set(CredentialProcessKey, &resolved.CredentialProcess, n, source.CredentialProcess)
+
+			return resolved, from
+		}
+
+		set(ClientIDKey, &resolved.ClientID, n, source.ClientID)
+		set(ClientSecretKey, &resolved.ClientSecret, n, source.ClientSecret)
+	}
+
+	return resolved, from
+}
@@ -0,0 +1,59 @@
+package profiles_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/profiles"
+)
+
+func TestResolveCredentials(t *testing.T) {
+	clientCredentials := profiles.Credentials{ClientID: "id", ClientSecret: "secret"}
+	token := profiles.Credentials{AccessToken: "token"}
+	process := profiles.Credentials{CredentialProcess: "vault-credentials"}
+
+	for name, test := range map[string]struct {
+		sources  []profiles.Credentials
+		want     profiles.Credentials
+		wantFrom map[string]int
+	}{
+		"none": {
+			want:     profiles.Credentials{},
+			wantFrom: map[string]int{},
+		},
+		"client credentials before a token": {
+			sources:  []profiles.Credentials{{}, clientCredentials, token},
+			want:     clientCredentials,
+			wantFrom: map[string]int{profiles.ClientIDKey: 1, profiles.ClientSecretKey: 1},
+		},
+		"client credentials completed": {
+			sources:  []profiles.Credentials{{ClientID: "id"}, process, {ClientSecret: "secret"}},
+			want:     clientCredentials,
+			wantFrom: map[string]int{profiles.ClientIDKey: 0, profiles.ClientSecretKey: 2},
+		},
+		"token before a credential process": {
+			sources:  []profiles.Credentials{token, process},
+			want:     token,
+			wantFrom: map[string]int{profiles.AccessTokenKey: 0},
+		},
+		"token before client credentials": {
+			sources:  []profiles.Credentials{{}, {AccessToken: "token", ClientID: "ignored"}, clientCredentials},
+			want:     token,
+			wantFrom: map[string]int{profiles.AccessTokenKey: 1},
+		},
+		"conflicting tokens of the same source": {
+			sources:  []profiles.Credentials{{AccessToken: "token", CredentialProcess: "vault-credentials"}},
+			want:     profiles.Credentials{AccessToken: "token", CredentialProcess: "vault-credentials"},
+			wantFrom: map[string]int{profiles.AccessTokenKey: 0, profiles.CredentialProcessKey: 0},
+		},
+	} {
+		got, from := profiles.ResolveCredentials(test.sources...)
+		if got != test.want {
+			t.Errorf("%s: got %+v, want %+v", name, got, test.want)
+		}
+
+		if !maps.Equal(from, test.wantFrom) {
+			t.Errorf("%s: got the sources %v, want %v", name, from, test.wantFrom)
+		}
+	}
+}
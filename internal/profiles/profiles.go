@@ -0,0 +1,198 @@
+// Package profiles reads named credential profiles from the shared configuration file,
+// so several companies can be managed without juggling environment variables:
+//
+//	[default]
+//	company_id    = 300
+//	client_id     = "xxx"
+//	client_secret = "xxx"
+//
+//	[staging]
+//	api_url = "https://api.example.com"
+//	...
+//
+// The file is INI, and the quoted values also make it valid TOML.
+package profiles
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultProfile is loaded when no profile is requested, if the file has it.
+	DefaultProfile = "default"
+	// FileEnvVar overrides the location of the configuration file.
+	FileEnvVar = "TCDN_CONFIG_FILE"
+)
+
+// ErrNotFound is returned when the configuration file or the requested profile do not exist.
+var ErrNotFound = errors.New("not found")
+
+// Profile is a section of the configuration file.
+type Profile struct {
+	Name string
+	// File is the configuration file the profile was read from.
+	File   string
+	values map[string]string
+}
+
+// Get returns the value of key in the profile, and whether it was set.
+func (p *Profile) Get(key string) (string, bool) {
+	value, found := p.values[key]
+
+	return value, found
+}
+
+// String describes where the profile comes from, for diagnostics.
+func (p *Profile) String() string {
+	return fmt.Sprintf("profile %q of %s", p.Name, p.File)
+}
+
+// DefaultFile returns the path of the configuration file: the value of TCDN_CONFIG_FILE,
+// or ~/.config/transparentedge/credentials.
+func DefaultFile() (string, error) {
+	if file := os.Getenv(FileEnvVar); file != "" {
+		return file, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find the home directory, set %s: %w", FileEnvVar, err)
+	}
+
+	return filepath.Join(home, ".config", "transparentedge", "credentials"), nil
+}
+
+// Load reads the profile name from file. The error wraps ErrNotFound when the file or the profile are missing.
+func Load(file string, name string) (*Profile, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("configuration file %s %w", file, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sections, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	values, found := sections[name]
+	if !found {
+		return nil, fmt.Errorf("profile %q %w in %s", name, ErrNotFound, file)
+	}
+
+	return &Profile{Name: name, File: file, values: values}, nil
+}
+
+// Select reads the profile name from the default file, or the default profile if the file has it when name is empty.
+// It returns nil when no profile applies, and an error if the requested one or the file could not be read.
+func Select(name string) (*Profile, error) {
+	requested := name != ""
+	if !requested {
+		name = DefaultProfile
+	}
+
+	file, err := DefaultFile()
+	if err != nil {
+		if requested {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	profile, err := Load(file, name)
+	if err != nil {
+		// The default profile is optional, but a broken file is reported anyway
+		if requested || !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return profile, nil
+}
+
+// Parse reads the sections of an INI file and their keys.
+// Lines starting with '#' or ';' are comments, and values may be quoted.
+func Parse(r io.Reader) (map[string]map[string]string, error) {
+	sections := map[string]map[string]string{}
+
+	var current map[string]string
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "["):
+			name, found := strings.CutSuffix(line, "]")
+			if !found {
+				return nil, fmt.Errorf("line %d: unterminated section header", lineNumber)
+			}
+
+			name = strings.TrimSpace(name[1:])
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty section name", lineNumber)
+			}
+
+			if sections[name] == nil {
+				sections[name] = map[string]string{}
+			}
+
+			current = sections[name]
+		default:
+			key, value, found := strings.Cut(line, "=")
+			if !found {
+				return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+			}
+
+			if current == nil {
+				return nil, fmt.Errorf("line %d: key outside of a section", lineNumber)
+			}
+
+			value, err := unquote(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+
+			current[strings.TrimSpace(key)] = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sections, nil
+}
+
+// unquote removes the double or single quotes around value, if any.
+func unquote(value string) (string, error) {
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		return value, nil
+	}
+
+	if len(value) < 2 || value[len(value)-1] != value[0] {
+		return "", errors.New("unterminated quoted value")
+	}
+
+	if value[0] == '\'' {
+		return value[1 : len(value)-1], nil
+	}
+
+	return strconv.Unquote(value)
+}
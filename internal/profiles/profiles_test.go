@@ -0,0 +1,141 @@
+package profiles_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/profiles"
+)
+
+const testFile = `
+# Companies managed from this workstation
+[default]
+company_id    = 300
+client_id     = "default-id"
+client_secret = 'default=secret'
+
+[staging]
+; a mirror of the API
+api_url   = https://api.example.com
+client_id = "staging \"id\""
+`
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestParse(t *testing.T) {
+	sections, err := profiles.Parse(strings.NewReader(testFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]string{
+		"default": {"company_id": "300", "client_id": "default-id", "client_secret": "default=secret"},
+		"staging": {"api_url": "https://api.example.com", "client_id": `staging "id"`},
+	}
+
+	for name, values := range want {
+		for key, value := range values {
+			if got := sections[name][key]; got != value {
+				t.Errorf("%s.%s = %q, want %q", name, key, got, value)
+			}
+		}
+
+		if len(sections[name]) != len(values) {
+			t.Errorf("%s has %d keys, want %d", name, len(sections[name]), len(values))
+		}
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	for content, want := range map[string]string{
+		"[default":                 "line 1: unterminated section header",
+		"company_id = 300":         "line 1: key outside of a section",
+		"[default]\ncompany_id":    "line 2: expected key = value",
+		"[]":                       "line 1: empty section name",
+		"[a]\nclient_id = \"a\\\"": "line 2: invalid syntax",
+		"[a]\nclient_id = \"a":     "line 2: unterminated quoted value",
+	} {
+		_, err := profiles.Parse(strings.NewReader(content))
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("Parse(%q): expected %q, got %v", content, want, err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := writeFile(t, testFile)
+
+	profile, err := profiles.Load(file, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, found := profile.Get("api_url"); !found || value != "https://api.example.com" {
+		t.Errorf("api_url = %q, %t", value, found)
+	}
+
+	if _, found := profile.Get("client_secret"); found {
+		t.Error("client_secret is not set in the staging profile")
+	}
+
+	if _, err := profiles.Load(file, "production"); !errors.Is(err, profiles.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing profile, got %v", err)
+	}
+
+	if _, err := profiles.Load(filepath.Join(t.TempDir(), "missing"), "default"); !errors.Is(err, profiles.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing file, got %v", err)
+	}
+}
+
+func TestDefaultFile(t *testing.T) {
+	t.Setenv(profiles.FileEnvVar, "")
+	t.Setenv("HOME", "/home/terraform")
+
+	if file, err := profiles.DefaultFile(); err != nil || file != "/home/terraform/.config/transparentedge/credentials" {
+		t.Errorf("DefaultFile() = %q, %v", file, err)
+	}
+
+	t.Setenv(profiles.FileEnvVar, "/etc/transparentedge/credentials")
+
+	if file, err := profiles.DefaultFile(); err != nil || file != "/etc/transparentedge/credentials" {
+		t.Errorf("DefaultFile() = %q, %v", file, err)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	t.Setenv(profiles.FileEnvVar, writeFile(t, testFile))
+
+	profile, err := profiles.Select("")
+	if err != nil || profile == nil || profile.Name != profiles.DefaultProfile {
+		t.Fatalf("expected the default profile, got %v, %v", profile, err)
+	}
+
+	if _, err := profiles.Select("production"); !errors.Is(err, profiles.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing profile, got %v", err)
+	}
+
+	// The default profile is optional
+	t.Setenv(profiles.FileEnvVar, writeFile(t, "[staging]\nclient_id = id\n"))
+
+	if profile, err := profiles.Select(""); profile != nil || err != nil {
+		t.Errorf("expected no profile, got %v, %v", profile, err)
+	}
+
+	t.Setenv(profiles.FileEnvVar, writeFile(t, "client_id = id\n"))
+
+	if _, err := profiles.Select(""); err == nil {
+		t.Error("expected the broken file to be reported")
+	}
+}
@@ -0,0 +1,106 @@
+package transparentedge
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/profiles"
+)
+
+// settingSources remembers where each credential was read from, so the diagnostics can point at it.
+type settingSources map[string]string
+
+// record notes the source of attribute when it is set in the provider configuration or in envVar.
+func (s settingSources) record(attribute string, configured bool, envVar string) {
+	switch {
+	case configured:
+		s[attribute] = "the provider configuration"
+	case os.Getenv(envVar) != "":
+		s[attribute] = "the " + envVar + " environment variable"
+	}
+}
+
+// credentialEnvVars are the environment variables of the credentials, by key.
+var credentialEnvVars = map[string]string{
+	profiles.ClientIDKey:          "TCDN_CLIENT_ID",
+	profiles.ClientSecretKey:      "TCDN_CLIENT_SECRET",
+	profiles.AccessTokenKey:       "TCDN_ACCESS_TOKEN",
+	profiles.CredentialProcessKey: "TCDN_CREDENTIAL_PROCESS",
+}
+
+// recordCredentials notes the source of the credentials chosen by profiles.ResolveCredentials
+// from the provider configuration, the environment and profile, in this order.
+func (s settingSources) recordCredentials(from map[string]int, profile *profiles.Profile) {
+	for key, source := range from {
+		switch source {
+		case 0:
+			s[key] = "the provider configuration"
+		case 1:
+			s[key] = "the " + credentialEnvVars[key] + " environment variable"
+		default:
+			s[key] = profile.String()
+		}
+	}
+}
+
+// fromProfile returns the value of attribute in profile if no other source set it, current otherwise.
+func (s settingSources) fromProfile(profile *profiles.Profile, attribute string, current string) string {
+	if _, found := s[attribute]; found {
+		return current
+	}
+
+	value, found := profile.Get(attribute)
+	if !found || value == "" {
+		return current
+	}
+
+	s[attribute] = profile.String()
+
+	return value
+}
+
+// companyIDFromProfile is fromProfile for company_id, an invalid value becomes 0 and is reported by the validation.
+func (s settingSources) companyIDFromProfile(profile *profiles.Profile, current int) int {
+	value := s.fromProfile(profile, "company_id", "")
+	if value == "" {
+		return current
+	}
+
+	companyid, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+
+	return companyid
+}
+
+// explain completes the detail of a diagnostic about attribute with the source of its value,
+// or with the places it can be set in when no source has it.
+func (s settingSources) explain(attribute string, envVar string, profile *profiles.Profile, detail string) string {
+	if source, found := s[attribute]; found {
+		return detail + " The value comes from " + source + "."
+	}
+
+	detail += fmt.Sprintf(" Set %s in the provider configuration, the %s environment variable or a credentials profile.", attribute, envVar)
+
+	if profile != nil {
+		detail += fmt.Sprintf(" The %s does not set it either.", profile)
+	}
+
+	return detail
+}
+
+// loadProfile reads the credentials profile name, or the default one if the file has it when name is empty.
+// It returns nil when no profile applies, adding an error to diags if the requested one could not be read.
+func loadProfile(name string, diags *diag.Diagnostics) *profiles.Profile {
+	profile, err := profiles.Select(name)
+	if err != nil {
+		diags.AddAttributeError(path.Root("profile"), "Unable to load the credentials profile", err.Error())
+	}
+
+	return profile
+}
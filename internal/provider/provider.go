@@ -2,21 +2,31 @@ package transparentedge
 
 import (
 	"context"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/autoprovisioning"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/companies"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/profiles"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/staging"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
@@ -27,7 +37,8 @@ const (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &TransparentEdgeProvider{}
+	_ provider.Provider                  = &TransparentEdgeProvider{}
+	_ provider.ProviderWithListResources = &TransparentEdgeProvider{}
 )
 
 type TransparentEdgeProvider struct {
@@ -71,16 +82,126 @@ func (*TransparentEdgeProvider) Schema(_ context.Context, _ provider.SchemaReque
 				Description:         "Client Secret (dashboard -> profile -> account options -> manage keys). May also be provided via TCDN_CLIENT_SECRET environment variable.",
 				MarkdownDescription: "Client Secret (`dashboard -> profile -> account options -> manage keys`). May also be provided via `TCDN_CLIENT_SECRET` environment variable.",
 			},
+			"access_token": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				Description:         "Access token sent as is to the API, instead of requesting one with 'client_id' and 'client_secret'. It is not renewed, the provider fails once the API rejects it. May also be provided via TCDN_ACCESS_TOKEN environment variable.",
+				MarkdownDescription: "Access token sent as is to the API, instead of requesting one with `client_id` and `client_secret`. It is not renewed, the provider fails once the API rejects it. May also be provided via `TCDN_ACCESS_TOKEN` environment variable.",
+			},
+			"credential_process": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Command printing the credentials as JSON on its standard output, run whenever a new access token is needed instead of using 'client_id' and 'client_secret'. It prints either 'client_id' and 'client_secret', or 'access_token' and an optional 'expires_at' (RFC 3339). May also be provided via TCDN_CREDENTIAL_PROCESS environment variable.",
+				MarkdownDescription: "Command printing the credentials as JSON on its standard output, run whenever a new access token is needed instead of using `client_id` and `client_secret`. It prints either `client_id` and `client_secret`, or `access_token` and an optional `expires_at` (RFC 3339). May also be provided via `TCDN_CREDENTIAL_PROCESS` environment variable.",
+			},
+			"profile": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Name of the credentials profile to read api_url, company_id, client_id and client_secret from, when they are not set in the provider configuration nor in the environment. The profiles are read from '~/.config/transparentedge/credentials', or the file set in TCDN_CONFIG_FILE environment variable. default: 'default' if the file has it. May also be provided via TCDN_PROFILE environment variable.",
+				MarkdownDescription: "Name of the credentials profile to read `api_url`, `company_id`, `client_id` and `client_secret` from, when they are not set in the provider configuration nor in the environment. The profiles are read from `~/.config/transparentedge/credentials`, or the file set in `TCDN_CONFIG_FILE` environment variable. default: `default` if the file has it. May also be provided via `TCDN_PROFILE` environment variable.",
+			},
 			"insecure": schema.BoolAttribute{
 				Optional:            true,
 				Description:         "Ignore TLS certificate for 'api_url'. May also be provided via TCDN_INSECURE environment variable.",
 				MarkdownDescription: "Ignore TLS certificate for `api_url`. May also be provided via `TCDN_INSECURE` environment variable.",
 			},
+			"ca_bundle": schema.StringAttribute{
+				Optional:            true,
+				Description:         "PEM encoded CA certificates trusted for 'api_url' in addition to the system ones, or the path to a file containing them. May also be provided via TCDN_CA_BUNDLE environment variable.",
+				MarkdownDescription: "PEM encoded CA certificates trusted for `api_url` in addition to the system ones, or the path to a file containing them. May also be provided via `TCDN_CA_BUNDLE` environment variable.",
+			},
+			"client_certificate": schema.StringAttribute{
+				Optional:            true,
+				Description:         "PEM encoded client certificate presented to 'api_url', or the path to a file containing it. Requires 'client_key'. May also be provided via TCDN_CLIENT_CERTIFICATE environment variable.",
+				MarkdownDescription: "PEM encoded client certificate presented to `api_url`, or the path to a file containing it. Requires `client_key`. May also be provided via `TCDN_CLIENT_CERTIFICATE` environment variable.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				Description:         "PEM encoded private key of 'client_certificate', or the path to a file containing it. May also be provided via TCDN_CLIENT_KEY environment variable.",
+				MarkdownDescription: "PEM encoded private key of `client_certificate`, or the path to a file containing it. May also be provided via `TCDN_CLIENT_KEY` environment variable.",
+			},
+			"min_tls_version": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(teclient.TLSVersionNames()...),
+				},
+				Description:         "Minimum TLS version accepted from 'api_url', one of: 1.0, 1.1, 1.2, 1.3. default: 1.2. May also be provided via TCDN_MIN_TLS_VERSION environment variable.",
+				MarkdownDescription: "Minimum TLS version accepted from `api_url`, one of: `1.0`, `1.1`, `1.2`, `1.3`. default: `1.2`. May also be provided via `TCDN_MIN_TLS_VERSION` environment variable.",
+			},
 			"auth": schema.BoolAttribute{
 				Optional:            true,
 				Description:         "Set to false if your configuration only consumes data sources that do not require authentication, such as `transparentedge_ip_ranges`.",
 				MarkdownDescription: "Set to false if your configuration only consumes data sources that do not require authentication, such as `transparentedge_ip_ranges`.",
 			},
+			"request_timeout": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				Description:         "Maximum time in seconds of a single API request, reading the whole response included. Every retry gets the full time again. default: 50. May also be provided via TCDN_REQUEST_TIMEOUT environment variable.",
+				MarkdownDescription: "Maximum time in seconds of a single API request, reading the whole response included. Every retry gets the full time again. default: `50`. May also be provided via `TCDN_REQUEST_TIMEOUT` environment variable.",
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:            true,
+				Description:         "URL of the proxy used to reach 'api_url', i.e: 'http://proxy.example.com:3128'. default: the HTTPS_PROXY and HTTP_PROXY environment variables. May also be provided via TCDN_PROXY_URL environment variable.",
+				MarkdownDescription: "URL of the proxy used to reach `api_url`, i.e: `http://proxy.example.com:3128`. default: the `HTTPS_PROXY` and `HTTP_PROXY` environment variables. May also be provided via `TCDN_PROXY_URL` environment variable.",
+			},
+			"no_proxy": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Comma-separated list of hosts, domains ('.example.com') and CIDR ranges reached without the proxy. default: the NO_PROXY environment variable. May also be provided via TCDN_NO_PROXY environment variable.",
+				MarkdownDescription: "Comma-separated list of hosts, domains (`.example.com`) and CIDR ranges reached without the proxy. default: the `NO_PROXY` environment variable. May also be provided via `TCDN_NO_PROXY` environment variable.",
+			},
+			"user_agent_suffix": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Text appended to the User-Agent header of the API requests, i.e: the name of the pipeline running Terraform. May also be provided via TCDN_USER_AGENT_SUFFIX environment variable.",
+				MarkdownDescription: "Text appended to the `User-Agent` header of the API requests, i.e: the name of the pipeline running Terraform. May also be provided via `TCDN_USER_AGENT_SUFFIX` environment variable.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description:         "Maximum number of retries for API requests failing with a transient error (429, 502, 503, 504 or a connection reset). Set to 0 to disable retries. default: 4. May also be provided via TCDN_MAX_RETRIES environment variable.",
+				MarkdownDescription: "Maximum number of retries for API requests failing with a transient error (`429`, `502`, `503`, `504` or a connection reset). Set to `0` to disable retries. default: `4`. May also be provided via `TCDN_MAX_RETRIES` environment variable.",
+			},
+			"max_backoff": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				Description:         "Maximum wait in seconds between two attempts of the same API request, also caps the API's Retry-After header. default: 30. May also be provided via TCDN_MAX_BACKOFF environment variable.",
+				MarkdownDescription: "Maximum wait in seconds between two attempts of the same API request, also caps the API's `Retry-After` header. default: `30`. May also be provided via `TCDN_MAX_BACKOFF` environment variable.",
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description:         "Maximum number of API requests in flight at the same time, shared by all the resources and data sources. Set to 0 for no limit. default: 0. May also be provided via TCDN_MAX_CONCURRENT_REQUESTS environment variable.",
+				MarkdownDescription: "Maximum number of API requests in flight at the same time, shared by all the resources and data sources. Set to `0` for no limit. default: `0`. May also be provided via `TCDN_MAX_CONCURRENT_REQUESTS` environment variable.",
+			},
+			"requests_per_second": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				Description:         "Maximum number of API requests started per second, shared by all the resources and data sources. Set to 0 for no limit. default: 0. May also be provided via TCDN_REQUESTS_PER_SECOND environment variable.",
+				MarkdownDescription: "Maximum number of API requests started per second, shared by all the resources and data sources. Set to `0` for no limit. default: `0`. May also be provided via `TCDN_REQUESTS_PER_SECOND` environment variable.",
+			},
+			"cache_token": schema.BoolAttribute{
+				Optional:            true,
+				Description:         "Keep the access token obtained with 'client_id' and 'client_secret' in the user cache directory, so the next runs reuse it instead of requesting a new one until it is about to expire. default: false. May also be provided via TCDN_CACHE_TOKEN environment variable.",
+				MarkdownDescription: "Keep the access token obtained with `client_id` and `client_secret` in the user cache directory, so the next runs reuse it instead of requesting a new one until it is about to expire. default: `false`. May also be provided via `TCDN_CACHE_TOKEN` environment variable.",
+			},
+			"cache_list_requests": schema.BoolAttribute{
+				Optional:            true,
+				Description:         "Keep the lists of sites, backends, certificates and VCL configurations in memory for the whole run, instead of downloading them for every resource. Any change made by the provider to a list drops it from the cache. default: false. May also be provided via TCDN_CACHE_LIST_REQUESTS environment variable.",
+				MarkdownDescription: "Keep the lists of sites, backends, certificates and VCL configurations in memory for the whole run, instead of downloading them for every resource. Any change made by the provider to a list drops it from the cache. default: `false`. May also be provided via `TCDN_CACHE_LIST_REQUESTS` environment variable.",
+			},
+			"read_only": schema.BoolAttribute{
+				Optional:            true,
+				Description:         "Refuse any change to the CDN configuration: plans that create, update or delete resources fail, and the API client rejects write requests. Meant for audit and drift detection runs. default: false. May also be provided via TCDN_READ_ONLY environment variable.",
+				MarkdownDescription: "Refuse any change to the CDN configuration: plans that create, update or delete resources fail, and the API client rejects write requests. Meant for audit and drift detection runs. default: `false`. May also be provided via `TCDN_READ_ONLY` environment variable.",
+			},
 		},
 	}
 }
@@ -91,8 +212,29 @@ type transparentedgeProviderModel struct {
 	CompanyID    types.Int64  `tfsdk:"company_id"`
 	ClientID     types.String `tfsdk:"client_id"`
 	ClientSecret types.String `tfsdk:"client_secret"`
-	Insecure     types.Bool   `tfsdk:"insecure"`
-	Auth         types.Bool   `tfsdk:"auth"`
+	Profile      types.String `tfsdk:"profile"`
+
+	AccessToken       types.String `tfsdk:"access_token"`
+	CredentialProcess types.String `tfsdk:"credential_process"`
+	Insecure          types.Bool   `tfsdk:"insecure"`
+	Auth              types.Bool   `tfsdk:"auth"`
+	RequestTimeout    types.Int64  `tfsdk:"request_timeout"`
+	ProxyURL          types.String `tfsdk:"proxy_url"`
+	NoProxy           types.String `tfsdk:"no_proxy"`
+	UserAgentSuffix   types.String `tfsdk:"user_agent_suffix"`
+	MaxRetries        types.Int64  `tfsdk:"max_retries"`
+	MaxBackoff        types.Int64  `tfsdk:"max_backoff"`
+
+	MaxConcurrentRequests types.Int64 `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond     types.Int64 `tfsdk:"requests_per_second"`
+	CacheListRequests     types.Bool  `tfsdk:"cache_list_requests"`
+	CacheToken            types.Bool  `tfsdk:"cache_token"`
+	ReadOnly              types.Bool  `tfsdk:"read_only"`
+
+	CABundle          types.String `tfsdk:"ca_bundle"`
+	ClientCertificate types.String `tfsdk:"client_certificate"`
+	ClientKey         types.String `tfsdk:"client_key"`
+	MinTLSVersion     types.String `tfsdk:"min_tls_version"`
 }
 
 func (p *TransparentEdgeProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -111,11 +253,25 @@ func (p *TransparentEdgeProvider) Configure(ctx context.Context, req provider.Co
 	// Default values to environment variables, but override
 	// with Terraform configuration value if set.
 	apiURL := os.Getenv("TCDN_API_URL")
-	clientid := os.Getenv("TCDN_CLIENT_ID")
-	clientsecret := os.Getenv("TCDN_CLIENT_SECRET")
+	profileName := os.Getenv("TCDN_PROFILE")
+	caBundle := os.Getenv("TCDN_CA_BUNDLE")
+	clientCertificate := os.Getenv("TCDN_CLIENT_CERTIFICATE")
+	clientKey := os.Getenv("TCDN_CLIENT_KEY")
+	minTLSVersion := os.Getenv("TCDN_MIN_TLS_VERSION")
+	proxyURL := os.Getenv("TCDN_PROXY_URL")
+	noProxy := os.Getenv("TCDN_NO_PROXY")
+	userAgentSuffix := os.Getenv("TCDN_USER_AGENT_SUFFIX")
 
 	companyid, _ := helpers.GetIntEnv("TCDN_COMPANY_ID", 0)
 	insecure, _ := helpers.GetEnvBool("TCDN_INSECURE", false)
+	requestTimeout, _ := helpers.GetIntEnv("TCDN_REQUEST_TIMEOUT", int(teclient.DefaultRequestTimeout.Seconds()))
+	maxRetries, _ := helpers.GetIntEnv("TCDN_MAX_RETRIES", teclient.DefaultMaxRetries)
+	maxBackoff, _ := helpers.GetIntEnv("TCDN_MAX_BACKOFF", int(teclient.DefaultMaxBackoff.Seconds()))
+	maxConcurrentRequests, _ := helpers.GetIntEnv("TCDN_MAX_CONCURRENT_REQUESTS", teclient.DefaultMaxConcurrentRequests)
+	requestsPerSecond, _ := helpers.GetIntEnv("TCDN_REQUESTS_PER_SECOND", teclient.DefaultRequestsPerSecond)
+	cacheListRequests, _ := helpers.GetEnvBool("TCDN_CACHE_LIST_REQUESTS", false)
+	cacheToken, _ := helpers.GetEnvBool("TCDN_CACHE_TOKEN", false)
+	readOnly, _ := helpers.GetEnvBool("TCDN_READ_ONLY", false)
 
 	auth := true
 
@@ -128,22 +284,116 @@ func (p *TransparentEdgeProvider) Configure(ctx context.Context, req provider.Co
 		companyid = int(config.CompanyID.ValueInt64())
 	}
 
-	if !config.ClientID.IsNull() {
-		clientid = config.ClientID.ValueString()
-	}
-
-	if !config.ClientSecret.IsNull() {
-		clientsecret = config.ClientSecret.ValueString()
+	if !config.Profile.IsNull() {
+		profileName = config.Profile.ValueString()
 	}
 
 	if !config.Insecure.IsNull() {
 		insecure = config.Insecure.ValueBool()
 	}
 
+	if !config.CABundle.IsNull() {
+		caBundle = config.CABundle.ValueString()
+	}
+
+	if !config.ClientCertificate.IsNull() {
+		clientCertificate = config.ClientCertificate.ValueString()
+	}
+
+	if !config.ClientKey.IsNull() {
+		clientKey = config.ClientKey.ValueString()
+	}
+
+	if !config.MinTLSVersion.IsNull() {
+		minTLSVersion = config.MinTLSVersion.ValueString()
+	}
+
 	if !config.Auth.IsNull() {
 		auth = config.Auth.ValueBool()
 	}
 
+	if !config.RequestTimeout.IsNull() {
+		requestTimeout = int(config.RequestTimeout.ValueInt64())
+	}
+
+	if !config.ProxyURL.IsNull() {
+		proxyURL = config.ProxyURL.ValueString()
+	}
+
+	if !config.NoProxy.IsNull() {
+		noProxy = config.NoProxy.ValueString()
+	}
+
+	if !config.UserAgentSuffix.IsNull() {
+		userAgentSuffix = config.UserAgentSuffix.ValueString()
+	}
+
+	if !config.MaxRetries.IsNull() {
+		maxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	if !config.MaxBackoff.IsNull() {
+		maxBackoff = int(config.MaxBackoff.ValueInt64())
+	}
+
+	if !config.MaxConcurrentRequests.IsNull() {
+		maxConcurrentRequests = int(config.MaxConcurrentRequests.ValueInt64())
+	}
+
+	if !config.RequestsPerSecond.IsNull() {
+		requestsPerSecond = int(config.RequestsPerSecond.ValueInt64())
+	}
+
+	if !config.CacheListRequests.IsNull() {
+		cacheListRequests = config.CacheListRequests.ValueBool()
+	}
+
+	if !config.CacheToken.IsNull() {
+		cacheToken = config.CacheToken.ValueBool()
+	}
+
+	if !config.ReadOnly.IsNull() {
+		readOnly = config.ReadOnly.ValueBool()
+	}
+
+	// The settings still unset are read from the profile, the source with the lowest precedence
+	sources := settingSources{}
+	sources.record("api_url", !config.APIURL.IsNull(), "TCDN_API_URL")
+	sources.record("company_id", !config.CompanyID.IsNull(), "TCDN_COMPANY_ID")
+
+	profile := loadProfile(profileName, &resp.Diagnostics)
+	if profile != nil {
+		apiURL = sources.fromProfile(profile, "api_url", apiURL)
+		companyid = sources.companyIDFromProfile(profile, companyid)
+	}
+
+	// The authentication mode is chosen by the source with the highest precedence setting any credential,
+	// so a token in the default profile doesn't replace the client credentials of the environment
+	credentials, credentialsFrom := profiles.ResolveCredentials(
+		profiles.Credentials{
+			ClientID:          config.ClientID.ValueString(),
+			ClientSecret:      config.ClientSecret.ValueString(),
+			AccessToken:       config.AccessToken.ValueString(),
+			CredentialProcess: config.CredentialProcess.ValueString(),
+		},
+		profiles.Credentials{
+			ClientID:          os.Getenv(credentialEnvVars[profiles.ClientIDKey]),
+			ClientSecret:      os.Getenv(credentialEnvVars[profiles.ClientSecretKey]),
+			AccessToken:       os.Getenv(credentialEnvVars[profiles.AccessTokenKey]),
+			CredentialProcess: os.Getenv(credentialEnvVars[profiles.CredentialProcessKey]),
+		},
+		profile.Credentials(),
+	)
+	sources.recordCredentials(credentialsFrom, profile)
+
+	clientid := credentials.ClientID
+	clientsecret := credentials.ClientSecret
+	accessToken := credentials.AccessToken
+	credentialProcess := credentials.CredentialProcess
+
+	// The access token and the credential process replace the client credentials
+	tokenAuth := credentials.TokenAuth()
+
 	// Values that need conversion (if not set in the configuration)
 
 	// Default values
@@ -170,23 +420,90 @@ func (p *TransparentEdgeProvider) Configure(ctx context.Context, req provider.Co
 		resp.Diagnostics.AddAttributeError(
 			path.Root("company_id"),
 			"Invalid Company ID value",
-			"Company ID is an integer greater than 0.",
+			sources.explain("company_id", "TCDN_COMPANY_ID", profile, "Company ID is an integer greater than 0."),
 		)
 	}
 
-	if clientid == "" {
+	if clientid == "" && !tokenAuth {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("client_id"),
 			"Missing Client ID",
-			"Please provide a valid Client ID.",
+			sources.explain("client_id", "TCDN_CLIENT_ID", profile, "Please provide a valid Client ID, or use access_token or credential_process instead."),
 		)
 	}
 
-	if clientsecret == "" {
+	if clientsecret == "" && !tokenAuth {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("client_secret"),
 			"Missing Client Secret",
-			"Please provide a valid Client Secret.",
+			sources.explain("client_secret", "TCDN_CLIENT_SECRET", profile, "Please provide a valid Client Secret, or use access_token or credential_process instead."),
+		)
+	}
+
+	if accessToken != "" && credentialProcess != "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("credential_process"),
+			"Conflicting authentication modes",
+			sources.explain("credential_process", "TCDN_CREDENTIAL_PROCESS", profile, "Please provide either access_token or credential_process, not both.")+
+				" The access token comes from "+sources["access_token"]+".",
+		)
+	}
+
+	if requestTimeout < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("request_timeout"),
+			"Invalid Request Timeout value",
+			"Request Timeout is a number of seconds greater than 0.",
+		)
+	}
+
+	if proxyURL != "" {
+		if u, err := url.Parse(proxyURL); err != nil || u.Host == "" || !slices.Contains([]string{"http", "https", "socks5"}, u.Scheme) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid Proxy URL",
+				"Please provide a valid proxy URL, protocol (http, https or socks5) and host are required.",
+			)
+		}
+	}
+
+	if strings.ContainsFunc(userAgentSuffix, unicode.IsControl) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("user_agent_suffix"),
+			"Invalid User Agent Suffix",
+			"The User Agent Suffix cannot contain control characters such as new lines.",
+		)
+	}
+
+	if maxRetries < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Invalid Max Retries value",
+			"Max Retries is an integer greater than or equal to 0.",
+		)
+	}
+
+	if maxBackoff < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_backoff"),
+			"Invalid Max Backoff value",
+			"Max Backoff is a number of seconds greater than 0.",
+		)
+	}
+
+	if maxConcurrentRequests < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_concurrent_requests"),
+			"Invalid Max Concurrent Requests value",
+			"Max Concurrent Requests is an integer greater than or equal to 0.",
+		)
+	}
+
+	if requestsPerSecond < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Invalid Requests Per Second value",
+			"Requests Per Second is an integer greater than or equal to 0.",
 		)
 	}
 
@@ -194,20 +511,63 @@ func (p *TransparentEdgeProvider) Configure(ctx context.Context, req provider.Co
 		resp.Diagnostics.AddAttributeError(
 			path.Root("api_url"),
 			"Invalid API URL",
-			"Please provide a valid API URL value, protocol (http or https) is required.",
+			sources.explain("api_url", "TCDN_API_URL", profile, "Please provide a valid API URL value, protocol (http or https) is required."),
 		)
 	}
 
+	tokenCacheDir := ""
+
+	if cacheToken {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("cache_token"),
+				"Token cache disabled",
+				"Unable to find the user cache directory: "+err.Error(),
+			)
+		} else {
+			tokenCacheDir = filepath.Join(cacheDir, "transparentedge")
+		}
+	}
+
+	tlsOpts := p.readTLSOptions(caBundle, clientCertificate, clientKey, minTLSVersion, &resp.Diagnostics)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	ctx = tflog.SetField(ctx, "tedge_api_url", apiURL)
 	ctx = tflog.SetField(ctx, "tedge_companyid", companyid)
+	ctx = tflog.SetField(ctx, "tedge_read_only", readOnly)
 	tflog.Debug(ctx, "Creating Transparent Edge API client")
 
 	// Create a new client using the configuration values
-	client, err := teclient.NewClient(&apiURL, &companyid, &clientid, &clientsecret, &insecure, &auth, &p.version)
+	clientOpts := teclient.ClientOptions{
+		RequestTimeout:  time.Duration(requestTimeout) * time.Second,
+		ProxyURL:        proxyURL,
+		NoProxy:         noProxy,
+		UserAgentSuffix: userAgentSuffix,
+
+		MaxRetries: maxRetries,
+		MaxBackoff: time.Duration(maxBackoff) * time.Second,
+
+		MaxConcurrentRequests: maxConcurrentRequests,
+		RequestsPerSecond:     requestsPerSecond,
+		CacheLists:            cacheListRequests,
+
+		CABundle:          tlsOpts.CABundle,
+		ClientCertificate: tlsOpts.ClientCertificate,
+		ClientKey:         tlsOpts.ClientKey,
+		MinTLSVersion:     tlsOpts.MinTLSVersion,
+
+		AccessToken:       accessToken,
+		CredentialProcess: credentialProcess,
+		TokenCacheDir:     tokenCacheDir,
+
+		ReadOnly: readOnly,
+	}
+
+	client, err := teclient.NewClient(ctx, &apiURL, &companyid, &clientid, &clientsecret, &insecure, &auth, &p.version, clientOpts)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create Transparent Edge API Client",
@@ -223,6 +583,7 @@ func (p *TransparentEdgeProvider) Configure(ctx context.Context, req provider.Co
 	// type Configure methods.
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.ListResourceData = client
 
 	tflog.Info(ctx, "Configured Transparent Edge API client", map[string]any{"success": true})
 }
@@ -235,6 +596,7 @@ func (*TransparentEdgeProvider) DataSources(_ context.Context) []func() datasour
 		autoprovisioning.NewBackendDataSource,
 		autoprovisioning.NewBackendsDataSource,
 		autoprovisioning.NewVclconfDataSource,
+		autoprovisioning.NewVclconfHistoryDataSource,
 		autoprovisioning.NewCertificatesDataSource,
 		autoprovisioning.NewCertReqDNSProvidersDataSource,
 		autoprovisioning.NewCertReqDNSCNAMEVerifDataSource,
@@ -244,6 +606,7 @@ func (*TransparentEdgeProvider) DataSources(_ context.Context) []func() datasour
 		staging.NewStagingBackendDataSource,
 		staging.NewStagingBackendsDataSource,
 		staging.NewStagingVclconfDataSource,
+		staging.NewStagingVclconfHistoryDataSource,
 		companies.NewIPRangesDataSource,
 	}
 }
@@ -263,6 +626,19 @@ func (*TransparentEdgeProvider) Resources(_ context.Context) []func() resource.R
 	}
 }
 
+// ListResources defines the list resources implemented in the provider, for 'terraform query'.
+func (*TransparentEdgeProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		autoprovisioning.NewSiteListResource,
+		autoprovisioning.NewBackendListResource,
+		autoprovisioning.NewCustomCertificateListResource,
+		autoprovisioning.NewCertReqDNSCredentialListResource,
+		autoprovisioning.NewCertReqDNSListResource,
+		autoprovisioning.NewCertReqHTTPListResource,
+		staging.NewStagingBackendListResource,
+	}
+}
+
 func New(version string, commit string) func() provider.Provider {
 	return func() provider.Provider {
 		return &TransparentEdgeProvider{
@@ -271,3 +647,52 @@ func New(version string, commit string) func() provider.Provider {
 		}
 	}
 }
+
+// readTLSOptions loads the PEM files of the TLS settings, adding an error to diags for every invalid one.
+func (*TransparentEdgeProvider) readTLSOptions(caBundle, clientCertificate, clientKey, minTLSVersion string, diags *diag.Diagnostics) teclient.ClientOptions {
+	var opts teclient.ClientOptions
+
+	readPEM := func(attribute string, value string) []byte {
+		if value == "" {
+			return nil
+		}
+
+		data, err := helpers.ReadPEMOrFile(value)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root(attribute),
+				"Unable to read "+attribute,
+				"The value is neither PEM encoded data nor a readable file: "+err.Error(),
+			)
+		}
+
+		return data
+	}
+
+	opts.CABundle = readPEM("ca_bundle", caBundle)
+	opts.ClientCertificate = readPEM("client_certificate", clientCertificate)
+	opts.ClientKey = readPEM("client_key", clientKey)
+
+	if (clientCertificate == "") != (clientKey == "") {
+		diags.AddAttributeError(
+			path.Root("client_key"),
+			"Incomplete client certificate",
+			"Please provide both client_certificate and client_key, or none of them.",
+		)
+	}
+
+	if minTLSVersion != "" {
+		version, found := teclient.TLSVersions[minTLSVersion]
+		if !found {
+			diags.AddAttributeError(
+				path.Root("min_tls_version"),
+				"Invalid Min TLS Version value",
+				"Min TLS Version is one of: "+strings.Join(teclient.TLSVersionNames(), ", ")+".",
+			)
+		}
+
+		opts.MinTLSVersion = version
+	}
+
+	return opts
+}
@@ -0,0 +1,462 @@
+package transparentedge_test
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+const testAccBackendConfig = `
+resource "transparentedge_backend" "test" {
+  name         = "origin1"
+  origin       = "origin.example.com"
+  port         = 80
+  ssl          = false
+  hchost       = "www.example.com"
+  hcpath       = "/"
+  hcstatuscode = 200
+}
+`
+
+func TestAccProvider_invalidCredentials(t *testing.T) {
+	acctest.NewServer(t)
+	t.Setenv("TCDN_CLIENT_SECRET", "wrong")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      `data "transparentedge_backends" "all" {}`,
+				ExpectError: regexp.MustCompile(`please\s+ensure credentials are correct`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_retriesTransientErrors(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					srv.InjectFault(testserver.Fault{Method: http.MethodGet, Path: "/backends", StatusCode: http.StatusServiceUnavailable, Times: 2})
+				},
+				Config: testAccBackendConfig,
+				Check:  resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_slowAPI(t *testing.T) {
+	srv := acctest.NewServer(t)
+	srv.SetLatency(50 * time.Millisecond)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig,
+				Check:  resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+			},
+		},
+	})
+}
+
+// The API rejects the access token in the middle of an apply, the provider must request a new one and carry on.
+func TestAccProvider_tokenExpiry(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					srv.InjectFault(testserver.Fault{
+						Method:     http.MethodPost,
+						Path:       "/backends",
+						StatusCode: http.StatusUnauthorized,
+						Body:       `{"detail": "Authentication credentials have expired."}`,
+						Times:      1,
+					})
+				},
+				Config: testAccBackendConfig,
+				Check:  resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_cacheListRequests(t *testing.T) {
+	acctest.NewServer(t)
+	t.Setenv("TCDN_CACHE_LIST_REQUESTS", "true")
+
+	config := `
+resource "transparentedge_backend" "test" {
+  count        = 3
+  name         = "origin${count.index}"
+  origin       = "origin.example.com"
+  port         = 80
+  ssl          = false
+  hchost       = "www.example.com"
+  hcpath       = "/"
+  hcstatuscode = 200
+}
+
+data "transparentedge_backends" "all" {
+  depends_on = [transparentedge_backend.test]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				// The data source must see the backends created in the same run
+				Check: resource.TestCheckResourceAttr("data.transparentedge_backends.all", "backends.#", "3"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_caBundle(t *testing.T) {
+	cert, key := acctest.SelfSignedCertificate(t, "terraform.example.com")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM([]byte(cert))
+
+	srv := acctest.NewTLSServer(t, clientCAs)
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caBundle, []byte(srv.CertificatePEM()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TCDN_CLIENT_KEY", key)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "transparentedge" {
+  ca_bundle = "` + caBundle + `"
+}
+` + testAccBackendConfig,
+				ExpectError: regexp.MustCompile(`Incomplete client certificate`),
+			},
+			{
+				Config: `
+provider "transparentedge" {
+  ca_bundle          = "` + caBundle + `"
+  client_certificate = <<-EOT
+` + cert + `  EOT
+  min_tls_version    = "1.3"
+}
+` + testAccBackendConfig,
+				Check: resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_profile(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	credentials := `
+[default]
+client_id     = "unused"
+
+[prod]
+api_url       = "` + srv.URL + `"
+company_id    = ` + strconv.Itoa(testserver.DefaultCompanyID) + `
+client_id     = "` + testserver.ClientID + `"
+client_secret = "` + testserver.ClientSecret + `"
+`
+	if err := os.WriteFile(os.Getenv("TCDN_CONFIG_FILE"), []byte(credentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, env := range []string{"TCDN_API_URL", "TCDN_COMPANY_ID", "TCDN_CLIENT_ID", "TCDN_CLIENT_SECRET"} {
+		t.Setenv(env, "")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The default profile is used when none is requested
+				Config:      testAccBackendConfig,
+				ExpectError: regexp.MustCompile(`(?s)Missing Client Secret.*The\s+profile\s+"default"\s+of.*set\s+it\s+either`),
+			},
+			{
+				Config: `
+provider "transparentedge" {
+  profile = "missing"
+}
+` + testAccBackendConfig,
+				ExpectError: regexp.MustCompile(`profile "missing" not found`),
+			},
+			{
+				// The environment takes precedence over the profile
+				PreConfig: func() {
+					t.Setenv("TCDN_PROFILE", "prod")
+					t.Setenv("TCDN_CLIENT_SECRET", "wrong")
+				},
+				Config:      testAccBackendConfig,
+				ExpectError: regexp.MustCompile(`please\s+ensure credentials are correct`),
+			},
+			{
+				PreConfig: func() {
+					t.Setenv("TCDN_CLIENT_SECRET", "")
+				},
+				Config: testAccBackendConfig,
+				Check:  resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_accessToken(t *testing.T) {
+	srv := acctest.NewServer(t)
+	t.Setenv("TCDN_CLIENT_ID", "")
+	t.Setenv("TCDN_CLIENT_SECRET", "")
+	t.Setenv("TCDN_ACCESS_TOKEN", srv.IssueToken(time.Hour))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "transparentedge" {
+  access_token       = "token"
+  credential_process = "cat credentials.json"
+}
+` + testAccBackendConfig,
+				ExpectError: regexp.MustCompile(`either access_token or credential_process, not both`),
+			},
+			{
+				Config: testAccBackendConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+					func(*terraform.State) error {
+						if n := srv.CountRequests(http.MethodPost, "/oauth2/access_token"); n != 0 {
+							return fmt.Errorf("expected no token request, got %d", n)
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// The authentication mode comes from the source with the highest precedence setting any credential,
+// the tokens of the lower ones are ignored.
+func TestAccProvider_credentialsPrecedence(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	credentials := `
+[default]
+access_token       = "revoked"
+credential_process = "false"
+`
+	if err := os.WriteFile(os.Getenv("TCDN_CONFIG_FILE"), []byte(credentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The client credentials of the environment win over the token of the default profile
+				Config: testAccBackendConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+					func(*terraform.State) error {
+						if n := srv.CountRequests(http.MethodPost, "/oauth2/access_token"); n == 0 {
+							return errors.New("expected the client credentials to request a token")
+						}
+
+						return nil
+					},
+				),
+			},
+			{
+				// The access token of the configuration doesn't conflict with the credential process of the profile
+				PreConfig: func() {
+					t.Setenv("TCDN_CLIENT_ID", "")
+					t.Setenv("TCDN_CLIENT_SECRET", "")
+				},
+				Config: `
+provider "transparentedge" {
+  access_token = "` + srv.IssueToken(time.Hour) + `"
+}
+` + testAccBackendConfig,
+				Check: resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_credentialProcess(t *testing.T) {
+	acctest.NewServer(t)
+	t.Setenv("TCDN_CLIENT_ID", "")
+	t.Setenv("TCDN_CLIENT_SECRET", "")
+
+	credentials := filepath.Join(t.TempDir(), "credentials.json")
+	content := `{"client_id": "` + testserver.ClientID + `", "client_secret": "` + testserver.ClientSecret + `"}`
+
+	if err := os.WriteFile(credentials, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "transparentedge" {
+  credential_process = "cat '` + credentials + `'"
+}
+` + testAccBackendConfig,
+				Check: resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+			},
+		},
+	})
+}
+
+func TestAccProvider_cacheToken(t *testing.T) {
+	srv := acctest.NewServer(t)
+	t.Setenv("TCDN_CACHE_TOKEN", "true")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig,
+				// Every terraform command of the test runs the provider again
+				Check: func(*terraform.State) error {
+					if n := srv.CountRequests(http.MethodPost, "/oauth2/access_token"); n != 1 {
+						return fmt.Errorf("expected a single token request, got %d", n)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccProvider_readOnly(t *testing.T) {
+	acctest.NewServer(t)
+
+	readOnlyConfig := func(origin string) string {
+		return `
+provider "transparentedge" {
+  read_only = true
+}
+
+resource "transparentedge_backend" "test" {
+  name         = "origin1"
+  origin       = "` + origin + `"
+  port         = 80
+  ssl          = false
+  hchost       = "www.example.com"
+  hcpath       = "/"
+  hcstatuscode = 200
+}
+`
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBackendConfig,
+			},
+			{
+				// Refreshing and planning without changes is allowed
+				Config:   readOnlyConfig("origin.example.com"),
+				PlanOnly: true,
+			},
+			{
+				Config:      readOnlyConfig("other.example.com"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`backend would be updated, but the provider is configured with\s+'read_only'`),
+			},
+			{
+				Config: readOnlyConfig("origin.example.com") + `
+resource "transparentedge_site" "test" {
+  domain = "www.example.com"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`site would be created`),
+			},
+			{
+				// Back to a writable provider so the backend can be destroyed
+				Config: testAccBackendConfig,
+			},
+		},
+	})
+}
+
+func TestAccProvider_proxyAndUserAgent(t *testing.T) {
+	srv := acctest.NewServer(t)
+	// Only reachable through the proxy, the test server acting as both
+	t.Setenv("TCDN_API_URL", "http://api.transparentedge.test")
+	t.Setenv("TCDN_PROXY_URL", srv.URL)
+	t.Setenv("TCDN_REQUEST_TIMEOUT", "10")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "transparentedge" {
+  proxy_url = "proxy.example.com:3128"
+}
+` + testAccBackendConfig,
+				ExpectError: regexp.MustCompile(`Invalid Proxy URL`),
+			},
+			{
+				Config: `
+provider "transparentedge" {
+  user_agent_suffix = "drift-detection"
+}
+` + testAccBackendConfig,
+				Check: func(*terraform.State) error {
+					if srv.ProxiedRequests() == 0 {
+						return errors.New("no request went through the proxy")
+					}
+
+					if ua := srv.UserAgent(); !strings.HasSuffix(ua, " drift-detection") {
+						return fmt.Errorf("unexpected User-Agent %q", ua)
+					}
+
+					return nil
+				},
+			},
+		},
+	})
+}
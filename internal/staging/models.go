@@ -11,7 +11,8 @@ import (
 // apiEnv is the API environment managed by this package.
 const apiEnv = teclient.StagingEnv
 
-type StagingBackend struct {
+// StagingBackendAttributes are the attributes of a staging backend, shared by the staging backends listing.
+type StagingBackendAttributes struct {
 	ID           types.Int64  `tfsdk:"id"`
 	Company      types.Int64  `tfsdk:"company"`
 	Name         types.String `tfsdk:"name"`
@@ -28,6 +29,29 @@ type StagingBackend struct {
 }
 
 type StagingVCLConf struct {
+	CompanyID          types.Int64              `tfsdk:"company_id"`
+	ID                 types.Int64              `tfsdk:"id"`
+	Company            types.Int64              `tfsdk:"company"`
+	VCLCode            customtypes.VCLCodeValue `tfsdk:"vclcode"`
+	UploadDate         types.String             `tfsdk:"uploaddate"`
+	ProductionDate     types.String             `tfsdk:"productiondate"`
+	User               types.String             `tfsdk:"user"`
+	Comment            types.String             `tfsdk:"comment"`
+	ReferencedBackends types.Set                `tfsdk:"referenced_backends"`
+	DiffContextLines   types.Int64              `tfsdk:"diff_context_lines"`
+	Snippets           types.List               `tfsdk:"snippets"`
+	Timeouts           timeouts.Value           `tfsdk:"timeouts"`
+}
+
+type StagingBackend struct {
+	StagingBackendAttributes
+
+	CompanyID types.Int64 `tfsdk:"company_id"`
+}
+
+// StagingActiveVCLConf is the StagingVCLConf without timeouts, for the data source.
+type StagingActiveVCLConf struct {
+	CompanyID      types.Int64              `tfsdk:"company_id"`
 	ID             types.Int64              `tfsdk:"id"`
 	Company        types.Int64              `tfsdk:"company"`
 	VCLCode        customtypes.VCLCodeValue `tfsdk:"vclcode"`
@@ -35,5 +59,15 @@ type StagingVCLConf struct {
 	ProductionDate types.String             `tfsdk:"productiondate"`
 	User           types.String             `tfsdk:"user"`
 	Comment        types.String             `tfsdk:"comment"`
-	Timeouts       timeouts.Value           `tfsdk:"timeouts"`
+}
+
+// StagingBackendIdentity identifies a staging backend by its company and name.
+type StagingBackendIdentity struct {
+	CompanyID types.Int64  `tfsdk:"company_id"`
+	Name      types.String `tfsdk:"name"`
+}
+
+// StagingVCLConfIdentity identifies the active staging VCL configuration, a single one per company.
+type StagingVCLConfIdentity struct {
+	CompanyID types.Int64 `tfsdk:"company_id"`
 }
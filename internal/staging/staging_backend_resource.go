@@ -10,16 +10,19 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -28,8 +31,24 @@ var (
 	_ resource.Resource                = &stagingBackendResource{}
 	_ resource.ResourceWithConfigure   = &stagingBackendResource{}
 	_ resource.ResourceWithImportState = &stagingBackendResource{}
+	_ resource.ResourceWithModifyPlan  = &stagingBackendResource{}
+	_ resource.ResourceWithIdentity    = &stagingBackendResource{}
 )
 
+// stagingBackendAPIFields maps the API fields of a staging backend to the resource attributes.
+var stagingBackendAPIFields = map[string]path.Path{
+	"name":          path.Root("name"),
+	"origin":        path.Root("origin"),
+	"ssl":           path.Root("ssl"),
+	"port":          path.Root("port"),
+	"extra_headers": path.Root("headers"),
+	"host":          path.Root("hchost"),
+	"health_check":  path.Root("hcpath"),
+	"status_code":   path.Root("hcstatuscode"),
+	"interval":      path.Root("hcinterval"),
+	"no_probe":      path.Root("hcdisabled"),
+}
+
 // NewStagingBackendResource is a helper function to simplify the provider implementation.
 func NewStagingBackendResource() resource.Resource {
 	return &stagingBackendResource{}
@@ -43,6 +62,8 @@ type stagingBackendResource struct {
 // Metadata returns the resource type name.
 func (*stagingBackendResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_staging_backend"
+	// Renaming a backend changes its identity
+	resp.ResourceBehavior.MutableIdentity = true
 }
 
 // Schema defines the schema for the resource.
@@ -52,6 +73,7 @@ func (*stagingBackendResource) Schema(_ context.Context, _ resource.SchemaReques
 		MarkdownDescription: "Provides a Staging Backend resource. This allows backends to be created, updated and deleted.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDResourceAttribute("staging backend"),
 			"id": schema.Int64Attribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.Int64{
@@ -79,7 +101,10 @@ func (*stagingBackendResource) Schema(_ context.Context, _ resource.SchemaReques
 				MarkdownDescription: "Name of the staging backend.",
 			},
 			"vclname": schema.StringAttribute{
-				Computed:            true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Description:         "Final unique name of the backend to be referenced in VCL Code: 'c{company_id}_{name}'.",
 				MarkdownDescription: "Final unique name of the backend to be referenced in VCL Code: `c{company_id}_{name}`.",
 			},
@@ -165,6 +190,8 @@ func (r *stagingBackendResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	tflog.Info(ctx, "Creating Staging Backend: "+plan.Name.ValueString())
 	newStagingBackend := teclient.NewBackendAPIModel{
 		Name:         plan.Name.ValueString(),
@@ -179,12 +206,10 @@ func (r *stagingBackendResource) Create(ctx context.Context, req resource.Create
 		HCDisabled:   plan.HCDisabled.ValueBool(),
 	}
 
-	stagingBackendState, errCreate := r.client.CreateBackend(newStagingBackend, apiEnv)
+	stagingBackendState, errCreate := r.client.CreateBackend(ctx, newStagingBackend, apiEnv)
 	if errCreate != nil {
-		resp.Diagnostics.AddError(
-			"Error creating staging backend",
-			fmt.Sprintf("Could not create the staging backend '%s': %s", plan.Name.ValueString(), errCreate),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error creating staging backend",
+			fmt.Sprintf("Could not create the staging backend '%s'", plan.Name.ValueString()), errCreate, stagingBackendAPIFields)
 
 		return
 	}
@@ -204,7 +229,9 @@ func (r *stagingBackendResource) Create(ctx context.Context, req resource.Create
 	plan.HCInterval = types.Int64Value(int64(stagingBackendState.HCInterval))
 	plan.HCDisabled = types.BoolValue(stagingBackendState.HCDisabled)
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, StagingBackendIdentity{CompanyID: plan.CompanyID, Name: plan.Name})...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
@@ -218,6 +245,8 @@ func (r *stagingBackendResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	tflog.Info(ctx, "Updating Staging Backend: "+plan.Name.ValueString())
 	newStagingBackend := teclient.BackendAPIModel{
 		ID:           int(plan.ID.ValueInt64()),
@@ -234,12 +263,10 @@ func (r *stagingBackendResource) Update(ctx context.Context, req resource.Update
 		HCDisabled:   plan.HCDisabled.ValueBool(),
 	}
 
-	stagingBackendState, errCreate := r.client.UpdateBackend(newStagingBackend, apiEnv)
+	stagingBackendState, errCreate := r.client.UpdateBackend(ctx, newStagingBackend, apiEnv)
 	if errCreate != nil {
-		resp.Diagnostics.AddError(
-			"Error updating staging backend",
-			fmt.Sprintf("Could not update the staging backend '%s': %s", plan.Name.ValueString(), errCreate),
-		)
+		helpers.AddAPIError(&resp.Diagnostics, "Error updating staging backend",
+			fmt.Sprintf("Could not update the staging backend '%s'", plan.Name.ValueString()), errCreate, stagingBackendAPIFields)
 
 		return
 	}
@@ -259,7 +286,9 @@ func (r *stagingBackendResource) Update(ctx context.Context, req resource.Update
 	plan.HCInterval = types.Int64Value(int64(stagingBackendState.HCInterval))
 	plan.HCDisabled = types.BoolValue(stagingBackendState.HCDisabled)
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, StagingBackendIdentity{CompanyID: plan.CompanyID, Name: plan.Name})...)
 }
 
 // Read resource information.
@@ -274,54 +303,60 @@ func (r *stagingBackendResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	var (
+		stagingBackend *teclient.BackendAPIModel
+		err            error
+	)
+
 	// Try to find by ID
 	if !state.ID.IsNull() {
-		stagingBackend, err := r.client.GetBackend(int(state.ID.ValueInt64()), apiEnv)
-		if err == nil {
-			state.ID = types.Int64Value(int64(stagingBackend.ID))
-			state.Company = types.Int64Value(int64(stagingBackend.Company))
-			state.Name = types.StringValue(stagingBackend.Name)
-			state.VclName = types.StringValue("c" + strconv.Itoa(stagingBackend.Company) + "_" + stagingBackend.Name)
-			state.Origin = types.StringValue(stagingBackend.Origin)
-			state.Ssl = types.BoolValue(stagingBackend.Ssl)
-			state.Port = types.Int64Value(int64(stagingBackend.Port))
-			state.Headers = types.StringValue(stagingBackend.Headers)
-			state.HCHost = types.StringValue(stagingBackend.HCHost)
-			state.HCPath = types.StringValue(stagingBackend.HCPath)
-			state.HCStatusCode = types.Int64Value(int64(stagingBackend.HCStatusCode))
-			state.HCInterval = types.Int64Value(int64(stagingBackend.HCInterval))
-			state.HCDisabled = types.BoolValue(stagingBackend.HCDisabled)
-			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-
-			return
-		}
+		stagingBackend, err = r.client.GetBackend(ctx, int(state.ID.ValueInt64()), apiEnv)
 	}
 
-	// Try to find by Name
-	stagingBackend, err := r.client.GetBackendByName(state.Name.ValueString(), apiEnv)
-	if err == nil {
-		if stagingBackend.Name == state.Name.ValueString() {
-			state.ID = types.Int64Value(int64(stagingBackend.ID))
-			state.Company = types.Int64Value(int64(stagingBackend.Company))
-			state.Name = types.StringValue(stagingBackend.Name)
-			state.VclName = types.StringValue("c" + strconv.Itoa(stagingBackend.Company) + "_" + stagingBackend.Name)
-			state.Origin = types.StringValue(stagingBackend.Origin)
-			state.Ssl = types.BoolValue(stagingBackend.Ssl)
-			state.Port = types.Int64Value(int64(stagingBackend.Port))
-			state.Headers = types.StringValue(stagingBackend.Headers)
-			state.HCHost = types.StringValue(stagingBackend.HCHost)
-			state.HCPath = types.StringValue(stagingBackend.HCPath)
-			state.HCStatusCode = types.Int64Value(int64(stagingBackend.HCStatusCode))
-			state.HCInterval = types.Int64Value(int64(stagingBackend.HCInterval))
-			state.HCDisabled = types.BoolValue(stagingBackend.HCDisabled)
-			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
-
-			return
-		}
+	// Try to find by Name, imports only know the name
+	if state.ID.IsNull() || teclient.IsNotFound(err) {
+		stagingBackend, err = r.client.GetBackendByName(ctx, state.Name.ValueString(), apiEnv)
 	}
 
-	// Not found
-	resp.Diagnostics.AddError("Staging Backend not found", "Staging Backend '"+state.Name.ValueString()+"' doesn't exist in API")
+	if teclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Staging backend '"+state.Name.ValueString()+"' no longer exists, removing it from the state")
+		resp.State.RemoveResource(ctx)
+
+		return
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading staging backend",
+			fmt.Sprintf("Could not read the staging backend '%s': %s", state.Name.ValueString(), err),
+		)
+
+		return
+	}
+
+	state.fromAPI(stagingBackend)
+	state.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, StagingBackendIdentity{CompanyID: state.CompanyID, Name: state.Name})...)
+}
+
+// fromAPI sets the attributes read from the API, for Read, the staging backends listing and the list resource.
+func (b *StagingBackendAttributes) fromAPI(stagingBackend *teclient.BackendAPIModel) {
+	b.ID = types.Int64Value(int64(stagingBackend.ID))
+	b.Company = types.Int64Value(int64(stagingBackend.Company))
+	b.Name = types.StringValue(stagingBackend.Name)
+	b.VclName = types.StringValue("c" + strconv.Itoa(stagingBackend.Company) + "_" + stagingBackend.Name)
+	b.Origin = types.StringValue(stagingBackend.Origin)
+	b.Ssl = types.BoolValue(stagingBackend.Ssl)
+	b.Port = types.Int64Value(int64(stagingBackend.Port))
+	b.Headers = types.StringValue(stagingBackend.Headers)
+	b.HCHost = types.StringValue(stagingBackend.HCHost)
+	b.HCPath = types.StringValue(stagingBackend.HCPath)
+	b.HCStatusCode = types.Int64Value(int64(stagingBackend.HCStatusCode))
+	b.HCInterval = types.Int64Value(int64(stagingBackend.HCInterval))
+	b.HCDisabled = types.BoolValue(stagingBackend.HCDisabled)
 }
 
 // Delete.
@@ -335,10 +370,12 @@ func (r *stagingBackendResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	// 204 on successful delete
 	tflog.Info(ctx, "Deleting Staging Backend: '"+state.Name.ValueString()+"' with id: "+state.ID.String())
 
-	err := r.client.DeleteBackend(int(state.ID.ValueInt64()), apiEnv)
+	err := r.client.DeleteBackend(ctx, int(state.ID.ValueInt64()), apiEnv)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting a Staging Backend",
@@ -349,6 +386,32 @@ func (r *stagingBackendResource) Delete(ctx context.Context, req resource.Delete
 	}
 }
 
+// ModifyPlan refuses any change in read-only mode, and plans the vclname of a renamed backend.
+func (r *stagingBackendResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	helpers.CheckReadOnlyPlan(r.client, "staging backend", req, resp)
+
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var state, plan StagingBackend
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || plan.Name.Equal(state.Name) {
+		return
+	}
+
+	// vclname is otherwise kept from the state, changing the company replaces the backend
+	vclName := types.StringUnknown()
+	if !plan.Name.IsUnknown() {
+		vclName = types.StringValue("c" + strconv.FormatInt(state.Company.ValueInt64(), 10) + "_" + plan.Name.ValueString())
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("vclname"), vclName)...)
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *stagingBackendResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -365,6 +428,31 @@ func (r *stagingBackendResource) Configure(_ context.Context, req resource.Confi
 	r.client = client
 }
 
+// IdentitySchema defines the identity of the resource, to import it with an identity instead of an ID.
+func (*stagingBackendResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"company_id": helpers.CompanyIDIdentityAttribute(),
+			"name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Name of the staging backend.",
+			},
+		},
+	}
+}
+
 func (*stagingBackendResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	// Imported with an identity instead of an ID
+	if req.ID == "" {
+		helpers.ImportFromIdentity(ctx, req, resp)
+
+		return
+	}
+
+	importID, ok := helpers.ImportCompanyID(ctx, req, resp)
+	if !ok {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), importID)...)
 }
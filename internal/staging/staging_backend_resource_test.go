@@ -0,0 +1,128 @@
+package staging_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+)
+
+func testAccStagingBackendConfig(name string, origin string) string {
+	return `
+resource "transparentedge_staging_backend" "test" {
+  name         = "` + name + `"
+  origin       = "` + origin + `"
+  port         = 80
+  ssl          = false
+  hchost       = "www.example.com"
+  hcpath       = "/"
+  hcstatuscode = 200
+  hcdisabled   = true
+}
+`
+}
+
+func TestAccStagingBackendResource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStagingBackendConfig("origin1", "origin.example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_staging_backend.test", "id"),
+					resource.TestCheckResourceAttr("transparentedge_staging_backend.test", "vclname", "c300_origin1"),
+					resource.TestCheckResourceAttr("transparentedge_staging_backend.test", "hcdisabled", "true"),
+				),
+			},
+			{
+				ResourceName:      "transparentedge_staging_backend.test",
+				ImportState:       true,
+				ImportStateId:     "origin1",
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccStagingBackendConfig("origin1", "origin2.example.com"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_staging_backend.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("transparentedge_staging_backend.test", "origin", "origin2.example.com"),
+			},
+		},
+	})
+}
+
+// Staging and production backends live in separate namespaces.
+func TestAccStagingBackendResource_sameNameAsProduction(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStagingBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_backend" "test" {
+  name         = "origin1"
+  origin       = "origin.example.com"
+  port         = 80
+  ssl          = false
+  hchost       = "www.example.com"
+  hcpath       = "/"
+  hcstatuscode = 200
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_staging_backend.test", "id"),
+					resource.TestCheckResourceAttrSet("transparentedge_backend.test", "id"),
+				),
+			},
+			{
+				Config: testAccStagingBackendConfig("origin1", "origin.example.com") + `
+resource "transparentedge_staging_backend" "duplicated" {
+  name         = "origin1"
+  origin       = "other.example.com"
+  port         = 80
+  ssl          = false
+  hchost       = "www.example.com"
+  hcpath       = "/"
+  hcstatuscode = 200
+}
+`,
+				ExpectError: regexp.MustCompile(`backend with this name\s+already\s+exists`),
+			},
+		},
+	})
+}
+
+func TestAccStagingBackendDataSources(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStagingBackendConfig("origin1", "origin.example.com") + `
+data "transparentedge_staging_backend" "test" {
+  name = transparentedge_staging_backend.test.name
+}
+
+data "transparentedge_staging_backends" "all" {
+  depends_on = [transparentedge_staging_backend.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.transparentedge_staging_backend.test", "id", "transparentedge_staging_backend.test", "id"),
+					resource.TestCheckResourceAttr("data.transparentedge_staging_backend.test", "origin", "origin.example.com"),
+					resource.TestCheckResourceAttr("data.transparentedge_staging_backends.all", "staging_backends.#", "1"),
+					resource.TestCheckResourceAttr("data.transparentedge_staging_backends.all", "staging_backends.0.name", "origin1"),
+				),
+			},
+		},
+	})
+}
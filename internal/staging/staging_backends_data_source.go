@@ -2,12 +2,12 @@ package staging
 
 import (
 	"context"
-	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
 	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
 )
 
@@ -29,7 +29,8 @@ type stagingBackendsDataSource struct {
 
 // maps the data source schema data.
 type stagingBackendsDataSourceModel struct {
-	StagingBackends []StagingBackend `tfsdk:"staging_backends"`
+	CompanyID       types.Int64                `tfsdk:"company_id"`
+	StagingBackends []StagingBackendAttributes `tfsdk:"staging_backends"`
 }
 
 // Metadata returns the data source type name.
@@ -44,6 +45,7 @@ func (*stagingBackendsDataSource) Schema(_ context.Context, _ datasource.SchemaR
 		MarkdownDescription: "Staging backend listing.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDDataSourceAttribute("staging backends"),
 			"staging_backends": schema.ListNestedAttribute{
 				Computed:    true,
 				Description: "List of all staging backends.",
@@ -121,10 +123,18 @@ func (*stagingBackendsDataSource) Schema(_ context.Context, _ datasource.SchemaR
 }
 
 // Read refreshes the Terraform state with the latest data.
-func (d *stagingBackendsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (d *stagingBackendsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state stagingBackendsDataSourceModel
 
-	stagingBackends, err := d.client.GetBackends(apiEnv)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	stagingBackends, err := d.client.GetBackends(ctx, apiEnv)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to read Staging Backends info",
@@ -136,26 +146,14 @@ func (d *stagingBackendsDataSource) Read(ctx context.Context, _ datasource.ReadR
 
 	// Map response body to model
 	for _, stagingBackend := range stagingBackends {
-		stagingBackendState := StagingBackend{
-			ID:           types.Int64Value(int64(stagingBackend.ID)),
-			Company:      types.Int64Value(int64(stagingBackend.Company)),
-			Name:         types.StringValue(stagingBackend.Name),
-			VclName:      types.StringValue("c" + strconv.Itoa(stagingBackend.Company) + "_" + stagingBackend.Name),
-			Origin:       types.StringValue(stagingBackend.Origin),
-			Ssl:          types.BoolValue(stagingBackend.Ssl),
-			Port:         types.Int64Value(int64(stagingBackend.Port)),
-			Headers:      types.StringValue(stagingBackend.Headers),
-			HCHost:       types.StringValue(stagingBackend.HCHost),
-			HCPath:       types.StringValue(stagingBackend.HCPath),
-			HCStatusCode: types.Int64Value(int64(stagingBackend.HCStatusCode)),
-			HCInterval:   types.Int64Value(int64(stagingBackend.HCInterval)),
-			HCDisabled:   types.BoolValue(stagingBackend.HCDisabled),
-		}
+		var stagingBackendState StagingBackendAttributes
+		stagingBackendState.fromAPI(&stagingBackend)
 
 		state.StagingBackends = append(state.StagingBackends, stagingBackendState)
 	}
 
 	// Set state
+	state.CompanyID = helpers.CompanyIDValue(ctx, d.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
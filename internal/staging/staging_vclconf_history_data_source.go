@@ -0,0 +1,74 @@
+package staging
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/helpers"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &stagingVclConfHistoryDataSource{}
+	_ datasource.DataSourceWithConfigure = &stagingVclConfHistoryDataSource{}
+)
+
+// NewStagingVclconfHistoryDataSource is a helper function to simplify the provider implementation.
+func NewStagingVclconfHistoryDataSource() datasource.DataSource {
+	return &stagingVclConfHistoryDataSource{}
+}
+
+// data source implementation.
+type stagingVclConfHistoryDataSource struct {
+	client *teclient.Client
+}
+
+// Metadata returns the data source type name.
+func (*stagingVclConfHistoryDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_staging_vclconf_history"
+}
+
+// Schema defines the schema for the data source.
+func (*stagingVclConfHistoryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = helpers.VCLConfHistorySchema("staging")
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *stagingVclConfHistoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state helpers.VCLConfHistory
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	helpers.ReadVCLConfHistory(ctx, d.client, apiEnv, &state, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.CompanyID = helpers.CompanyIDValue(ctx, d.client)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *stagingVclConfHistoryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*teclient.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to configure", "error while configuring API client")
+
+		return
+	}
+
+	d.client = client
+}
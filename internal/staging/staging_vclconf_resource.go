@@ -2,6 +2,7 @@ package staging
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
@@ -24,12 +26,20 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &stagingVclConfResource{}
-	_ resource.ResourceWithConfigure   = &stagingVclConfResource{}
-	_ resource.ResourceWithImportState = &stagingVclConfResource{}
-	_ resource.ResourceWithModifyPlan  = &stagingVclConfResource{}
+	_ resource.Resource                   = &stagingVclConfResource{}
+	_ resource.ResourceWithConfigure      = &stagingVclConfResource{}
+	_ resource.ResourceWithImportState    = &stagingVclConfResource{}
+	_ resource.ResourceWithModifyPlan     = &stagingVclConfResource{}
+	_ resource.ResourceWithValidateConfig = &stagingVclConfResource{}
+	_ resource.ResourceWithIdentity       = &stagingVclConfResource{}
 )
 
+// stagingVCLConfAPIFields maps the API fields of a VCL configuration to the resource attributes.
+var stagingVCLConfAPIFields = map[string]path.Path{
+	"config_body": path.Root("vclcode"),
+	"comment":     path.Root("comment"),
+}
+
 // NewStagingVclconfResource is a helper function to simplify the provider implementation.
 func NewStagingVclconfResource() resource.Resource {
 	return &stagingVclConfResource{}
@@ -51,9 +61,12 @@ func (*stagingVclConfResource) Schema(ctx context.Context, _ resource.SchemaRequ
 		Description: "Manages Staging VCL Configuration.",
 		MarkdownDescription: "Provides Staging VCL Configuration resource. This allows to generate a new VCL configuration that replaces the current one." +
 			" Changing `vclcode` or `comment` uploads a new configuration version in place (no destroy/recreate)." +
-			" Destroying the resource uploads an empty VCL configuration so that any backends referenced by the current code can be removed afterwards.",
+			" Destroying the resource uploads an empty VCL configuration so that any backends referenced by the current code can be removed afterwards." +
+			" The code is checked for backends of the company that don't exist: the plan only warns about them, since they may be created in the same apply," +
+			" and the upload fails if they still don't exist then. Use the `vclname` attribute of those backends, or add them to `depends_on`, so they're created first.",
 
 		Attributes: map[string]schema.Attribute{
+			"company_id": helpers.CompanyIDResourceAttribute("staging VCL configuration"),
 			"id": schema.Int64Attribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.Int64{
@@ -71,14 +84,19 @@ func (*stagingVclConfResource) Schema(ctx context.Context, _ resource.SchemaRequ
 				MarkdownDescription: "Company ID that owns this Staging VCL Config.",
 			},
 			"vclcode": schema.StringAttribute{
-				Required:   true,
+				Optional:   true,
+				Computed:   true,
 				CustomType: customtypes.VCLCodeType{},
 				Description: "Verbatim of the VCL (Varnish Configuration Language) code configuration to apply." +
 					" After a successful code upload, it may take between 5 and 10 minutes for the new configuration to be fully applied." +
-					" You can know if a configuration is already in **staging** by running 'terraform plan' and checking the 'productiondate' field.",
+					" You can know if a configuration is already in **staging** by running 'terraform plan' and checking the 'productiondate' field." +
+					" The syntax of the code is checked by 'terraform validate', the API compiles it when it's uploaded." +
+					" Either this or 'snippets' must be set.",
 				MarkdownDescription: "Verbatim of the VCL (_Varnish Configuration Language_) code configuration to apply." +
 					" After a successful code upload, it may take between 5 and 10 minutes for the new configuration to be fully replicated in all the CDN edge nodes." +
-					" You can check if a configuration is already in **staging** by running `terraform plan` and checking the `productiondate` field.",
+					" You can check if a configuration is already in **staging** by running `terraform plan` and checking the `productiondate` field." +
+					" The syntax of the code is checked by `terraform validate`, the API compiles it when it's uploaded." +
+					" Either this or `snippets` must be set.",
 			},
 			"uploaddate": schema.StringAttribute{
 				Computed: true,
@@ -111,6 +129,8 @@ func (*stagingVclConfResource) Schema(ctx context.Context, _ resource.SchemaRequ
 				Description:         "Optional comment describing the changes introduced by this configuration.",
 				MarkdownDescription: "Optional comment describing the changes introduced by this configuration.",
 			},
+			"referenced_backends": helpers.ReferencedBackendsAttribute(),
+			"diff_context_lines":  helpers.DiffContextLinesAttribute(),
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 				CreateDescription: "If set, the provider will wait until the VCL configuration is fully deployed " +
@@ -118,6 +138,9 @@ func (*stagingVclConfResource) Schema(ctx context.Context, _ resource.SchemaRequ
 					"greater than 5m, since propagation typically takes between 5 and 10 minutes (e.g. \"15m\").",
 			}),
 		},
+		Blocks: map[string]schema.Block{
+			"snippets": helpers.SnippetsBlock(),
+		},
 	}
 }
 
@@ -133,6 +156,8 @@ func (r *stagingVclConfResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	tflog.Info(ctx, "Creating new VCL configuration")
 
 	r.pushVCLConf(ctx, &plan, &resp.Diagnostics)
@@ -141,7 +166,10 @@ func (r *stagingVclConfResource) Create(ctx context.Context, req resource.Create
 		return
 	}
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	plan.ReferencedBackends = helpers.ReferencedBackendsValue(plan.VCLCode.StringValue)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, StagingVCLConfIdentity{CompanyID: plan.CompanyID})...)
 }
 
 // Update pushes a new VCL configuration version whenever vclcode or comment actually
@@ -156,9 +184,14 @@ func (r *stagingVclConfResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, plan.CompanyID)
+
 	if helpers.VCLSemanticEquals(state.VCLCode.ValueString(), plan.VCLCode.ValueString()) &&
 		state.Comment.ValueString() == plan.Comment.ValueString() {
+		plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+		plan.ReferencedBackends = helpers.ReferencedBackendsValue(plan.VCLCode.StringValue)
 		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, StagingVCLConfIdentity{CompanyID: plan.CompanyID})...)
 
 		return
 	}
@@ -171,7 +204,10 @@ func (r *stagingVclConfResource) Update(ctx context.Context, req resource.Update
 		return
 	}
 
+	plan.CompanyID = helpers.CompanyIDValue(ctx, r.client)
+	plan.ReferencedBackends = helpers.ReferencedBackendsValue(plan.VCLCode.StringValue)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, StagingVCLConfIdentity{CompanyID: plan.CompanyID})...)
 }
 
 // Read resource information.
@@ -186,7 +222,9 @@ func (r *stagingVclConfResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	apiResp, err := r.client.GetActiveVCLConf(apiEnv)
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
+	apiResp, err := r.client.GetActiveVCLConf(ctx, apiEnv)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to read Staging VclConf info",
@@ -206,7 +244,10 @@ func (r *stagingVclConfResource) Read(ctx context.Context, req resource.ReadRequ
 	// by the API client, so syncing it here always matches, including right after an Import.
 	state.Comment = types.StringValue(apiResp.Comment)
 
+	state.ReferencedBackends = helpers.ReferencedBackendsValue(state.VCLCode.StringValue)
+	state.CompanyID = helpers.CompanyIDValue(ctx, r.client)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, StagingVCLConfIdentity{CompanyID: state.CompanyID})...)
 }
 
 // Delete uploads an empty VCL configuration so any backends referenced by the
@@ -221,6 +262,8 @@ func (r *stagingVclConfResource) Delete(ctx context.Context, req resource.Delete
 		return
 	}
 
+	ctx = helpers.CompanyContext(ctx, state.CompanyID)
+
 	tflog.Info(ctx, "Emptying VCL configuration so any referenced backends can be deleted")
 
 	emptyConf := teclient.NewVCLConfAPIModel{
@@ -228,7 +271,7 @@ func (r *stagingVclConfResource) Delete(ctx context.Context, req resource.Delete
 		Comment: "Emptied by 'terraform destroy'",
 	}
 
-	_, errCreate := r.client.CreateVclconf(emptyConf, apiEnv)
+	_, errCreate := r.client.CreateVclconf(ctx, emptyConf, apiEnv)
 	if errCreate != nil {
 		resp.Diagnostics.AddError(
 			"Error emptying Staging VCL Configuration",
@@ -237,10 +280,30 @@ func (r *stagingVclConfResource) Delete(ctx context.Context, req resource.Delete
 	}
 }
 
+// ValidateConfig checks that the VCL code is set either in vclcode or in snippets.
+func (*stagingVclConfResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config StagingVCLConf
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	helpers.ValidateVCLSource(ctx, config.VCLCode.StringValue, config.Snippets, &resp.Diagnostics)
+}
+
 // ModifyPlan marks the computed attributes as unknown whenever a new VCL configuration
 // version is going to be uploaded (i.e. vclcode or comment change), since the API always
-// assigns fresh values (id, dates, ...) to every uploaded version.
-func (*stagingVclConfResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+// assigns fresh values (id, dates, ...) to every uploaded version, and reports the changes of
+// the code as a unified diff.
+func (r *stagingVclConfResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	helpers.CheckReadOnlyPlan(r.client, "staging VCL configuration", req, resp)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// If the entire plan is null, the resource is planned for destruction.
 	if req.Plan.Raw.IsNull() {
 		resp.Diagnostics.AddWarning(
@@ -253,15 +316,32 @@ func (*stagingVclConfResource) ModifyPlan(ctx context.Context, req resource.Modi
 		return
 	}
 
+	var plan StagingVCLConf
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// vclcode is assembled from the snippets when they're set
+	if plan.Snippets.IsUnknown() || len(plan.Snippets.Elements()) > 0 {
+		plan.VCLCode = customtypes.VCLCodeValue{StringValue: helpers.AssembleSnippets(ctx, plan.Snippets, &resp.Diagnostics)}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("vclcode"), plan.VCLCode)...)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("referenced_backends"), helpers.ReferencedBackendsValue(plan.VCLCode.StringValue))...)
+
 	// Nothing to compare against yet, this is a resource creation.
 	if req.State.Raw.IsNull() {
+		r.checkReferencedBackends(ctx, plan, &resp.Diagnostics)
+
 		return
 	}
 
-	var state, plan StagingVCLConf
+	var state StagingVCLConf
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -280,6 +360,22 @@ func (*stagingVclConfResource) ModifyPlan(ctx context.Context, req resource.Modi
 	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("uploaddate"), types.StringUnknown())...)
 	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("productiondate"), types.StringUnknown())...)
 	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("user"), types.StringUnknown())...)
+
+	if !plan.VCLCode.IsUnknown() {
+		helpers.AddVCLDiffWarning(&resp.Diagnostics, state.VCLCode.ValueString(), plan.VCLCode.ValueString(), plan.DiffContextLines)
+	}
+
+	r.checkReferencedBackends(ctx, plan, &resp.Diagnostics)
+}
+
+// checkReferencedBackends warns about the backends used by the planned code that don't exist yet.
+func (r *stagingVclConfResource) checkReferencedBackends(ctx context.Context, plan StagingVCLConf, diags *diag.Diagnostics) {
+	// The code is unknown when it uses the vclname of a backend created or changed in the same plan
+	if plan.VCLCode.IsUnknown() {
+		return
+	}
+
+	helpers.WarnReferencedBackends(helpers.CompanyContext(ctx, plan.CompanyID), r.client, apiEnv, plan.VCLCode.ValueString(), diags)
 }
 
 // Configure adds the provider configured client to the resource.
@@ -298,10 +394,31 @@ func (r *stagingVclConfResource) Configure(_ context.Context, req resource.Confi
 	r.client = client
 }
 
+// IdentitySchema defines the identity of the resource, to import it with an identity instead of an ID.
+func (*stagingVclConfResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"company_id": helpers.CompanyIDIdentityAttribute(),
+		},
+	}
+}
+
 func (*stagingVclConfResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Imported with an identity instead of an ID
+	if req.ID == "" {
+		helpers.ImportFromIdentity(ctx, req, resp)
+
+		return
+	}
+
 	// path.Root here is ignored
 	// VCL Configs can be imported without issues, but they won't match perfectly
 	// the configuration because of newlines and spaces
+	// The ID value doesn't matter, but a 'company_id/' prefix selects the company
+	if _, ok := helpers.ImportCompanyID(ctx, req, resp); !ok {
+		return
+	}
+
 	resource.ImportStatePassthroughID(ctx, path.Root("user"), req, resp)
 }
 
@@ -309,17 +426,28 @@ func (*stagingVclConfResource) ImportState(ctx context.Context, req resource.Imp
 // populates the computed attributes with the API response. Used by both Create and
 // Update, since every upload produces a brand new history entry in the API.
 func (r *stagingVclConfResource) pushVCLConf(ctx context.Context, plan *StagingVCLConf, diags *diag.Diagnostics) {
+	// The backends created in the same apply exist by now, the ones still missing would fail to compile
+	helpers.CheckReferencedBackends(ctx, r.client, apiEnv, plan.VCLCode.ValueString(), diags)
+
+	if diags.HasError() {
+		return
+	}
+
 	newConf := teclient.NewVCLConfAPIModel{
 		VCLCode: plan.VCLCode.ValueString(),
 		Comment: plan.Comment.ValueString(),
 	}
 
-	apiResp, errCreate := r.client.CreateVclconf(newConf, apiEnv)
+	apiResp, errCreate := r.client.CreateVclconf(ctx, newConf, apiEnv)
 	if errCreate != nil {
-		diags.AddError(
-			"Error uploading Staging VCL Configuration",
-			fmt.Sprintf("Could not upload the Staging VCL Configuration: %s", errCreate),
-		)
+		var compileErr *teclient.VCLCompilationError
+		if errors.As(errCreate, &compileErr) {
+			diags.AddAttributeError(path.Root("vclcode"), "Error uploading Staging VCL Configuration", compileErr.Error())
+
+			return
+		}
+
+		helpers.AddAPIError(diags, "Error uploading Staging VCL Configuration", "Could not upload the Staging VCL Configuration", errCreate, stagingVCLConfAPIFields)
 
 		return
 	}
@@ -355,7 +483,7 @@ poll:
 			break poll
 
 		case <-time.After(10 * time.Second):
-			vclconf, err := r.client.GetVCLConfByID(apiEnv, apiResp.ID)
+			vclconf, err := r.client.GetVCLConfByID(pollCtx, apiEnv, apiResp.ID)
 			if err != nil {
 				continue
 			}
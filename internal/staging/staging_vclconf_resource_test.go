@@ -0,0 +1,248 @@
+package staging_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+func testAccStagingVCLConfConfig(vclcode string) string {
+	return `
+resource "transparentedge_staging_vclconf" "test" {
+  vclcode = <<-EOT
+` + vclcode + `
+  EOT
+}
+`
+}
+
+const testAccStagingVCLCode = `    sub vcl_recv {
+      set req.http.X-Staging = "true";
+    }`
+
+func TestAccStagingVCLConfResource(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStagingVCLConfConfig(testAccStagingVCLCode),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("transparentedge_staging_vclconf.test", "id"),
+					resource.TestCheckResourceAttr("transparentedge_staging_vclconf.test", "company", strconv.Itoa(testserver.DefaultCompanyID)),
+					func(_ *terraform.State) error {
+						if n := srv.CountRequests("POST", "/v1/autoprovisioning/300/config"); n != 0 {
+							return fmt.Errorf("expected no production uploads, got %d", n)
+						}
+
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "transparentedge_staging_vclconf.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccStagingVCLConfResource_compilationError(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccStagingVCLConfConfig("    sub vcl_recv { " + testserver.CompilationErrorMarker + " }"),
+				ExpectError: regexp.MustCompile(`VCL COMPILATION ERROR`),
+			},
+		},
+	})
+}
+
+// Syntax errors are reported by 'terraform validate', before any configuration is uploaded.
+func TestAccStagingVCLConfResource_syntaxError(t *testing.T) {
+	srv := acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccStagingVCLConfConfig("    sub vcl_recv {"),
+				ExpectError: regexp.MustCompile(`(?s)Invalid VCL code.*line 1, column 14.*Unbalanced braces`),
+			},
+		},
+	})
+
+	if n := srv.CountRequests("POST", "/v1/staging/300/config"); n != 0 {
+		t.Errorf("expected no uploads, got %d", n)
+	}
+}
+
+// The backends used by the code must exist in staging, the production ones don't count.
+func TestAccStagingVCLConfResource_unknownBackend(t *testing.T) {
+	acctest.NewServer(t)
+
+	productionBackend := `
+resource "transparentedge_backend" "test" {
+  name         = "origin1"
+  origin       = "origin.example.com"
+  port         = 443
+  ssl          = true
+  hchost       = "www.example.com"
+  hcpath       = "/favicon.ico"
+  hcstatuscode = 200
+}
+`
+
+	vclconf := `
+resource "transparentedge_staging_vclconf" "test" {
+  vclcode = <<-EOT
+    sub vcl_recv {
+      set req.backend_hint = %s.backend();
+    }
+  EOT
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: productionBackend,
+			},
+			{
+				Config:      productionBackend + fmt.Sprintf(vclconf, "c300_origin1"),
+				ExpectError: regexp.MustCompile(`(?s)Unknown backend.*'c300_origin1'.*doesn't\s+exist in staging`),
+			},
+			{
+				Config: testAccStagingBackendConfig("origin1", "origin.example.com") + fmt.Sprintf(vclconf, "${transparentedge_staging_backend.test.vclname}"),
+				Check:  resource.TestCheckTypeSetElemAttr("transparentedge_staging_vclconf.test", "referenced_backends.*", "c300_origin1"),
+			},
+			{
+				// Updating the backend keeps its vclname, renaming it plans the new one
+				Config: testAccStagingBackendConfig("origin1", "other.example.com") + fmt.Sprintf(vclconf, "${transparentedge_staging_backend.test.vclname}"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("transparentedge_staging_vclconf.test", plancheck.ResourceActionNoop),
+					},
+				},
+			},
+			{
+				Config: testAccStagingBackendConfig("origin2", "other.example.com") + fmt.Sprintf(vclconf, "${transparentedge_staging_backend.test.vclname}"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue("transparentedge_staging_backend.test", tfjsonpath.New("vclname"), knownvalue.StringExact("c300_origin2")),
+					},
+				},
+				Check: resource.TestCheckTypeSetElemAttr("transparentedge_staging_vclconf.test", "referenced_backends.*", "c300_origin2"),
+			},
+		},
+	})
+}
+
+// The snippets are assembled into vclcode, sorted by subroutine and priority.
+func TestAccStagingVCLConfResource_snippets(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "transparentedge_staging_vclconf" "test" {
+  vclcode = "sub vcl_recv {}"
+
+  snippets {
+    subroutine = "vcl_recv"
+    content    = "return (pass);"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Only one of 'vclcode' or 'snippets' can be set`),
+			},
+			{
+				Config: `
+resource "transparentedge_staging_vclconf" "test" {
+  snippets {
+    subroutine = "vcl_deliver"
+    content    = "unset resp.http.Via;"
+  }
+
+  snippets {
+    subroutine = "vcl_recv"
+    content    = "return (pass);"
+  }
+}
+`,
+				Check: resource.TestMatchResourceAttr("transparentedge_staging_vclconf.test", "vclcode",
+					regexp.MustCompile(`(?s)sub vcl_recv \{\n    # BEGIN snippet, priority 0\n    return \(pass\);\n    # END snippet\n.*sub vcl_deliver \{\n`)),
+			},
+		},
+	})
+}
+
+func TestAccStagingVCLConfDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStagingVCLConfConfig(testAccStagingVCLCode) + `
+data "transparentedge_staging_vclconf" "active" {
+  depends_on = [transparentedge_staging_vclconf.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.transparentedge_staging_vclconf.active", "id", "transparentedge_staging_vclconf.test", "id"),
+					resource.TestCheckResourceAttr("data.transparentedge_staging_vclconf.active", "company", strconv.Itoa(testserver.DefaultCompanyID)),
+				),
+			},
+		},
+	})
+}
+
+// The staging history doesn't include the production configurations.
+func TestAccStagingVCLConfHistoryDataSource(t *testing.T) {
+	acctest.NewServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStagingVCLConfConfig(testAccStagingVCLCode) + `
+data "transparentedge_staging_vclconf_history" "test" {
+  include_code = true
+
+  depends_on = [transparentedge_staging_vclconf.test]
+}
+
+data "transparentedge_vclconf_history" "production" {
+  depends_on = [transparentedge_staging_vclconf.test]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transparentedge_staging_vclconf_history.test", "vclconfs.#", "1"),
+					resource.TestCheckResourceAttrPair("data.transparentedge_staging_vclconf_history.test", "vclconfs.0.id", "transparentedge_staging_vclconf.test", "id"),
+					resource.TestCheckResourceAttrPair("data.transparentedge_staging_vclconf_history.test", "vclconfs.0.vclcode", "transparentedge_staging_vclconf.test", "vclcode"),
+					resource.TestCheckResourceAttr("data.transparentedge_staging_vclconf_history.test", "vclconfs.0.active", "true"),
+					resource.TestCheckResourceAttr("data.transparentedge_vclconf_history.production", "vclconfs.#", "0"),
+				),
+			},
+		},
+	})
+}
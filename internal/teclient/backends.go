@@ -1,17 +1,16 @@
 package teclient
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 )
 
-func (c *Client) GetBackend(backendID int, environment APIEnvironment) (*BackendAPIModel, error) {
+func (c *Client) GetBackend(ctx context.Context, backendID int, environment APIEnvironment) (*BackendAPIModel, error) {
 	envpath := c.MustGetAPIEnvironmentPath(environment)
 
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s/%d/backends/%d/", c.HostURL, envpath, c.CompanyID, backendID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s/%d/backends/%d/", c.HostURL, envpath, c.CompanyIDFor(ctx), backendID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -22,7 +21,7 @@ func (c *Client) GetBackend(backendID int, environment APIEnvironment) (*Backend
 	}
 
 	if sc != http.StatusOK {
-		return nil, fmt.Errorf("failed to retrieve the backend with ID %d: %s", backendID, c.parseAPIError(body))
+		return nil, newAPIError(fmt.Sprintf("failed to retrieve the backend with ID %d", backendID), req, sc, body)
 	}
 
 	backend := BackendAPIModel{}
@@ -35,21 +34,21 @@ func (c *Client) GetBackend(backendID int, environment APIEnvironment) (*Backend
 	return &backend, nil
 }
 
-func (c *Client) GetBackends(environment APIEnvironment) ([]BackendAPIModel, error) {
+func (c *Client) GetBackends(ctx context.Context, environment APIEnvironment) ([]BackendAPIModel, error) {
 	envpath := c.MustGetAPIEnvironmentPath(environment)
 
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s/%d/backends/", c.HostURL, envpath, c.CompanyID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s/%d/backends/", c.HostURL, envpath, c.CompanyIDFor(ctx)), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, sc, err := c.doRequest(req)
+	body, sc, err := c.doCachedRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if sc != http.StatusOK {
-		return nil, fmt.Errorf("failed to retrieve the list of backends: %s", c.parseAPIError(body))
+		return nil, newAPIError("failed to retrieve the list of backends", req, sc, body)
 	}
 
 	backends := []BackendAPIModel{}
@@ -62,8 +61,8 @@ func (c *Client) GetBackends(environment APIEnvironment) ([]BackendAPIModel, err
 	return backends, nil
 }
 
-func (c *Client) GetBackendByName(name string, environment APIEnvironment) (*BackendAPIModel, error) {
-	backends, err := c.GetBackends(environment)
+func (c *Client) GetBackendByName(ctx context.Context, name string, environment APIEnvironment) (*BackendAPIModel, error) {
+	backends, err := c.GetBackends(ctx, environment)
 	if err != nil {
 		return nil, err
 	}
@@ -74,24 +73,24 @@ func (c *Client) GetBackendByName(name string, environment APIEnvironment) (*Bac
 		}
 	}
 
-	return nil, fmt.Errorf("no backend named '%s' found", name)
+	return nil, fmt.Errorf("%w: no backend named '%s'", ErrNotFound, name)
 }
 
-func (c *Client) CreateBackend(backend NewBackendAPIModel, environment APIEnvironment) (*BackendAPIModel, error) {
+func (c *Client) CreateBackend(ctx context.Context, backend NewBackendAPIModel, environment APIEnvironment) (*BackendAPIModel, error) {
 	envpath := c.MustGetAPIEnvironmentPath(environment)
 
-	req, err := c.prepareJSONRequest(backend, http.MethodPost, fmt.Sprintf("%s/v1/%s/%d/backends/", c.HostURL, envpath, c.CompanyID))
+	req, err := c.prepareJSONRequest(ctx, backend, http.MethodPost, fmt.Sprintf("%s/v1/%s/%d/backends/", c.HostURL, envpath, c.CompanyIDFor(ctx)))
 	if err != nil {
 		return nil, err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("%d - %s", sc, err.Error())
+		return nil, err
 	}
 
 	if sc != http.StatusOK && sc != http.StatusCreated {
-		return nil, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, newAPIError("failed to create the backend", req, sc, body)
 	}
 
 	newBackend := BackendAPIModel{}
@@ -104,21 +103,21 @@ func (c *Client) CreateBackend(backend NewBackendAPIModel, environment APIEnviro
 	return &newBackend, nil
 }
 
-func (c *Client) UpdateBackend(backend BackendAPIModel, environment APIEnvironment) (*BackendAPIModel, error) {
+func (c *Client) UpdateBackend(ctx context.Context, backend BackendAPIModel, environment APIEnvironment) (*BackendAPIModel, error) {
 	envpath := c.MustGetAPIEnvironmentPath(environment)
 
-	req, err := c.prepareJSONRequest(backend, http.MethodPut, fmt.Sprintf("%s/v1/%s/%d/backends/%d/", c.HostURL, envpath, c.CompanyID, backend.ID))
+	req, err := c.prepareJSONRequest(ctx, backend, http.MethodPut, fmt.Sprintf("%s/v1/%s/%d/backends/%d/", c.HostURL, envpath, c.CompanyIDFor(ctx), backend.ID))
 	if err != nil {
 		return nil, err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("%d - %s", sc, err.Error())
+		return nil, err
 	}
 
 	if sc != http.StatusOK && sc != http.StatusCreated {
-		return nil, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, newAPIError(fmt.Sprintf("failed to update the backend with ID %d", backend.ID), req, sc, body)
 	}
 
 	newBackend := BackendAPIModel{}
@@ -131,10 +130,10 @@ func (c *Client) UpdateBackend(backend BackendAPIModel, environment APIEnvironme
 	return &newBackend, nil
 }
 
-func (c *Client) DeleteBackend(backendID int, environment APIEnvironment) error {
+func (c *Client) DeleteBackend(ctx context.Context, backendID int, environment APIEnvironment) error {
 	envpath := c.MustGetAPIEnvironmentPath(environment)
 
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/v1/%s/%d/backends/%d/", c.HostURL, envpath, c.CompanyID, backendID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/v1/%s/%d/backends/%d/", c.HostURL, envpath, c.CompanyIDFor(ctx), backendID), nil)
 	if err != nil {
 		return err
 	}
@@ -144,14 +143,14 @@ func (c *Client) DeleteBackend(backendID int, environment APIEnvironment) error
 		return err
 	}
 
-	if sc == http.StatusForbidden {
-		if strings.Contains(c.parseAPIError(body), "references in active config") {
-			return errors.New("cannot delete a backend with references in the active autoprovisioning configuration, please remove all the references from the configuration first")
+	if sc != http.StatusNoContent {
+		apiErr := newAPIError(fmt.Sprintf("API request failed trying to DELETE the backend ID %d", backendID), req, sc, body)
+		if sc == http.StatusForbidden && apiErr.Mentions("references in active config") {
+			apiErr.Op = "cannot delete a backend with references in the active autoprovisioning configuration, " +
+				"please remove all the references from the configuration first"
 		}
-	}
 
-	if sc != http.StatusNoContent {
-		return fmt.Errorf("%d - API request failed trying to DELETE the backend ID %d: %s", sc, backendID, c.parseAPIError(body))
+		return apiErr
 	}
 
 	return nil
@@ -0,0 +1,185 @@
+package teclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// cacheStats holds the counters of the list cache for a collection, i.e: "/v1/autoprovisioning/300/backends".
+// They're only logged, along with each lookup and invalidation, since a provider instance isn't told when the run ends.
+type cacheStats struct {
+	hits          int
+	misses        int
+	invalidations int
+}
+
+// listCache keeps the responses of the list endpoints for the lifetime of the client,
+// so resources looking themselves up in a list (backends by name, sites by domain...)
+// download it once per run instead of once per instance.
+// A collection is dropped as soon as the client sends any write request under its path.
+type listCache struct {
+	mu sync.Mutex
+	// entries by collection path and then by request URL, the offset of paginated lists is part of the URL.
+	entries map[string]map[string]*cacheEntry
+	stats   map[string]*cacheStats
+}
+
+// cacheEntry is a cached response, or one being fetched when ready is still open.
+type cacheEntry struct {
+	ready chan struct{}
+	body  []byte
+	// ok is false when the response could not be cached (transport error or non 200 status).
+	ok bool
+}
+
+func newListCache() *listCache {
+	return &listCache{
+		entries: map[string]map[string]*cacheEntry{},
+		stats:   map[string]*cacheStats{},
+	}
+}
+
+// collectionPath returns the path identifying the collection of a request, without the trailing slash.
+func collectionPath(req *http.Request) string {
+	return strings.TrimSuffix(req.URL.Path, "/")
+}
+
+// doCachedRequest sends a GET request for a list, serving it from the cache when it is enabled.
+// Concurrent requests for the same list wait for the first one instead of downloading it again.
+func (c *Client) doCachedRequest(req *http.Request) ([]byte, int, error) {
+	if c.cache == nil {
+		return c.doRequest(req)
+	}
+
+	collection := collectionPath(req)
+	key := req.URL.String()
+
+	entry, owner, stats := c.cache.lookup(collection, key)
+
+	tflog.Debug(req.Context(), "List cache lookup", map[string]any{
+		"url":           key,
+		"hit":           !owner,
+		"hits":          stats.hits,
+		"misses":        stats.misses,
+		"invalidations": stats.invalidations,
+	})
+
+	if !owner {
+		select {
+		case <-entry.ready:
+		case <-req.Context().Done():
+			return nil, 0, req.Context().Err()
+		}
+
+		if entry.ok {
+			return entry.body, http.StatusOK, nil
+		}
+
+		// The request being waited for failed, try on our own
+		return c.doRequest(req)
+	}
+
+	body, sc, err := c.doRequest(req)
+
+	entry.ok = err == nil && sc == http.StatusOK
+	if entry.ok {
+		entry.body = body
+	} else {
+		c.cache.forget(collection, key, entry)
+	}
+
+	close(entry.ready)
+
+	return body, sc, err
+}
+
+// lookup returns the entry of key, creating it if needed, and the counters of collection after the lookup.
+// owner is true when the caller created the entry and must fetch the response and close ready.
+func (l *listCache) lookup(collection string, key string) (*cacheEntry, bool, cacheStats) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.collectionStats(collection)
+
+	if entry, found := l.entries[collection][key]; found {
+		stats.hits++
+
+		return entry, false, *stats
+	}
+
+	stats.misses++
+
+	if l.entries[collection] == nil {
+		l.entries[collection] = map[string]*cacheEntry{}
+	}
+
+	entry := &cacheEntry{ready: make(chan struct{})}
+	l.entries[collection][key] = entry
+
+	return entry, true, *stats
+}
+
+// forget removes entry from the cache, unless it was replaced already.
+func (l *listCache) forget(collection string, key string, entry *cacheEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.entries[collection][key] == entry {
+		delete(l.entries[collection], key)
+	}
+}
+
+// invalidate drops the collections affected by a write request to path,
+// that is the ones whose path is path itself or one of its parents.
+func (l *listCache) invalidate(ctx context.Context, path string) {
+	path = strings.TrimSuffix(path, "/")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for collection := range l.entries {
+		if path == collection || strings.HasPrefix(path, collection+"/") {
+			delete(l.entries, collection)
+
+			stats := l.collectionStats(collection)
+			stats.invalidations++
+
+			tflog.Debug(ctx, "List cache invalidated", map[string]any{
+				"collection":    collection,
+				"hits":          stats.hits,
+				"misses":        stats.misses,
+				"invalidations": stats.invalidations,
+			})
+		}
+	}
+}
+
+// collectionStats returns the counters of collection. The caller must hold mu.
+func (l *listCache) collectionStats(collection string) *cacheStats {
+	stats, found := l.stats[collection]
+	if !found {
+		stats = &cacheStats{}
+		l.stats[collection] = stats
+	}
+
+	return stats
+}
+
+// invalidateCache drops the cached lists a write request to path may have changed.
+func (c *Client) invalidateCache(ctx context.Context, path string) {
+	if c.cache != nil {
+		c.cache.invalidate(ctx, path)
+	}
+}
+
+// invalidateCacheURL is invalidateCache for a full URL, built like the ones of the requests.
+func (c *Client) invalidateCacheURL(ctx context.Context, rawURL string) {
+	if u, err := url.Parse(rawURL); err == nil {
+		c.invalidateCache(ctx, u.Path)
+	}
+}
@@ -1,24 +1,25 @@
 package teclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 )
 
-func (c *Client) GetCertificates() ([]SSLCertificate, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/", c.HostURL, c.CompanyID), nil)
+func (c *Client) GetCertificates(ctx context.Context) ([]SSLCertificate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/", c.HostURL, c.CompanyIDFor(ctx)), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, sc, err := c.doRequest(req)
+	body, sc, err := c.doCachedRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if sc != http.StatusOK {
-		return nil, fmt.Errorf("failed retrieving the list of certificates: %s", c.parseAPIError(body))
+		return nil, newAPIError("failed retrieving the list of certificates", req, sc, body)
 	}
 
 	certs := []SSLCertificate{}
@@ -31,8 +32,8 @@ func (c *Client) GetCertificates() ([]SSLCertificate, error) {
 	return certs, nil
 }
 
-func (c *Client) GetCertificate(certID int) (*SSLCertificate, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/%d/", c.HostURL, c.CompanyID, certID), nil)
+func (c *Client) GetCertificate(ctx context.Context, certID int) (*SSLCertificate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/%d/", c.HostURL, c.CompanyIDFor(ctx), certID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +44,7 @@ func (c *Client) GetCertificate(certID int) (*SSLCertificate, error) {
 	}
 
 	if sc != http.StatusOK {
-		return nil, fmt.Errorf("failed to retrieve the Custom Certificate with ID: %d. %s", certID, c.parseAPIError(body))
+		return nil, newAPIError(fmt.Sprintf("failed to retrieve the Custom Certificate with ID: %d", certID), req, sc, body)
 	}
 
 	cert := SSLCertificate{}
@@ -56,19 +57,19 @@ func (c *Client) GetCertificate(certID int) (*SSLCertificate, error) {
 	return &cert, nil
 }
 
-func (c *Client) CreateCustomCertificate(cert SSLCustomCertificate) (*SSLCertificate, error) {
-	req, err := c.prepareJSONRequest(cert, http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/", c.HostURL, c.CompanyID))
+func (c *Client) CreateCustomCertificate(ctx context.Context, cert SSLCustomCertificate) (*SSLCertificate, error) {
+	req, err := c.prepareJSONRequest(ctx, cert, http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/", c.HostURL, c.CompanyIDFor(ctx)))
 	if err != nil {
 		return nil, err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("%d - %s", sc, err.Error())
+		return nil, err
 	}
 
 	if sc != http.StatusOK && sc != http.StatusCreated {
-		return nil, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, newAPIError("failed to create the Custom Certificate", req, sc, body)
 	}
 
 	newCustomCertificate := SSLCertificate{}
@@ -81,23 +82,23 @@ func (c *Client) CreateCustomCertificate(cert SSLCustomCertificate) (*SSLCertifi
 	return &newCustomCertificate, nil
 }
 
-func (c *Client) UpdateCustomCertificate(cert SSLCustomCertificate) (*SSLCertificate, error) {
-	req, err := c.prepareJSONRequest(cert, http.MethodPut, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/%d/", c.HostURL, c.CompanyID, cert.ID))
+func (c *Client) UpdateCustomCertificate(ctx context.Context, cert SSLCustomCertificate) (*SSLCertificate, error) {
+	req, err := c.prepareJSONRequest(ctx, cert, http.MethodPut, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/%d/", c.HostURL, c.CompanyIDFor(ctx), cert.ID))
 	if err != nil {
 		return nil, err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("%d - %s", sc, err.Error())
+		return nil, err
 	}
 
 	if sc != http.StatusOK && sc != http.StatusCreated {
-		return nil, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, newAPIError(fmt.Sprintf("failed to update the Custom Certificate with ID %d", cert.ID), req, sc, body)
 	}
 
 	// API doesnt return the new model at this moment, try to get the updated certificate
-	newCustomCertificate, err := c.GetCertificate(cert.ID)
+	newCustomCertificate, err := c.GetCertificate(ctx, cert.ID)
 	if err != nil {
 		return nil, fmt.Errorf("certificate was updated but couldn't retrieve the new data from API, an import is required: %w", err)
 	}
@@ -105,8 +106,8 @@ func (c *Client) UpdateCustomCertificate(cert SSLCustomCertificate) (*SSLCertifi
 	return newCustomCertificate, nil
 }
 
-func (c *Client) DeleteCustomCertificate(certID int) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/%d/", c.HostURL, c.CompanyID, certID), nil)
+func (c *Client) DeleteCustomCertificate(ctx context.Context, certID int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/%d/", c.HostURL, c.CompanyIDFor(ctx), certID), nil)
 	if err != nil {
 		return err
 	}
@@ -117,7 +118,7 @@ func (c *Client) DeleteCustomCertificate(certID int) error {
 	}
 
 	if sc != 204 {
-		return fmt.Errorf("%d - API request failed trying to DELETE the Certificate ID %d: %s", sc, certID, c.parseAPIError(body))
+		return newAPIError(fmt.Sprintf("API request failed trying to DELETE the Certificate ID %d", certID), req, sc, body)
 	}
 
 	return nil
@@ -1,14 +1,15 @@
 package teclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 )
 
-func (c *Client) GetCRDNSProviders() ([]CRDNSProvider, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/dnshook/", c.HostURL), nil) // nolint: perfsprint
+func (c *Client) GetCRDNSProviders(ctx context.Context) ([]CRDNSProvider, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/dnshook/", c.HostURL), nil) // nolint: perfsprint
 	if err != nil {
 		return nil, err
 	}
@@ -19,7 +20,7 @@ func (c *Client) GetCRDNSProviders() ([]CRDNSProvider, error) {
 	}
 
 	if sc != http.StatusOK {
-		return nil, fmt.Errorf("failure retrieving DNS Providers: %s", c.parseAPIError(body))
+		return nil, newAPIError("failure retrieving DNS Providers", req, sc, body)
 	}
 
 	providers := []CRDNSProvider{}
@@ -32,19 +33,20 @@ func (c *Client) GetCRDNSProviders() ([]CRDNSProvider, error) {
 	return providers, nil
 }
 
-func (c *Client) GetDNSCNAMEVerification() (string, error) {
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/ssldnsverificationcname/", c.HostURL, c.CompanyID), nil)
+func (c *Client) GetDNSCNAMEVerification(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/ssldnsverificationcname/", c.HostURL, c.CompanyIDFor(ctx)), nil)
 	if err != nil {
 		return "", err
 	}
 
-	body, sc, err := c.doRequest(req)
+	// Read-style POST, it only returns the CNAME assigned to the company
+	body, sc, err := c.doRequest(markReadRequest(markRetrySafe(req)))
 	if err != nil {
 		return "", err
 	}
 
 	if sc != http.StatusOK {
-		return "", fmt.Errorf("failure retrieving CNAME verification: %s", c.parseAPIError(body))
+		return "", newAPIError("failure retrieving CNAME verification", req, sc, body)
 	}
 
 	var data map[string]any
@@ -62,8 +64,33 @@ func (c *Client) GetDNSCNAMEVerification() (string, error) {
 	return cnameValue, nil
 }
 
-func (c *Client) GetCRDNSCredential(id int) (CRDNSCredential, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscredential/%d/", c.HostURL, c.CompanyID, id), nil)
+func (c *Client) GetCRDNSCredentials(ctx context.Context) ([]CRDNSCredential, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscredential/", c.HostURL, c.CompanyIDFor(ctx)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, sc, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if sc != http.StatusOK {
+		return nil, newAPIError("failed retrieving the list of DNS Credentials", req, sc, body)
+	}
+
+	data := []CRDNSCredential{}
+
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (c *Client) GetCRDNSCredential(ctx context.Context, id int) (CRDNSCredential, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscredential/%d/", c.HostURL, c.CompanyIDFor(ctx), id), nil)
 	if err != nil {
 		return CRDNSCredential{}, err
 	}
@@ -74,7 +101,7 @@ func (c *Client) GetCRDNSCredential(id int) (CRDNSCredential, error) {
 	}
 
 	if sc != http.StatusOK {
-		return CRDNSCredential{}, fmt.Errorf("failure retrieving DNS Credential with id %d. %s", id, c.parseAPIError(body))
+		return CRDNSCredential{}, newAPIError(fmt.Sprintf("failure retrieving DNS Credential with id %d", id), req, sc, body)
 	}
 
 	data := CRDNSCredential{}
@@ -87,28 +114,19 @@ func (c *Client) GetCRDNSCredential(id int) (CRDNSCredential, error) {
 	return data, nil
 }
 
-func (c *Client) CreateDNSCredential(dnsCredential NewCRDNSCredential) (*CRDNSCredential, error) {
-	req, err := c.prepareJSONRequest(dnsCredential, http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscredential/", c.HostURL, c.CompanyID))
+func (c *Client) CreateDNSCredential(ctx context.Context, dnsCredential NewCRDNSCredential) (*CRDNSCredential, error) {
+	req, err := c.prepareJSONRequest(ctx, dnsCredential, http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscredential/", c.HostURL, c.CompanyIDFor(ctx)))
 	if err != nil {
 		return nil, err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("%d - %s", sc, err.Error())
-	}
-
-	if sc == http.StatusBadRequest {
-		apiError := c.parseAPIError(body)
-
-		err := json.Unmarshal(body, &apiError)
-		if err == nil {
-			return nil, errors.New(apiError)
-		}
+		return nil, err
 	}
 
 	if sc != http.StatusOK && sc != http.StatusCreated {
-		return nil, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, newAPIError("failed to create the DNS Credential", req, sc, body)
 	}
 
 	newData := CRDNSCredential{}
@@ -121,28 +139,19 @@ func (c *Client) CreateDNSCredential(dnsCredential NewCRDNSCredential) (*CRDNSCr
 	return &newData, nil
 }
 
-func (c *Client) UpdateDNSCredential(dnsCredential NewCRDNSCredential, id int) (*CRDNSCredential, error) {
-	req, err := c.prepareJSONRequest(dnsCredential, http.MethodPut, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscredential/%d", c.HostURL, c.CompanyID, id))
+func (c *Client) UpdateDNSCredential(ctx context.Context, dnsCredential NewCRDNSCredential, id int) (*CRDNSCredential, error) {
+	req, err := c.prepareJSONRequest(ctx, dnsCredential, http.MethodPut, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscredential/%d", c.HostURL, c.CompanyIDFor(ctx), id))
 	if err != nil {
 		return nil, err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("%d - %s", sc, err.Error())
-	}
-
-	if sc == http.StatusBadRequest {
-		apiError := c.parseAPIError(body)
-
-		err := json.Unmarshal(body, &apiError)
-		if err == nil {
-			return nil, errors.New(apiError)
-		}
+		return nil, err
 	}
 
 	if sc != http.StatusOK && sc != http.StatusCreated {
-		return nil, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, newAPIError(fmt.Sprintf("failed to update the DNS Credential with id %d", id), req, sc, body)
 	}
 
 	newData := CRDNSCredential{}
@@ -155,8 +164,8 @@ func (c *Client) UpdateDNSCredential(dnsCredential NewCRDNSCredential, id int) (
 	return &newData, nil
 }
 
-func (c *Client) DeleteCRCredential(id int) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscredential/%d", c.HostURL, c.CompanyID, id), nil)
+func (c *Client) DeleteCRCredential(ctx context.Context, id int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscredential/%d", c.HostURL, c.CompanyIDFor(ctx), id), nil)
 	if err != nil {
 		return err
 	}
@@ -167,14 +176,39 @@ func (c *Client) DeleteCRCredential(id int) error {
 	}
 
 	if sc != 204 {
-		return fmt.Errorf("%d - DELETE Failed for ID %d: %s", sc, id, c.parseAPIError(body))
+		return newAPIError(fmt.Sprintf("DELETE failed for ID %d", id), req, sc, body)
 	}
 
 	return nil
 }
 
-func (c *Client) GetCertReqDNS(id int) (CertReqDNS, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscertrequest/%d", c.HostURL, c.CompanyID, id), nil)
+func (c *Client) GetCertReqsDNS(ctx context.Context) ([]CertReqDNS, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscertrequest/", c.HostURL, c.CompanyIDFor(ctx)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, sc, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if sc != http.StatusOK {
+		return nil, newAPIError("failed retrieving the list of DNS Certificate Requests", req, sc, body)
+	}
+
+	data := []CertReqDNS{}
+
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (c *Client) GetCertReqDNS(ctx context.Context, id int) (CertReqDNS, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscertrequest/%d", c.HostURL, c.CompanyIDFor(ctx), id), nil)
 	if err != nil {
 		return CertReqDNS{}, err
 	}
@@ -185,7 +219,7 @@ func (c *Client) GetCertReqDNS(id int) (CertReqDNS, error) {
 	}
 
 	if sc != http.StatusOK {
-		return CertReqDNS{}, fmt.Errorf("failure retrieving DNS Certificate Request with id %d. %s", id, c.parseAPIError(body))
+		return CertReqDNS{}, newAPIError(fmt.Sprintf("failure retrieving DNS Certificate Request with id %d", id), req, sc, body)
 	}
 
 	data := CertReqDNS{}
@@ -195,31 +229,27 @@ func (c *Client) GetCertReqDNS(id int) (CertReqDNS, error) {
 		return CertReqDNS{}, err
 	}
 
+	// The certificate is issued asynchronously, the cached list does not have it yet
+	if data.CertificateID != nil {
+		c.invalidateCacheURL(ctx, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/", c.HostURL, c.CompanyIDFor(ctx)))
+	}
+
 	return data, nil
 }
 
-func (c *Client) CreateDNSCertReq(certreq any) (*CertReqDNS, error) {
-	req, err := c.prepareJSONRequest(certreq, http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscertrequest/", c.HostURL, c.CompanyID))
+func (c *Client) CreateDNSCertReq(ctx context.Context, certreq any) (*CertReqDNS, error) {
+	req, err := c.prepareJSONRequest(ctx, certreq, http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscertrequest/", c.HostURL, c.CompanyIDFor(ctx)))
 	if err != nil {
 		return nil, err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("%d - %s", sc, err.Error())
-	}
-
-	if sc == http.StatusBadRequest {
-		apiError := c.parseAPIError(body)
-
-		err := json.Unmarshal(body, &apiError)
-		if err == nil {
-			return nil, errors.New(apiError)
-		}
+		return nil, err
 	}
 
 	if sc != http.StatusOK && sc != http.StatusCreated {
-		return nil, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, newAPIError("failed to create the DNS Certificate Request", req, sc, body)
 	}
 
 	newData := CertReqDNS{}
@@ -232,8 +262,8 @@ func (c *Client) CreateDNSCertReq(certreq any) (*CertReqDNS, error) {
 	return &newData, nil
 }
 
-func (c *Client) DeleteDNSCertReq(id int) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscertrequest/%d", c.HostURL, c.CompanyID, id), nil)
+func (c *Client) DeleteDNSCertReq(ctx context.Context, id int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscertrequest/%d", c.HostURL, c.CompanyIDFor(ctx), id), nil)
 	if err != nil {
 		return err
 	}
@@ -244,45 +274,61 @@ func (c *Client) DeleteDNSCertReq(id int) error {
 	}
 
 	if sc != http.StatusNoContent {
-		return fmt.Errorf("%d - DELETE Failed for ID %d: %s", sc, id, c.parseAPIError(body))
+		return newAPIError(fmt.Sprintf("DELETE failed for ID %d", id), req, sc, body)
 	}
 
 	return nil
 }
 
-func (c *Client) UpdateDNSCertReq(certReqID int, credID int) error {
+func (c *Client) UpdateDNSCertReq(ctx context.Context, certReqID int, credID int) error {
 	data := map[string]any{
 		"credential": credID,
 	}
 
-	req, err := c.prepareJSONRequest(data, http.MethodPut, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscertrequest/%d", c.HostURL, c.CompanyID, certReqID))
+	req, err := c.prepareJSONRequest(ctx, data, http.MethodPut, fmt.Sprintf("%s/v1/autoprovisioning/%d/dnscertrequest/%d", c.HostURL, c.CompanyIDFor(ctx), certReqID))
 	if err != nil {
 		return err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return fmt.Errorf("%d - %s", sc, err.Error())
+		return err
 	}
 
-	if sc == http.StatusBadRequest {
-		apiError := c.parseAPIError(body)
+	if sc != http.StatusOK && sc != http.StatusCreated {
+		return newAPIError(fmt.Sprintf("failed to update the DNS Certificate Request with id %d", certReqID), req, sc, body)
+	}
 
-		err := json.Unmarshal(body, &apiError)
-		if err == nil {
-			return errors.New(apiError)
-		}
+	return nil
+}
+
+func (c *Client) GetCertReqsHTTP(ctx context.Context) ([]CertReqHTTP, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslcertificaterequest/", c.HostURL, c.CompanyIDFor(ctx)), nil)
+	if err != nil {
+		return nil, err
 	}
 
-	if sc != http.StatusOK && sc != http.StatusCreated {
-		return fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+	body, sc, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	if sc != http.StatusOK {
+		return nil, newAPIError("failed retrieving the list of HTTP Certificate Requests", req, sc, body)
+	}
+
+	data := []CertReqHTTP{}
+
+	err = json.Unmarshal(body, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
 }
 
-func (c *Client) GetCertReqHTTP(id int) (CertReqHTTP, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslcertificaterequest/%d", c.HostURL, c.CompanyID, id), nil)
+func (c *Client) GetCertReqHTTP(ctx context.Context, id int) (CertReqHTTP, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslcertificaterequest/%d", c.HostURL, c.CompanyIDFor(ctx), id), nil)
 	if err != nil {
 		return CertReqHTTP{}, err
 	}
@@ -293,7 +339,7 @@ func (c *Client) GetCertReqHTTP(id int) (CertReqHTTP, error) {
 	}
 
 	if sc != http.StatusOK {
-		return CertReqHTTP{}, fmt.Errorf("failure retrieving HTTP Certificate Request with id %d. %s", id, c.parseAPIError(body))
+		return CertReqHTTP{}, newAPIError(fmt.Sprintf("failure retrieving HTTP Certificate Request with id %d", id), req, sc, body)
 	}
 
 	data := CertReqHTTP{}
@@ -303,31 +349,27 @@ func (c *Client) GetCertReqHTTP(id int) (CertReqHTTP, error) {
 		return CertReqHTTP{}, err
 	}
 
+	// The certificate is issued asynchronously, the cached list does not have it yet
+	if data.CertificateID != nil {
+		c.invalidateCacheURL(ctx, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslconfig/", c.HostURL, c.CompanyIDFor(ctx)))
+	}
+
 	return data, nil
 }
 
-func (c *Client) CreateHTTPCertReq(certreq any) (*CertReqHTTP, error) {
-	req, err := c.prepareJSONRequest(certreq, http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslcertificaterequest/", c.HostURL, c.CompanyID))
+func (c *Client) CreateHTTPCertReq(ctx context.Context, certreq any) (*CertReqHTTP, error) {
+	req, err := c.prepareJSONRequest(ctx, certreq, http.MethodPost, fmt.Sprintf("%s/v1/autoprovisioning/%d/sslcertificaterequest/", c.HostURL, c.CompanyIDFor(ctx)))
 	if err != nil {
 		return nil, err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("%d - %s", sc, err.Error())
-	}
-
-	if sc == http.StatusBadRequest {
-		apiError := c.parseAPIError(body)
-
-		err := json.Unmarshal(body, &apiError)
-		if err == nil {
-			return nil, errors.New(apiError)
-		}
+		return nil, err
 	}
 
 	if sc != http.StatusOK && sc != http.StatusCreated {
-		return nil, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, newAPIError("failed to create the HTTP Certificate Request", req, sc, body)
 	}
 
 	newData := CertReqHTTP{}
@@ -2,49 +2,92 @@ package teclient
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 	"time"
 )
 
 const (
-	defaultAPIHTTPTimeout time.Duration = 50 * time.Second
+	// DefaultRequestTimeout bounds every exchange with the API, reading the whole response included.
+	DefaultRequestTimeout time.Duration = 50 * time.Second
+	// tokenRefreshMargin is how long before its announced expiry the access token is renewed,
+	// so requests in flight never carry a token that lapses on the way.
+	// Tokens living less than 4 margins are renewed after three quarters of their lifetime instead.
+	tokenRefreshMargin time.Duration = 2 * time.Minute
 )
 
-func NewClient(host *string,
+func NewClient(ctx context.Context,
+	host *string,
 	companyid *int,
 	clientid *string,
 	clientsecret *string,
 	insecure *bool,
 	auth *bool,
 	providerVersion *string,
+	opts ClientOptions,
 ) (*Client, error) {
+	tlsConfig, err := newTLSConfig(*insecure, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: *insecure}, // nolint: gosec
-		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+		Proxy:           proxyFunc(opts.ProxyURL, opts.NoProxy),
+	}
+
+	timeout := opts.RequestTimeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+
+	userAgent := "terraform-provider-transparentedge/" + *providerVersion
+	if opts.UserAgentSuffix != "" {
+		userAgent += " " + opts.UserAgentSuffix
 	}
 
 	token := TokenStruct{}
 
 	c := Client{
-		HTTPClient: &http.Client{Timeout: defaultAPIHTTPTimeout, Transport: tr},
-		Token:      token,
+		HTTPClient: &http.Client{
+			Timeout:   timeout,
+			Transport: &loggingTransport{next: tr, secrets: []string{*clientsecret}},
+		},
+		Token: token,
 
 		HostURL:         *host,
 		CompanyID:       *companyid,
 		ClientID:        *clientid,
 		ClientSecret:    *clientsecret,
 		VerifySSL:       *insecure,
-		UserAgent:       "terraform-provider-transparentedge/" + *providerVersion,
+		UserAgent:       userAgent,
 		ProviderVersion: *providerVersion,
+		auth:            *auth,
+		maxRetries:      max(opts.MaxRetries, 0),
+		maxBackoff:      opts.MaxBackoff,
+		limiter:         newLimiter(opts.MaxConcurrentRequests, opts.RequestsPerSecond),
+
+		accessToken:       opts.AccessToken,
+		credentialProcess: opts.CredentialProcess,
+		tokenCacheDir:     opts.TokenCacheDir,
+
+		readOnly: opts.ReadOnly,
+	}
+
+	if c.maxBackoff <= 0 {
+		c.maxBackoff = DefaultMaxBackoff
+	}
+
+	if opts.CacheLists {
+		c.cache = newListCache()
 	}
 
 	if *auth {
-		err := c.getToken()
+		err := c.getToken(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -55,69 +98,238 @@ func NewClient(host *string,
 	return &c, nil
 }
 
-func (c *Client) getToken() error {
+// requestToken requests a new access token using the client credentials grant,
+// unless the token cache holds a valid one.
+// The caller must hold tokenMu unless the client is not shared yet.
+func (c *Client) requestToken(ctx context.Context) error {
+	if c.loadCachedToken(ctx) {
+		return nil
+	}
+
 	reqBody := fmt.Appendf(nil, "client_id=%s&client_secret=%s&grant_type=client_credentials", c.ClientID, c.ClientSecret)
 
-	req, err := http.NewRequest(http.MethodPost, c.HostURL+"/v1/oauth2/access_token/", bytes.NewBuffer(reqBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.HostURL+"/v1/oauth2/access_token/", bytes.NewBuffer(reqBody))
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("User-Agent", c.UserAgent)
 
-	resp, err := c.HTTPClient.Do(req)
+	// Requesting a token has no side effects, it can always be retried
+	respBody, sc, err := c.sendRequest(markRetrySafe(req), "")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("received status code %d, could not create API client, please ensure credentials are correct", resp.StatusCode)
+	if sc == http.StatusUnauthorized {
+		return fmt.Errorf("received status code %d, could not create API client, please ensure credentials are correct", sc)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		respBody, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("%d - Failure creating API Token: %w", resp.StatusCode, err)
-		}
+	if sc != http.StatusOK {
+		return newAPIError("unable to create API Token", req, sc, respBody)
+	}
+
+	token := TokenStruct{}
+
+	errDecode := json.Unmarshal(respBody, &token)
+	if errDecode != nil {
+		return fmt.Errorf("fatal error creating API Token: %w", errDecode)
+	}
+
+	if token.Token == "" {
+		return errors.New("fatal error creating API Token: empty access token in the API response")
+	}
+
+	c.Token = token
+	c.setTokenExpiry(time.Time{})
+
+	if token.ExpiresIn > 0 {
+		c.setTokenExpiry(time.Now().Add(time.Duration(token.ExpiresIn) * time.Second))
+	}
+
+	c.storeCachedToken(ctx)
+
+	return nil
+}
 
-		return fmt.Errorf("received status code %d, unable to create API Token: %s", resp.StatusCode, c.parseAPIError(respBody))
+// setTokenExpiry records the expiry of the current access token, zero when unknown,
+// and when to renew it, so a short-lived token isn't renewed before every request.
+// The caller must hold tokenMu unless the client is not shared yet.
+func (c *Client) setTokenExpiry(expiry time.Time) {
+	c.tokenExpiry = expiry
+	c.tokenRefreshAt = time.Time{}
+
+	if !expiry.IsZero() {
+		c.tokenRefreshAt = expiry.Add(-min(tokenRefreshMargin, max(time.Until(expiry)/4, 0)))
 	}
+}
 
-	errDecode := json.NewDecoder(resp.Body).Decode(&c.Token)
-	if errDecode != nil || c.Token.Token == "" {
-		return fmt.Errorf("fatal error creating API Token: %s", errDecode.Error())
+// validToken returns the current access token, requesting a new one first
+// if it has expired or is about to expire.
+func (c *Client) validToken(ctx context.Context) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if !c.auth || c.tokenExpiry.IsZero() || time.Now().Before(c.tokenRefreshAt) {
+		return c.Token.Token, nil
 	}
 
-	return err
+	if err := c.getToken(ctx); err != nil {
+		return "", fmt.Errorf("unable to refresh the API Token: %w", err)
+	}
+
+	return c.Token.Token, nil
 }
 
+// renewToken requests a new access token after the API rejected staleToken.
+// If another request already renewed it in the meantime, the new token is reused.
+func (c *Client) renewToken(ctx context.Context, staleToken string) (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.Token.Token != staleToken {
+		return c.Token.Token, nil
+	}
+
+	if c.accessToken != "" {
+		return "", errors.New("the API rejected the access token, please provide a valid one")
+	}
+
+	c.forgetCachedToken(ctx, staleToken)
+
+	if err := c.getToken(ctx); err != nil {
+		return "", fmt.Errorf("unable to re-authenticate against the API: %w", err)
+	}
+
+	return c.Token.Token, nil
+}
+
+// doRequest sends an authenticated request and returns the response body and status code.
+// When the API answers 401 the client authenticates again and replays the request once.
+// Write requests drop the cached lists they may change, even if they fail, and are refused in read-only mode.
 func (c *Client) doRequest(req *http.Request) ([]byte, int, error) {
-	req.Header.Set("Authorization", "Bearer "+c.Token.Token)
+	if c.readOnly && !isReadRequest(req) {
+		return nil, 0, fmt.Errorf("%w: refusing to send %s %s", ErrReadOnly, req.Method, req.URL.Redacted())
+	}
+
+	if !isReadRequest(req) {
+		defer c.invalidateCache(req.Context(), req.URL.Path)
+	}
+
+	token, err := c.validToken(req.Context())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	body, sc, err := c.sendRequest(req, token)
+	if err != nil || sc != http.StatusUnauthorized || !c.auth {
+		return body, sc, err
+	}
+
+	token, err = c.renewToken(req.Context(), token)
+	if err != nil {
+		return nil, sc, err
+	}
+
+	replay, err := rewindRequest(req)
+	if err != nil {
+		return nil, sc, err
+	}
+
+	return c.sendRequest(replay, token)
+}
+
+// sendRequest sends the request with the given bearer token (if any), retrying transient
+// failures with backoff as long as the retry policy allows it.
+func (c *Client) sendRequest(req *http.Request, token string) ([]byte, int, error) {
+	for attempt := 0; ; attempt++ {
+		body, resp, err := c.roundTrip(req, token)
+
+		statusCode := 0
+		header := http.Header{}
+
+		if resp != nil {
+			statusCode = resp.StatusCode
+			header = resp.Header
+		}
+
+		if attempt >= c.maxRetries || !shouldRetry(req, statusCode, err) {
+			return body, statusCode, err
+		}
+
+		delay := c.retryDelay(attempt, header)
+
+		select {
+		case <-req.Context().Done():
+			return body, statusCode, err
+		case <-time.After(delay):
+		}
+
+		req, err = rewindRequest(req)
+		if err != nil {
+			return body, statusCode, err
+		}
+	}
+}
+
+// roundTrip performs a single HTTP exchange and reads the whole response body.
+// Every exchange goes through the client limiter, retries and token requests included.
+func (c *Client) roundTrip(req *http.Request, token string) ([]byte, *http.Response, error) {
+	release, err := c.limiter.acquire(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
 	req.Header.Set("User-Agent", c.UserAgent)
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, resp.StatusCode, err
+		return nil, resp, err
 	}
 
-	return body, resp.StatusCode, err
+	return body, resp, nil
 }
 
-func (*Client) prepareJSONRequest(jdata any, method string, url string) (*http.Request, error) {
+// rewindRequest returns a copy of req with a fresh body so it can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	replay := req.Clone(req.Context())
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return replay, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, errors.New("unable to replay the request: its body cannot be rewound")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+
+	replay.Body = body
+
+	return replay, nil
+}
+
+func (*Client) prepareJSONRequest(ctx context.Context, jdata any, method string, url string) (*http.Request, error) {
 	data, err := json.Marshal(jdata)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
@@ -138,26 +350,3 @@ func (*Client) MustGetAPIEnvironmentPath(environment APIEnvironment) string {
 	// Invalid env
 	panic(fmt.Sprintf("Invalid environment: %+v", environment))
 }
-
-func (*Client) parseAPIError(body []byte) string {
-	apiError := APIMessage{}
-	apiDetail := APIDetail{}
-
-	decoder := json.NewDecoder(strings.NewReader(string(body)))
-	decoder.DisallowUnknownFields()
-
-	err := decoder.Decode(&apiError)
-	if err == nil {
-		return apiError.Message
-	}
-
-	decoder = json.NewDecoder(strings.NewReader(string(body)))
-	decoder.DisallowUnknownFields()
-
-	err = decoder.Decode(&apiDetail)
-	if err == nil {
-		return apiDetail.Detail
-	}
-
-	return string(body)
-}
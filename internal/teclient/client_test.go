@@ -0,0 +1,282 @@
+package teclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+const tokenPath = "/v1/oauth2/access_token"
+
+func newTestClient(t *testing.T, srv *testserver.Server, opts teclient.ClientOptions) *teclient.Client {
+	t.Helper()
+
+	host := srv.URL
+	companyID := testserver.DefaultCompanyID
+	clientID := testserver.ClientID
+	clientSecret := testserver.ClientSecret
+	insecure := false
+	auth := true
+	version := "test"
+
+	client, err := teclient.NewClient(t.Context(), &host, &companyID, &clientID, &clientSecret, &insecure, &auth, &version, opts)
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+
+	return client
+}
+
+func testBackend(name string) teclient.NewBackendAPIModel {
+	return teclient.NewBackendAPIModel{
+		Name:         name,
+		Origin:       "origin.example.com",
+		Port:         80,
+		HCHost:       "www.example.com",
+		HCPath:       "/",
+		HCStatusCode: 200,
+		HCInterval:   40,
+	}
+}
+
+func TestNewClient_invalidCredentials(t *testing.T) {
+	srv := testserver.New(t)
+
+	host := srv.URL
+	companyID := testserver.DefaultCompanyID
+	clientID := testserver.ClientID
+	clientSecret := "wrong"
+	insecure := false
+	auth := true
+	version := "test"
+
+	_, err := teclient.NewClient(t.Context(), &host, &companyID, &clientID, &clientSecret, &insecure, &auth, &version, teclient.ClientOptions{})
+
+	if err == nil || !strings.Contains(err.Error(), "please ensure credentials are correct") {
+		t.Errorf("expected a credentials error, got %v", err)
+	}
+}
+
+func TestClient_retriesTransientErrors(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{MaxRetries: 3, MaxBackoff: time.Millisecond})
+
+	srv.InjectFault(testserver.Fault{Method: http.MethodGet, Path: "/backends", StatusCode: http.StatusServiceUnavailable, Times: 2})
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Fatalf("GetBackends: %s", err)
+	}
+
+	if n := srv.CountRequests(http.MethodGet, "/backends"); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestClient_givesUpAfterMaxRetries(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{MaxRetries: 2, MaxBackoff: time.Millisecond})
+
+	srv.InjectFault(testserver.Fault{Method: http.MethodGet, Path: "/backends", StatusCode: http.StatusBadGateway})
+
+	_, err := client.GetBackends(t.Context(), teclient.ProdEnv)
+
+	var apiErr *teclient.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a 502 APIError, got %v", err)
+	}
+
+	if n := srv.CountRequests(http.MethodGet, "/backends"); n != 3 {
+		t.Errorf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestClient_doesNotRetryUnsafePOST(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{MaxRetries: 3, MaxBackoff: time.Millisecond})
+
+	srv.InjectFault(testserver.Fault{Method: http.MethodPost, Path: "/backends", StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+	if _, err := client.CreateBackend(t.Context(), testBackend("origin1"), teclient.ProdEnv); err == nil {
+		t.Fatal("expected the first attempt error")
+	}
+
+	if n := srv.CountRequests(http.MethodPost, "/backends"); n != 1 {
+		t.Errorf("expected 1 attempt, got %d", n)
+	}
+}
+
+func TestClient_honoursRetryAfter(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{MaxRetries: 1, MaxBackoff: 5 * time.Second})
+
+	srv.InjectFault(testserver.Fault{
+		Method:     http.MethodGet,
+		Path:       "/backends",
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"1"}},
+		Times:      1,
+	})
+
+	start := time.Now()
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Fatalf("GetBackends: %s", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait at least 1s, waited %s", elapsed)
+	}
+}
+
+func TestClient_reauthenticatesOn401(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{})
+
+	srv.ExpireTokens()
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Fatalf("GetBackends: %s", err)
+	}
+
+	if n := srv.CountRequests(http.MethodPost, tokenPath); n != 2 {
+		t.Errorf("expected 2 token requests, got %d", n)
+	}
+
+	if n := srv.CountRequests(http.MethodGet, "/backends"); n != 2 {
+		t.Errorf("expected the request to be sent again once, got %d attempts", n)
+	}
+}
+
+func TestClient_refreshesTokenBeforeExpiry(t *testing.T) {
+	srv := testserver.New(t)
+	// Shorter than the refresh margin, so the token is renewed after three quarters of its lifetime
+	srv.SetTokenTTL(time.Second)
+
+	client := newTestClient(t, srv, teclient.ClientOptions{})
+
+	for range 2 {
+		if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+			t.Fatalf("GetBackends: %s", err)
+		}
+	}
+
+	if n := srv.CountRequests(http.MethodPost, tokenPath); n != 1 {
+		t.Errorf("expected a short-lived token to be reused while it's fresh, got %d token requests", n)
+	}
+
+	time.Sleep(800 * time.Millisecond)
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Fatalf("GetBackends: %s", err)
+	}
+
+	if n := srv.CountRequests(http.MethodPost, tokenPath); n != 2 {
+		t.Errorf("expected the token to be renewed before it expires, got %d token requests", n)
+	}
+
+	if n := srv.CountRequests(http.MethodGet, "/backends"); n != 3 {
+		t.Errorf("expected no request to be rejected, got %d attempts", n)
+	}
+}
+
+func TestClient_notFound(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{})
+
+	_, err := client.GetBackend(t.Context(), 1, teclient.ProdEnv)
+	if !teclient.IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+
+	_, err = client.GetBackendByName(t.Context(), "missing", teclient.ProdEnv)
+	if !teclient.IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestClient_fieldErrors(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{})
+
+	if _, err := client.CreateBackend(t.Context(), testBackend("origin1"), teclient.ProdEnv); err != nil {
+		t.Fatalf("CreateBackend: %s", err)
+	}
+
+	_, err := client.CreateBackend(t.Context(), testBackend("origin1"), teclient.ProdEnv)
+
+	if !teclient.IsConflict(err) {
+		t.Errorf("expected a conflict, got %v", err)
+	}
+
+	apiErr, ok := teclient.AsAPIError(err)
+	if !ok {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+
+	if msgs := apiErr.FieldErrors["name"]; len(msgs) != 1 || msgs[0] != "backend with this name already exists." {
+		t.Errorf("unexpected field errors: %v", apiErr.FieldErrors)
+	}
+}
+
+func TestClient_contextCancellation(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{})
+
+	srv.SetLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetBackends(ctx, teclient.ProdEnv)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context deadline error, got %v", err)
+	}
+}
+
+func TestClient_limitsConcurrentRequests(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{MaxConcurrentRequests: 2})
+
+	srv.SetLatency(20 * time.Millisecond)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Go(func() {
+			if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+				t.Errorf("GetBackends: %s", err)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if n := srv.MaxInFlight(); n > 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", n)
+	}
+}
+
+func TestClient_limitsRequestRate(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{RequestsPerSecond: 20})
+
+	start := time.Now()
+
+	for range 5 {
+		if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+			t.Fatalf("GetBackends: %s", err)
+		}
+	}
+
+	// The token request took the first slot, the next 5 are spaced by 50ms
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected the requests to take at least 200ms, took %s", elapsed)
+	}
+}
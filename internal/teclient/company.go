@@ -0,0 +1,27 @@
+package teclient
+
+import "context"
+
+// companyIDKey is the context key of the company targeted by the API calls.
+type companyIDKey struct{}
+
+// WithCompanyID returns a copy of ctx whose API calls target companyID instead of the
+// default company of the client, i.e: a child company of a reseller.
+// A companyID lower than 1 keeps the default company.
+func WithCompanyID(ctx context.Context, companyID int) context.Context {
+	if companyID < 1 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, companyIDKey{}, companyID)
+}
+
+// CompanyIDFor returns the company targeted by the API calls made with ctx:
+// the one set by WithCompanyID, or the default company of the client.
+func (c *Client) CompanyIDFor(ctx context.Context) int {
+	if companyID, ok := ctx.Value(companyIDKey{}).(int); ok {
+		return companyID
+	}
+
+	return c.CompanyID
+}
@@ -0,0 +1,168 @@
+package teclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// credentialProcessTimeout bounds the run of the credential process.
+const credentialProcessTimeout = time.Minute
+
+// ProcessCredentials is the JSON document printed by the credential process on its standard output.
+// It holds either a client ID and secret, exchanged for an access token as usual, or an access token
+// and its optional expiry.
+type ProcessCredentials struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	AccessToken  string `json:"access_token"`
+	// ExpiresAt is the expiry of AccessToken in RFC 3339 format, the token is used until the API rejects it if empty.
+	ExpiresAt string `json:"expires_at"`
+}
+
+// getToken obtains a new access token from the configured source: the static access token,
+// the credential process, or the client credentials grant.
+// The caller must hold tokenMu unless the client is not shared yet.
+func (c *Client) getToken(ctx context.Context) error {
+	switch {
+	case c.accessToken != "":
+		c.Token = TokenStruct{Token: c.accessToken, TokenType: "Bearer"}
+		c.setTokenExpiry(time.Time{})
+
+		return nil
+	case c.credentialProcess != "":
+		return c.getProcessToken(ctx)
+	}
+
+	return c.requestToken(ctx)
+}
+
+// getProcessToken runs the credential process and uses the token it prints,
+// or requests one with the client credentials it prints.
+func (c *Client) getProcessToken(ctx context.Context) error {
+	creds, err := runCredentialProcess(ctx, c.credentialProcess)
+	if err != nil {
+		return err
+	}
+
+	if creds.AccessToken == "" {
+		c.ClientID = creds.ClientID
+		c.ClientSecret = creds.ClientSecret
+
+		return c.requestToken(ctx)
+	}
+
+	c.Token = TokenStruct{Token: creds.AccessToken, TokenType: "Bearer"}
+	c.setTokenExpiry(time.Time{})
+
+	if creds.ExpiresAt != "" {
+		// Already validated by runCredentialProcess
+		expiry, _ := time.Parse(time.RFC3339, creds.ExpiresAt)
+		c.setTokenExpiry(expiry)
+	}
+
+	return nil
+}
+
+// runCredentialProcess runs command and decodes the credentials it prints.
+func runCredentialProcess(ctx context.Context, command string) (*ProcessCredentials, error) {
+	args, err := splitCommand(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential process: %w", err)
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("invalid credential process: empty command")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, credentialProcessTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) // nolint: gosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("credential process failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	creds := ProcessCredentials{}
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		// The output is not part of the error, it holds secrets
+		return nil, fmt.Errorf("credential process printed invalid JSON: %w", err)
+	}
+
+	switch {
+	case creds.AccessToken != "":
+		if creds.ExpiresAt != "" {
+			if _, err := time.Parse(time.RFC3339, creds.ExpiresAt); err != nil {
+				return nil, fmt.Errorf("credential process printed an invalid expires_at: %w", err)
+			}
+		}
+	case creds.ClientID == "" || creds.ClientSecret == "":
+		return nil, errors.New("credential process printed neither an access_token nor a client_id and client_secret")
+	}
+
+	return &creds, nil
+}
+
+// splitCommand splits a command line in words, honouring single and double quotes and backslash escapes.
+func splitCommand(command string) ([]string, error) {
+	var (
+		args    []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+
+			inWord = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+
+	if inWord {
+		args = append(args, word.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,170 @@
+package teclient_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+// newClientWithoutSecret creates a client for srv that has no client credentials, only opts.
+func newClientWithoutSecret(t *testing.T, srv *testserver.Server, opts teclient.ClientOptions) (*teclient.Client, error) {
+	t.Helper()
+
+	host := srv.URL
+	companyID := testserver.DefaultCompanyID
+	clientID := ""
+	clientSecret := ""
+	insecure := false
+	auth := true
+	version := "test"
+
+	return teclient.NewClient(t.Context(), &host, &companyID, &clientID, &clientSecret, &insecure, &auth, &version, opts)
+}
+
+// credentialProcess writes a shell script printing output and returns the command running it.
+// Every run of the script is appended to the returned log file.
+func credentialProcess(t *testing.T, output string) (string, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is required to run the credential process")
+	}
+
+	dir := filepath.Join(t.TempDir(), "credential process")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	script := filepath.Join(dir, "creds.sh")
+	runs := filepath.Join(dir, "runs.log")
+	content := fmt.Sprintf("echo run >> '%s'\n%s\n", runs, output)
+
+	if err := os.WriteFile(script, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return `sh "` + script + `"`, runs
+}
+
+// countRuns returns the number of times the script of credentialProcess ran.
+func countRuns(t *testing.T, runs string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Count(string(data), "run\n")
+}
+
+func TestClient_accessToken(t *testing.T) {
+	srv := testserver.New(t)
+
+	client, err := newClientWithoutSecret(t, srv, teclient.ClientOptions{AccessToken: srv.IssueToken(time.Hour)})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Fatalf("GetBackends: %s", err)
+	}
+
+	if n := srv.CountRequests(http.MethodPost, tokenPath); n != 0 {
+		t.Errorf("expected no token request, got %d", n)
+	}
+
+	// A static token cannot be renewed
+	srv.ExpireTokens()
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err == nil || !strings.Contains(err.Error(), "rejected the access token") {
+		t.Errorf("expected a rejected token error, got %v", err)
+	}
+}
+
+func TestClient_credentialProcessClientCredentials(t *testing.T) {
+	srv := testserver.New(t)
+	command, runs := credentialProcess(t, fmt.Sprintf(`echo '{"client_id": "%s", "client_secret": "%s"}'`, testserver.ClientID, testserver.ClientSecret))
+
+	client, err := newClientWithoutSecret(t, srv, teclient.ClientOptions{CredentialProcess: command})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+
+	// The process runs again to renew the token rejected by the API
+	srv.ExpireTokens()
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Fatalf("GetBackends: %s", err)
+	}
+
+	if n := countRuns(t, runs); n != 2 {
+		t.Errorf("expected 2 runs of the credential process, got %d", n)
+	}
+
+	if n := srv.CountRequests(http.MethodPost, tokenPath); n != 2 {
+		t.Errorf("expected 2 token requests, got %d", n)
+	}
+}
+
+func TestClient_credentialProcessToken(t *testing.T) {
+	srv := testserver.New(t)
+
+	// The process prints a token already expired, so it is renewed before every request
+	token := srv.IssueToken(time.Hour)
+	expiresAt := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	command, runs := credentialProcess(t, fmt.Sprintf(`echo '{"access_token": "%s", "expires_at": "%s"}'`, token, expiresAt))
+
+	client, err := newClientWithoutSecret(t, srv, teclient.ClientOptions{CredentialProcess: command})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+
+	for range 2 {
+		if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+			t.Fatalf("GetBackends: %s", err)
+		}
+	}
+
+	if n := countRuns(t, runs); n != 3 {
+		t.Errorf("expected 3 runs of the credential process, got %d", n)
+	}
+
+	if n := srv.CountRequests(http.MethodPost, tokenPath); n != 0 {
+		t.Errorf("expected no token request, got %d", n)
+	}
+}
+
+func TestClient_credentialProcessErrors(t *testing.T) {
+	srv := testserver.New(t)
+
+	for output, want := range map[string]string{
+		`echo "vault is sealed" >&2; exit 1`:                         "credential process failed: exit status 1: vault is sealed",
+		`echo 'client_secret=hunter2'`:                               "credential process printed invalid JSON",
+		`echo '{"client_id": "id"}'`:                                 "neither an access_token nor a client_id and client_secret",
+		`echo '{"access_token": "token", "expires_at": "tomorrow"}'`: "invalid expires_at",
+	} {
+		command, _ := credentialProcess(t, output)
+
+		_, err := newClientWithoutSecret(t, srv, teclient.ClientOptions{CredentialProcess: command})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected %q, got %v", output, want, err)
+		}
+
+		if err != nil && strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("the error leaks the output of the credential process: %s", err)
+		}
+	}
+
+	if _, err := newClientWithoutSecret(t, srv, teclient.ClientOptions{CredentialProcess: `sh "unterminated`}); err == nil || !strings.Contains(err.Error(), "unterminated quote") {
+		t.Errorf("expected an invalid command error, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package teclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NonFieldErrorsKey is the key used by the API for validation errors not tied to a single field.
+const NonFieldErrorsKey = "non_field_errors"
+
+// ErrNotFound matches, through errors.Is, any error reporting an object that does not exist in the API.
+var ErrNotFound = errors.New("not found")
+
+// APIError is returned when the API answers a request with an unexpected status code.
+type APIError struct {
+	// Op describes what the client was trying to do, i.e: "failed to retrieve the backend with ID 1".
+	Op         string
+	StatusCode int
+	Method     string
+	URL        string
+	// Message and Detail hold the "message" and "detail" fields of the response, when present.
+	Message string
+	Detail  string
+	// FieldErrors holds the validation errors of a rejected request keyed by API field name.
+	// Nested fields are joined with dots, i.e: "creds.0.hook_key_value".
+	FieldErrors map[string][]string
+	// Body is the raw response body.
+	Body string
+}
+
+// newAPIError builds an APIError from the response to req.
+func newAPIError(op string, req *http.Request, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		Op:          op,
+		StatusCode:  statusCode,
+		Body:        strings.TrimSpace(string(body)),
+		FieldErrors: map[string][]string{},
+	}
+
+	if req != nil {
+		apiErr.Method = req.Method
+		apiErr.URL = req.URL.Redacted()
+	}
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return apiErr
+	}
+
+	switch value := decoded.(type) {
+	case string:
+		apiErr.Message = value
+	case []any:
+		collectFieldErrors(apiErr.FieldErrors, NonFieldErrorsKey, value)
+	case map[string]any:
+		for key, field := range value {
+			text, isString := field.(string)
+
+			switch {
+			case key == "message" && isString:
+				apiErr.Message = text
+			case key == "detail" && isString:
+				apiErr.Detail = text
+			default:
+				collectFieldErrors(apiErr.FieldErrors, key, field)
+			}
+		}
+	}
+
+	return apiErr
+}
+
+// collectFieldErrors flattens the (possibly nested) validation errors of a field into errs.
+func collectFieldErrors(errs map[string][]string, field string, value any) {
+	switch v := value.(type) {
+	case string:
+		errs[field] = append(errs[field], v)
+	case []any:
+		for i, item := range v {
+			if _, nested := item.(map[string]any); nested {
+				collectFieldErrors(errs, field+"."+strconv.Itoa(i), item)
+			} else {
+				collectFieldErrors(errs, field, item)
+			}
+		}
+	case map[string]any:
+		for key, item := range v {
+			collectFieldErrors(errs, field+"."+key, item)
+		}
+	case nil:
+	default:
+		errs[field] = append(errs[field], fmt.Sprint(v))
+	}
+}
+
+// Summary returns the most descriptive message found in the API response.
+func (e *APIError) Summary() string {
+	if e.Message != "" {
+		return e.Message
+	}
+
+	if e.Detail != "" {
+		return e.Detail
+	}
+
+	if len(e.FieldErrors) > 0 {
+		msgs := make([]string, 0, len(e.FieldErrors))
+
+		for _, field := range slices.Sorted(maps.Keys(e.FieldErrors)) {
+			text := strings.Join(e.FieldErrors[field], " ")
+			if field != NonFieldErrorsKey {
+				text = field + ": " + text
+			}
+
+			msgs = append(msgs, text)
+		}
+
+		return strings.Join(msgs, "; ")
+	}
+
+	if e.Body != "" {
+		return e.Body
+	}
+
+	return http.StatusText(e.StatusCode)
+}
+
+func (e *APIError) Error() string {
+	msg := e.Summary()
+	if e.Op != "" {
+		msg = e.Op + ": " + msg
+	}
+
+	if e.Method != "" {
+		return fmt.Sprintf("%s (%s %s returned %d)", msg, e.Method, e.URL, e.StatusCode)
+	}
+
+	return fmt.Sprintf("%s (status code %d)", msg, e.StatusCode)
+}
+
+// Is reports a 404 response as ErrNotFound.
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// Mentions reports whether any message of the response contains text.
+// The API does not return error codes, a few cases can only be told apart by their message.
+func (e *APIError) Mentions(text string) bool {
+	if strings.Contains(e.Message, text) || strings.Contains(e.Detail, text) {
+		return true
+	}
+
+	for _, msgs := range e.FieldErrors {
+		for _, msg := range msgs {
+			if strings.Contains(msg, text) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// AsAPIError returns the APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	return nil, false
+}
+
+// IsNotFound reports whether err means that the requested object does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err means that the object collides with an existing one,
+// either with a 409 or with a unique constraint validation error ("... already exists").
+func IsConflict(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+
+	return apiErr.StatusCode == http.StatusConflict ||
+		(apiErr.StatusCode == http.StatusBadRequest && apiErr.Mentions("already exists"))
+}
+
+// IsForbidden reports whether the API refused to perform the operation.
+func IsForbidden(err error) bool {
+	apiErr, ok := AsAPIError(err)
+
+	return ok && apiErr.StatusCode == http.StatusForbidden
+}
@@ -1,33 +1,26 @@
 package teclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 )
 
-func (c *Client) GetIPRanges() ([]string, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/companies/ipranges", c.HostURL), nil) // nolint: perfsprint
+func (c *Client) GetIPRanges(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v2/companies/ipranges", c.HostURL), nil) // nolint: perfsprint
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("User-Agent", c.UserAgent)
-
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	// Public endpoint, no token is sent
+	body, sc, err := c.sendRequest(req, "")
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to retrieve IP Ranges: %s", c.parseAPIError(body))
+	if sc != http.StatusOK {
+		return nil, newAPIError("failed to retrieve IP Ranges", req, sc, body)
 	}
 
 	ranges := []string{}
@@ -0,0 +1,110 @@
+package teclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// DefaultMaxConcurrentRequests is the number of API requests allowed in flight at the same time, 0 means unlimited,
+	// so only the parallelism of Terraform applies unless the user opts in.
+	DefaultMaxConcurrentRequests int = 0
+	// DefaultRequestsPerSecond is the rate at which new API requests are sent, 0 means unlimited.
+	DefaultRequestsPerSecond int = 0
+)
+
+// limiter is shared by every resource and data source using the same client,
+// it caps both the requests in flight and the rate at which new ones are started.
+type limiter struct {
+	// slots holds a token per request in flight, nil when the concurrency is unlimited.
+	slots chan struct{}
+	// interval is the minimum time between the start of two requests, 0 when the rate is unlimited.
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newLimiter(maxConcurrent int, requestsPerSecond int) *limiter {
+	l := &limiter{}
+
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+
+	if requestsPerSecond > 0 {
+		l.interval = time.Second / time.Duration(requestsPerSecond)
+	}
+
+	return l
+}
+
+// acquire waits until req is allowed to be sent and returns the function releasing its slot.
+// The time spent in the queue is logged, it is the first thing to look at when an apply is slow.
+func (l *limiter) acquire(req *http.Request) (func(), error) {
+	ctx := req.Context()
+	start := time.Now()
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if err := l.wait(ctx); err != nil {
+		release()
+
+		return nil, err
+	}
+
+	if queued := time.Since(start); queued >= time.Millisecond {
+		tflog.Debug(ctx, "API request waited in the client queue", map[string]any{
+			"method":     req.Method,
+			"url":        req.URL.String(),
+			"queue_wait": queued.String(),
+		})
+	}
+
+	return release, nil
+}
+
+// wait reserves the next start time allowed by the rate limit and sleeps until then.
+func (l *limiter) wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+
+	at := now
+	if l.next.After(now) {
+		at = l.next
+	}
+
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	if at.Equal(now) {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(at.Sub(now)):
+		return nil
+	}
+}
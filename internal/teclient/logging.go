@@ -0,0 +1,187 @@
+package teclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// maxLoggedBodySize is the number of bytes of each body written to the logs.
+	maxLoggedBodySize = 2048
+	redactedValue     = "***"
+)
+
+// sensitiveKeys are the JSON and form fields whose values never reach the logs.
+var sensitiveKeys = map[string]bool{
+	"client_secret":  true,
+	"access_token":   true,
+	"refresh_token":  true,
+	"key":            true, // private key of the certificates
+	"dns_key_value":  true,
+	"hook_key_value": true,
+}
+
+// privateKeyRe catches PEM private keys the body redaction missed, i.e: in a truncated JSON body.
+var privateKeyRe = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?(-----END [A-Z ]*PRIVATE KEY-----|$)`)
+
+// loggingTransport writes every API call to the provider logs: a debug entry with the method,
+// URL, status and latency, and a trace entry adding the request and response bodies.
+// Secrets are removed from the bodies and masked by tflog in every field.
+type loggingTransport struct {
+	next http.RoundTripper
+	// secrets are masked in the logs wherever they show up, i.e: the client secret.
+	secrets []string
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := t.maskSecrets(req)
+
+	fields := map[string]any{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	}
+
+	reqBody := loggableRequestBody(req)
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "API call failed", fields)
+
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+
+	if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
+		fields["request_id"] = requestID
+	}
+
+	tflog.Debug(ctx, "API call", fields)
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// Hand the body over to the caller untouched, even if it could only be read partially
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields["request_body"] = reqBody
+	fields["response_body"] = redactBody(respBody, resp.Header.Get("Content-Type"))
+	tflog.Trace(ctx, "API call bodies", fields)
+
+	return resp, nil
+}
+
+// maskSecrets returns the request context with tflog masking the client secret and the bearer token.
+func (t *loggingTransport) maskSecrets(req *http.Request) context.Context {
+	secrets := make([]string, 0, len(t.secrets)+1)
+
+	for _, secret := range t.secrets {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+
+	if token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); found && token != "" {
+		secrets = append(secrets, token)
+	}
+
+	ctx := tflog.MaskAllFieldValuesStrings(req.Context(), secrets...)
+
+	return tflog.MaskAllFieldValuesRegexes(ctx, privateKeyRe)
+}
+
+// loggableRequestBody returns a redacted copy of the request body, leaving the request untouched.
+func loggableRequestBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+
+	return redactBody(data, req.Header.Get("Content-Type"))
+}
+
+// redactBody removes the values of the sensitive fields from a JSON or form body and truncates it.
+func redactBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	redacted := string(body)
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(redacted); err == nil {
+			for key := range values {
+				if sensitiveKeys[strings.ToLower(key)] {
+					values.Set(key, redactedValue)
+				}
+			}
+
+			redacted = values.Encode()
+		}
+	default:
+		var data any
+		if err := json.Unmarshal(body, &data); err == nil {
+			if encoded, err := json.Marshal(redactJSON(data)); err == nil {
+				redacted = string(encoded)
+			}
+		}
+	}
+
+	if len(redacted) > maxLoggedBodySize {
+		redacted = fmt.Sprintf("%s... (%d bytes truncated)", redacted[:maxLoggedBodySize], len(redacted)-maxLoggedBodySize)
+	}
+
+	return redacted
+}
+
+// redactJSON replaces the values of the sensitive keys found at any depth of data.
+func redactJSON(data any) any {
+	switch value := data.(type) {
+	case map[string]any:
+		for key, nested := range value {
+			if sensitiveKeys[strings.ToLower(key)] {
+				value[key] = redactedValue
+			} else {
+				value[key] = redactJSON(nested)
+			}
+		}
+	case []any:
+		for i, nested := range value {
+			value[i] = redactJSON(nested)
+		}
+	}
+
+	return data
+}
@@ -1,6 +1,10 @@
 package teclient
 
-import "net/http"
+import (
+	"net/http"
+	"sync"
+	"time"
+)
 
 // APIEnvironment.
 type APIEnvironment int
@@ -10,15 +14,6 @@ const (
 	StagingEnv APIEnvironment = 1
 )
 
-// APIMessage.
-type APIMessage struct {
-	Message string `json:"message"`
-}
-
-type APIDetail struct {
-	Detail string `json:"detail"`
-}
-
 type TokenStruct struct {
 	Token     string `json:"access_token"`
 	ExpiresIn int    `json:"expires_in"`
@@ -30,6 +25,24 @@ type Client struct {
 	HTTPClient *http.Client
 	Token      TokenStruct
 
+	// tokenMu guards Token, tokenExpiry and tokenRefreshAt, shared by concurrent resource operations.
+	tokenMu        sync.Mutex
+	tokenExpiry    time.Time
+	tokenRefreshAt time.Time
+	auth           bool
+	// accessToken and credentialProcess replace the client credentials as the source of the tokens.
+	accessToken       string
+	credentialProcess string
+	// tokenCacheDir keeps the access tokens across runs when not empty.
+	tokenCacheDir string
+
+	maxRetries int
+	maxBackoff time.Duration
+	limiter    *limiter
+	cache      *listCache
+	// readOnly rejects the requests that may change the CDN configuration.
+	readOnly bool
+
 	HostURL         string
 	CompanyID       int
 	ClientID        string
@@ -39,6 +52,46 @@ type Client struct {
 	ProviderVersion string
 }
 
+// ClientOptions holds the optional tuning settings of the API client.
+type ClientOptions struct {
+	// MaxRetries is the number of times a request failing with a transient error is retried, 0 disables retries.
+	MaxRetries int
+	// MaxBackoff caps the wait between two attempts, including the one requested by Retry-After.
+	MaxBackoff time.Duration
+	// MaxConcurrentRequests caps the requests in flight at the same time, 0 means unlimited.
+	MaxConcurrentRequests int
+	// RequestsPerSecond caps the rate at which requests are sent, 0 means unlimited.
+	RequestsPerSecond int
+	// CacheLists keeps the lists of sites, backends, certificates and VCL configurations
+	// in memory until a write request changes them.
+	CacheLists bool
+	// CABundle holds PEM encoded certificates trusted for the API on top of the system ones.
+	CABundle []byte
+	// ClientCertificate and ClientKey are the PEM encoded pair presented when the API asks for a client certificate.
+	ClientCertificate []byte
+	ClientKey         []byte
+	// MinTLSVersion is the lowest TLS version accepted, i.e: tls.VersionTLS13. 0 means DefaultMinTLSVersion.
+	MinTLSVersion uint16
+	// AccessToken is sent as is instead of requesting tokens with the client credentials.
+	AccessToken string
+	// CredentialProcess is a command line printing the credentials as JSON, see ProcessCredentials.
+	// It runs again whenever a new token is needed.
+	CredentialProcess string
+	// TokenCacheDir is the directory where the access tokens obtained with client credentials are kept,
+	// so the next runs reuse them until they are about to expire. Empty disables the cache.
+	TokenCacheDir string
+	// RequestTimeout bounds every exchange with the API, 0 means DefaultRequestTimeout.
+	RequestTimeout time.Duration
+	// ProxyURL is the proxy of the API requests instead of the one in HTTPS_PROXY and HTTP_PROXY.
+	ProxyURL string
+	// NoProxy is a comma-separated list of hosts reached without proxy instead of the one in NO_PROXY.
+	NoProxy string
+	// UserAgentSuffix is appended to the User-Agent header, i.e: the name of the pipeline running Terraform.
+	UserAgentSuffix string
+	// ReadOnly makes the client refuse POST, PUT and DELETE requests, except the few POSTs that only read data.
+	ReadOnly bool
+}
+
 // SiteAPIModel.
 type SiteAPIModel struct {
 	ID      int    `json:"id"`
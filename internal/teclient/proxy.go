@@ -0,0 +1,33 @@
+package teclient
+
+import (
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyFunc returns the proxy selection of the API requests: the one of the environment
+// (HTTPS_PROXY, HTTP_PROXY and NO_PROXY) with proxyURL and noProxy replacing its values when set.
+func proxyFunc(proxyURL string, noProxy string) func(*http.Request) (*url.URL, error) {
+	if proxyURL == "" && noProxy == "" {
+		return http.ProxyFromEnvironment
+	}
+
+	cfg := httpproxy.FromEnvironment()
+
+	if proxyURL != "" {
+		cfg.HTTPProxy = proxyURL
+		cfg.HTTPSProxy = proxyURL
+	}
+
+	if noProxy != "" {
+		cfg.NoProxy = noProxy
+	}
+
+	proxy := cfg.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
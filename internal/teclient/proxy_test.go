@@ -0,0 +1,89 @@
+package teclient_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+// proxiedAPIURL is an API URL only reachable through the proxy, the test server acting as both.
+const proxiedAPIURL = "http://api.transparentedge.test"
+
+// newProxiedClient creates a client for proxiedAPIURL with opts.
+func newProxiedClient(t *testing.T, opts teclient.ClientOptions) (*teclient.Client, error) {
+	t.Helper()
+
+	host := proxiedAPIURL
+	companyID := testserver.DefaultCompanyID
+	clientID := testserver.ClientID
+	clientSecret := testserver.ClientSecret
+	insecure := false
+	auth := true
+	version := "test"
+
+	return teclient.NewClient(t.Context(), &host, &companyID, &clientID, &clientSecret, &insecure, &auth, &version, opts)
+}
+
+func TestClient_proxyURL(t *testing.T) {
+	srv := testserver.New(t)
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("NO_PROXY", "")
+
+	client, err := newProxiedClient(t, teclient.ClientOptions{ProxyURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Fatalf("GetBackends: %s", err)
+	}
+
+	// The token and the backends
+	if n := srv.ProxiedRequests(); n != 2 {
+		t.Errorf("expected 2 requests through the proxy, got %d", n)
+	}
+}
+
+func TestClient_noProxy(t *testing.T) {
+	srv := testserver.New(t)
+	t.Setenv("HTTP_PROXY", srv.URL)
+
+	// The API host is skipped by no_proxy, so it is resolved directly and cannot be reached
+	_, err := newProxiedClient(t, teclient.ClientOptions{NoProxy: ".transparentedge.test"})
+	if err == nil {
+		t.Fatal("expected the API to be unreachable without the proxy")
+	}
+
+	if n := srv.ProxiedRequests(); n != 0 {
+		t.Errorf("expected no request through the proxy, got %d", n)
+	}
+}
+
+func TestClient_requestTimeout(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{RequestTimeout: 100 * time.Millisecond})
+
+	srv.SetLatency(time.Second)
+
+	_, err := client.GetBackends(t.Context(), teclient.ProdEnv)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestClient_userAgentSuffix(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{UserAgentSuffix: "drift-detection/1"})
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Fatalf("GetBackends: %s", err)
+	}
+
+	if ua := srv.UserAgent(); ua != "terraform-provider-transparentedge/test drift-detection/1" {
+		t.Errorf("unexpected User-Agent %q", ua)
+	}
+}
@@ -0,0 +1,35 @@
+package teclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrReadOnly matches, through errors.Is, the requests refused because the client is in read-only mode.
+var ErrReadOnly = errors.New("the provider is in read-only mode")
+
+// readRequestKey marks POST requests that only read data.
+type readRequestKey struct{}
+
+// markReadRequest flags a POST request as one that only reads data, so it is allowed in read-only mode.
+func markReadRequest(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), readRequestKey{}, true))
+}
+
+// isReadRequest reports whether the request cannot change anything in the API.
+func isReadRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	read, _ := req.Context().Value(readRequestKey{}).(bool)
+
+	return read
+}
+
+// ReadOnly reports whether the client refuses the requests that may change the CDN configuration.
+func (c *Client) ReadOnly() bool {
+	return c.readOnly
+}
@@ -0,0 +1,35 @@
+package teclient_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+func TestClient_readOnly(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{ReadOnly: true})
+
+	if _, err := client.CreateBackend(t.Context(), testBackend("origin1"), teclient.ProdEnv); !errors.Is(err, teclient.ErrReadOnly) {
+		t.Errorf("expected CreateBackend to be refused, got %v", err)
+	}
+
+	if err := client.DeleteBackend(t.Context(), 1, teclient.ProdEnv); !errors.Is(err, teclient.ErrReadOnly) {
+		t.Errorf("expected DeleteBackend to be refused, got %v", err)
+	}
+
+	if n := srv.CountRequests("", "/backends"); n != 0 {
+		t.Errorf("expected no write request to reach the API, got %d", n)
+	}
+
+	// Reads, including the read-style POSTs, are still allowed
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Errorf("GetBackends: %s", err)
+	}
+
+	if _, err := client.GetDNSCNAMEVerification(t.Context()); err != nil {
+		t.Errorf("GetDNSCNAMEVerification: %s", err)
+	}
+}
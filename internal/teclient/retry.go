@@ -0,0 +1,112 @@
+package teclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a request failing with a transient error is retried.
+	DefaultMaxRetries int = 4
+	// DefaultMaxBackoff caps the wait between two attempts of the same request.
+	DefaultMaxBackoff time.Duration = 30 * time.Second
+
+	baseBackoff time.Duration = 500 * time.Millisecond
+)
+
+// retrySafeKey marks POST requests that can be sent again without side effects.
+type retrySafeKey struct{}
+
+// markRetrySafe flags a POST request as safe to be retried on any transient failure,
+// either because it only reads data or because the API handles it idempotently.
+func markRetrySafe(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), retrySafeKey{}, true))
+}
+
+// isRetrySafe reports whether the request can be repeated without side effects.
+func isRetrySafe(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	safe, _ := req.Context().Value(retrySafeKey{}).(bool)
+
+	return safe
+}
+
+// shouldRetry decides if a failed attempt is worth repeating.
+// Requests that never reached the API (connection refused, 429) are always retried,
+// the rest only when repeating them is safe.
+func shouldRetry(req *http.Request, statusCode int, err error) bool {
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return true
+		}
+
+		return isRetrySafe(req) && isTransientNetError(err)
+	}
+
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isRetrySafe(req)
+	}
+
+	return false
+}
+
+func isTransientNetError(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryDelay returns how long to wait before the next attempt. The API's Retry-After
+// header is honoured when present, otherwise a jittered exponential backoff is used.
+// Both are capped by maxBackoff.
+func (c *Client) retryDelay(attempt int, header http.Header) time.Duration {
+	if delay, ok := parseRetryAfter(header.Get("Retry-After")); ok {
+		return min(delay, c.maxBackoff)
+	}
+
+	backoff := min(baseBackoff<<min(attempt, 16), c.maxBackoff)
+
+	// Equal jitter: half of the backoff is fixed, the other half random,
+	// so parallel operations hitting the same limit spread out.
+	return backoff/2 + rand.N(backoff/2+1) // nolint: gosec
+}
+
+// parseRetryAfter parses the Retry-After header, which holds either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
@@ -1,22 +1,22 @@
 package teclient
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 )
 
-func (c *Client) GetSiteVerifyString(siteDomain string) string {
+func (c *Client) GetSiteVerifyString(ctx context.Context, siteDomain string) string {
 	data := SiteVerifyStringAPIModelRequest{Domain: siteDomain}
 
-	req, err := c.prepareJSONRequest(data, "POST", fmt.Sprintf("%s/v1/companies/%d/siteverification/", c.HostURL, c.CompanyID))
+	req, err := c.prepareJSONRequest(ctx, data, "POST", fmt.Sprintf("%s/v1/companies/%d/siteverification/", c.HostURL, c.CompanyIDFor(ctx)))
 	if err != nil {
 		return ""
 	}
 
-	body, sc, err := c.doRequest(req)
+	// Asking for the verification string has no side effects
+	body, sc, err := c.doRequest(markReadRequest(markRetrySafe(req)))
 	if err != nil || sc != 200 {
 		return ""
 	}
@@ -31,19 +31,19 @@ func (c *Client) GetSiteVerifyString(siteDomain string) string {
 	return svsResp.Txt
 }
 
-func (c *Client) GetSites() ([]SiteAPIModel, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/companies/%d/sites/", c.HostURL, c.CompanyID), nil)
+func (c *Client) GetSites(ctx context.Context) ([]SiteAPIModel, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/companies/%d/sites/", c.HostURL, c.CompanyIDFor(ctx)), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, sc, err := c.doRequest(req)
+	body, sc, err := c.doCachedRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if sc != http.StatusOK {
-		return nil, fmt.Errorf("failure while retrieving the list of sites: %s", c.parseAPIError(body))
+		return nil, newAPIError("failure while retrieving the list of sites", req, sc, body)
 	}
 
 	sites := []SiteAPIModel{}
@@ -56,8 +56,8 @@ func (c *Client) GetSites() ([]SiteAPIModel, error) {
 	return sites, nil
 }
 
-func (c *Client) GetSite(siteID int) (*SiteAPIModel, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/companies/%d/sites/%d/", c.HostURL, c.CompanyID, siteID), nil)
+func (c *Client) GetSite(ctx context.Context, siteID int) (*SiteAPIModel, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/companies/%d/sites/%d/", c.HostURL, c.CompanyIDFor(ctx), siteID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +68,7 @@ func (c *Client) GetSite(siteID int) (*SiteAPIModel, error) {
 	}
 
 	if sc != http.StatusOK {
-		return nil, fmt.Errorf("failure while retrieving the site with ID %d: %s", siteID, c.parseAPIError(body))
+		return nil, newAPIError(fmt.Sprintf("failure while retrieving the site with ID %d", siteID), req, sc, body)
 	}
 
 	site := SiteAPIModel{}
@@ -81,16 +81,17 @@ func (c *Client) GetSite(siteID int) (*SiteAPIModel, error) {
 	return &site, nil
 }
 
-func (c *Client) CreateSite(site SiteNewAPIModel) (*SiteAPIModel, bool, error) {
+func (c *Client) CreateSite(ctx context.Context, site SiteNewAPIModel) (*SiteAPIModel, bool, error) {
 	// returns model, error, verify_error
-	req, err := c.prepareJSONRequest(site, "POST", fmt.Sprintf("%s/v1/companies/%d/sites/", c.HostURL, c.CompanyID))
+	req, err := c.prepareJSONRequest(ctx, site, "POST", fmt.Sprintf("%s/v1/companies/%d/sites/", c.HostURL, c.CompanyIDFor(ctx)))
 	if err != nil {
 		return nil, false, err
 	}
 
-	body, sc, err := c.doRequest(req)
-	if sc == http.StatusBadGateway {
-		return nil, false, errors.New("failed to create the site - API error")
+	// Creating a site is idempotent: an existing site is returned or activated again
+	body, sc, err := c.doRequest(markRetrySafe(req))
+	if err != nil {
+		return nil, false, err
 	}
 
 	if sc == http.StatusForbidden {
@@ -100,35 +101,33 @@ func (c *Client) CreateSite(site SiteNewAPIModel) (*SiteAPIModel, bool, error) {
 			"  * Option 2: A TXT record: _tcdn_challenge." + site.URL + " with the verification string\n"
 
 		// Best effor here to show the user the verification string
-		verifyString := c.GetSiteVerifyString(site.URL)
+		verifyString := c.GetSiteVerifyString(ctx, site.URL)
 		if verifyString != "" {
 			msg = msg + "\nThe verification string for this site is: " + verifyString + "\n"
 		}
 
-		msg = msg + "\nAPI Response: " + c.parseAPIError(body) + "\n\n" +
+		msg = msg + "\nAPI Response: " + newAPIError("", req, sc, body).Summary() + "\n\n" +
 			"If you need to get the verification string again, run 'terraform plan' and 'terraform show'" +
 			" with the datasource 'siteverify'.\nIn case of doubts please contact with support."
 
 		return nil, true, fmt.Errorf("validation error:\n%s", msg)
 	}
 
-	if sc == http.StatusBadRequest {
-		if strings.Contains(c.parseAPIError(body), "Site ownership denied") {
+	if sc != http.StatusOK && sc != http.StatusCreated { // 200 = new, 201 = activated again
+		apiErr := newAPIError("failed to create the site", req, sc, body)
+		if sc == http.StatusBadRequest && apiErr.Mentions("Site ownership denied") {
 			// site belongs to another company
-			return nil, false, fmt.Errorf("site not owned: %s", c.parseAPIError(body))
+			apiErr.Op = "site not owned"
+
+			return nil, false, apiErr
 		}
+
 		// check if the site already exists
-		if existingSite := c.GetIfExists(body, site.URL); existingSite != nil {
+		if existingSite := c.GetIfExists(ctx, apiErr, site.URL); existingSite != nil {
 			return existingSite, false, nil
 		}
-	}
-
-	if err != nil {
-		return nil, false, fmt.Errorf("%d - %s", sc, err.Error())
-	}
 
-	if sc != http.StatusOK && sc != http.StatusCreated { // 200 = new, 201 = activated again
-		return nil, false, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, false, apiErr
 	}
 
 	newSite := SiteAPIModel{}
@@ -141,11 +140,11 @@ func (c *Client) CreateSite(site SiteNewAPIModel) (*SiteAPIModel, bool, error) {
 	return &newSite, false, nil
 }
 
-func (c *Client) GetIfExists(body []byte, siteDomain string) *SiteAPIModel {
-	errorMessage := c.parseAPIError(body)
-	if strings.Contains(errorMessage, "already exists") {
+// GetIfExists returns the site named siteDomain when err reports that it already exists.
+func (c *Client) GetIfExists(ctx context.Context, err error, siteDomain string) *SiteAPIModel {
+	if IsConflict(err) {
 		// Try to find the site
-		sites, err := c.GetSites()
+		sites, err := c.GetSites(ctx)
 		if err == nil {
 			for _, site := range sites {
 				if site.URL == siteDomain {
@@ -158,8 +157,8 @@ func (c *Client) GetIfExists(body []byte, siteDomain string) *SiteAPIModel {
 	return nil
 }
 
-func (c *Client) DeleteSite(siteID int) error {
-	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/v1/companies/%d/sites/%d/", c.HostURL, c.CompanyID, siteID), nil)
+func (c *Client) DeleteSite(ctx context.Context, siteID int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/v1/companies/%d/sites/%d/", c.HostURL, c.CompanyIDFor(ctx), siteID), nil)
 	if err != nil {
 		return err
 	}
@@ -170,7 +169,7 @@ func (c *Client) DeleteSite(siteID int) error {
 	}
 
 	if sc != http.StatusNoContent {
-		return fmt.Errorf("%d - API request failed trying to DELETE the site ID %d: %s", sc, siteID, c.parseAPIError(body))
+		return newAPIError(fmt.Sprintf("API request failed trying to DELETE the site ID %d", siteID), req, sc, body)
 	}
 
 	return nil
@@ -0,0 +1,73 @@
+package teclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// DefaultMinTLSVersion is the lowest TLS version accepted from the API unless configured otherwise.
+const DefaultMinTLSVersion = tls.VersionTLS12
+
+// TLSVersions maps the version names accepted in the provider configuration to their values.
+var TLSVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSVersionNames returns the keys of TLSVersions, sorted.
+func TLSVersionNames() []string {
+	names := make([]string, 0, len(TLSVersions))
+	for name := range TLSVersions {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// newTLSConfig builds the TLS configuration used to reach the API from the client options.
+func newTLSConfig(insecure bool, opts ClientOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: insecure, // nolint: gosec
+		MinVersion:         opts.MinTLSVersion,
+	}
+
+	if config.MinVersion == 0 {
+		config.MinVersion = DefaultMinTLSVersion
+	}
+
+	if len(opts.CABundle) > 0 {
+		// The bundle is trusted on top of the system certificates, i.e: the CA of a TLS-intercepting proxy
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(opts.CABundle) {
+			return nil, errors.New("the CA bundle does not contain any PEM encoded certificate")
+		}
+
+		config.RootCAs = pool
+	}
+
+	if len(opts.ClientCertificate) > 0 || len(opts.ClientKey) > 0 {
+		if len(opts.ClientCertificate) == 0 || len(opts.ClientKey) == 0 {
+			return nil, errors.New("the client certificate and the client key must be provided together")
+		}
+
+		cert, err := tls.X509KeyPair(opts.ClientCertificate, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
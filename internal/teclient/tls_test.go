@@ -0,0 +1,68 @@
+package teclient_test
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/acctest"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+// tryNewClient creates a client for srv with opts, returning the error of NewClient.
+func tryNewClient(t *testing.T, srv *testserver.Server, opts teclient.ClientOptions) error {
+	t.Helper()
+
+	host := srv.URL
+	companyID := testserver.DefaultCompanyID
+	clientID := testserver.ClientID
+	clientSecret := testserver.ClientSecret
+	insecure := false
+	auth := true
+	version := "test"
+
+	_, err := teclient.NewClient(t.Context(), &host, &companyID, &clientID, &clientSecret, &insecure, &auth, &version, opts)
+
+	return err
+}
+
+func TestNewClient_caBundle(t *testing.T) {
+	srv := testserver.NewTLS(t, nil)
+
+	if err := tryNewClient(t, srv, teclient.ClientOptions{}); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("expected a certificate verification error, got %v", err)
+	}
+
+	if err := tryNewClient(t, srv, teclient.ClientOptions{CABundle: []byte(srv.CertificatePEM())}); err != nil {
+		t.Errorf("NewClient with the CA bundle: %s", err)
+	}
+
+	if err := tryNewClient(t, srv, teclient.ClientOptions{CABundle: []byte("not a certificate")}); err == nil || !strings.Contains(err.Error(), "CA bundle") {
+		t.Errorf("expected an invalid CA bundle error, got %v", err)
+	}
+}
+
+func TestNewClient_clientCertificate(t *testing.T) {
+	cert, key := acctest.SelfSignedCertificate(t, "terraform.example.com")
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM([]byte(cert))
+
+	srv := testserver.NewTLS(t, clientCAs)
+	caBundle := []byte(srv.CertificatePEM())
+
+	if err := tryNewClient(t, srv, teclient.ClientOptions{CABundle: caBundle}); err == nil {
+		t.Error("expected the API to reject a client without certificate")
+	}
+
+	opts := teclient.ClientOptions{CABundle: caBundle, ClientCertificate: []byte(cert), ClientKey: []byte(key)}
+	if err := tryNewClient(t, srv, opts); err != nil {
+		t.Errorf("NewClient with a client certificate: %s", err)
+	}
+
+	opts.ClientKey = nil
+	if err := tryNewClient(t, srv, opts); err == nil || !strings.Contains(err.Error(), "together") {
+		t.Errorf("expected an incomplete client certificate error, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package teclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// tokenCacheMargin is how long a cached access token must still be valid to be reused,
+// so a run does not start with a token about to expire.
+const tokenCacheMargin time.Duration = 10 * time.Minute
+
+// cachedToken is the content of a token cache file.
+type cachedToken struct {
+	Token     string    `json:"access_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// tokenCachePath returns the cache file of the credentials in use, keyed by the API URL and the client ID.
+func (c *Client) tokenCachePath() string {
+	sum := sha256.Sum256([]byte(c.HostURL + "\n" + c.ClientID))
+
+	return filepath.Join(c.tokenCacheDir, "token-"+hex.EncodeToString(sum[:])+".json")
+}
+
+// loadCachedToken sets the access token from the cache when it holds one valid long enough.
+// It returns false when a new token must be requested. The caller must hold tokenMu.
+func (c *Client) loadCachedToken(ctx context.Context) bool {
+	if c.tokenCacheDir == "" {
+		return false
+	}
+
+	data, err := os.ReadFile(c.tokenCachePath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			tflog.Warn(ctx, "Unable to read the token cache", map[string]any{"error": err.Error()})
+		}
+
+		return false
+	}
+
+	cached := cachedToken{}
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Token == "" {
+		tflog.Warn(ctx, "Ignoring an invalid token cache file", map[string]any{"path": c.tokenCachePath()})
+
+		return false
+	}
+
+	if time.Until(cached.ExpiresAt) < tokenCacheMargin {
+		return false
+	}
+
+	tflog.Debug(ctx, "Using the access token from the cache", map[string]any{"expires_at": cached.ExpiresAt})
+
+	c.Token = TokenStruct{Token: cached.Token, TokenType: "Bearer"}
+	c.setTokenExpiry(cached.ExpiresAt)
+
+	return true
+}
+
+// storeCachedToken saves the current access token for the next runs, if it has a known expiry.
+// The caller must hold tokenMu.
+func (c *Client) storeCachedToken(ctx context.Context) {
+	if c.tokenCacheDir == "" || c.tokenExpiry.IsZero() {
+		return
+	}
+
+	data, err := json.Marshal(cachedToken{Token: c.Token.Token, ExpiresAt: c.tokenExpiry})
+	if err == nil {
+		err = writeFileAtomic(c.tokenCachePath(), data)
+	}
+
+	if err != nil {
+		tflog.Warn(ctx, "Unable to write the token cache", map[string]any{"error": err.Error()})
+	}
+}
+
+// forgetCachedToken removes staleToken from the cache after the API rejected it.
+// A token cached meanwhile by another run is kept. The caller must hold tokenMu.
+func (c *Client) forgetCachedToken(ctx context.Context, staleToken string) {
+	if c.tokenCacheDir == "" {
+		return
+	}
+
+	path := c.tokenCachePath()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	cached := cachedToken{}
+	if json.Unmarshal(data, &cached) == nil && cached.Token != staleToken {
+		return
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		tflog.Warn(ctx, "Unable to remove the rejected token from the cache", map[string]any{"error": err.Error()})
+	}
+}
+
+// writeFileAtomic writes data to path readable by the owner only, through a temporary file
+// so concurrent runs never read a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".token-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	// CreateTemp already uses 0600, but be explicit about it
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
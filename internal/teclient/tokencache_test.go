@@ -0,0 +1,97 @@
+package teclient_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+// tokenCacheFiles returns the files of the token cache in dir.
+func tokenCacheFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "token-*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
+
+func TestClient_tokenCache(t *testing.T) {
+	srv := testserver.New(t)
+	opts := teclient.ClientOptions{TokenCacheDir: filepath.Join(t.TempDir(), "cache")}
+
+	first := newTestClient(t, srv, opts)
+	second := newTestClient(t, srv, opts)
+
+	if n := srv.CountRequests(http.MethodPost, tokenPath); n != 1 {
+		t.Errorf("expected a single token request, got %d", n)
+	}
+
+	if first.Token.Token != second.Token.Token {
+		t.Error("the second client did not reuse the cached token")
+	}
+
+	files := tokenCacheFiles(t, opts.TokenCacheDir)
+	if len(files) != 1 {
+		t.Fatalf("expected a single cache file, got %v", files)
+	}
+
+	info, err := os.Stat(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected the cache file to be readable by its owner only, got %s", perm)
+	}
+}
+
+func TestClient_tokenCacheInvalidatedOn401(t *testing.T) {
+	srv := testserver.New(t)
+	opts := teclient.ClientOptions{TokenCacheDir: t.TempDir()}
+
+	client := newTestClient(t, srv, opts)
+	staleToken := client.Token.Token
+
+	srv.ExpireTokens()
+
+	if _, err := client.GetBackends(t.Context(), teclient.ProdEnv); err != nil {
+		t.Fatalf("GetBackends: %s", err)
+	}
+
+	if client.Token.Token == staleToken {
+		t.Fatal("the rejected token was reused from the cache")
+	}
+
+	// The next run picks the renewed token
+	next := newTestClient(t, srv, opts)
+
+	if next.Token.Token != client.Token.Token {
+		t.Error("the renewed token was not cached")
+	}
+
+	if n := srv.CountRequests(http.MethodPost, tokenPath); n != 2 {
+		t.Errorf("expected 2 token requests, got %d", n)
+	}
+}
+
+func TestClient_tokenCacheSkipsShortLivedTokens(t *testing.T) {
+	srv := testserver.New(t)
+	srv.SetTokenTTL(5 * time.Minute)
+
+	opts := teclient.ClientOptions{TokenCacheDir: t.TempDir()}
+
+	newTestClient(t, srv, opts)
+	newTestClient(t, srv, opts)
+
+	if n := srv.CountRequests(http.MethodPost, tokenPath); n != 2 {
+		t.Errorf("expected a token request per client, got %d", n)
+	}
+}
@@ -1,30 +1,48 @@
 package teclient
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 	"regexp"
+	"slices"
 )
 
 var tfProviderSuffixRe = regexp.MustCompile(`\s{0,1}\[Terraform/[^\]]+\]$`)
 
-func (c *Client) GetVclConfs(offset int, environment APIEnvironment) ([]VCLConfAPIModel, error) {
+// VCLCompilationError is returned when the API rejects a configuration that does not compile.
+type VCLCompilationError struct {
+	*APIError
+}
+
+func (e *VCLCompilationError) Error() string {
+	return "VCL COMPILATION ERROR\n\n" + e.Message + "\n"
+}
+
+func (e *VCLCompilationError) Unwrap() error {
+	return e.APIError
+}
+
+// GetVclConfs returns a page of the configuration history of environment.
+// The API calls the page number "offset", pages start at 1 and asking past the last one is a 404.
+func (c *Client) GetVclConfs(ctx context.Context, page int, environment APIEnvironment) ([]VCLConfAPIModel, error) {
 	envpath := c.MustGetAPIEnvironmentPath(environment)
 
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s/%d/config/?offset=%d", c.HostURL, envpath, c.CompanyID, offset), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s/%d/config/?offset=%d", c.HostURL, envpath, c.CompanyIDFor(ctx), page), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, sc, err := c.doRequest(req)
+	body, sc, err := c.doCachedRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
 	if sc != http.StatusOK {
-		return nil, fmt.Errorf("failure while retrieving the list of configurations: %s", c.parseAPIError(body))
+		return nil, newAPIError(fmt.Sprintf("failure while retrieving the page %d of the list of configurations", page), req, sc, body)
 	}
 
 	vclconfs := []VCLConfAPIModel{}
@@ -37,33 +55,87 @@ func (c *Client) GetVclConfs(offset int, environment APIEnvironment) ([]VCLConfA
 	return vclconfs, nil
 }
 
-func (c *Client) GetActiveVCLConf(environment APIEnvironment) (*VCLConfAPIModel, error) {
-	confs, err := c.GetVclConfs(1, environment)
-	if err != nil {
-		return nil, err
+// IterVCLConfs walks the configuration history of environment, newest first, fetching the pages as needed.
+// It stops after limit configurations, or at the end of the history when limit is 0 or less.
+// Uploads made while walking shift the pages, the configurations already returned are skipped.
+func (c *Client) IterVCLConfs(ctx context.Context, environment APIEnvironment, limit int) iter.Seq2[VCLConfAPIModel, error] {
+	return func(yield func(VCLConfAPIModel, error) bool) {
+		seen := map[int]bool{}
+
+		for page := 1; ; page++ {
+			confs, err := c.GetVclConfs(ctx, page, environment)
+			if IsNotFound(err) {
+				return
+			}
+
+			if err != nil {
+				yield(VCLConfAPIModel{}, err)
+
+				return
+			}
+
+			// The API sorts by upload date, make it explicit so pages can be merged
+			slices.SortFunc(confs, func(a, b VCLConfAPIModel) int { return b.ID - a.ID })
+
+			fresh := 0
+
+			for _, conf := range confs {
+				if seen[conf.ID] {
+					continue
+				}
+
+				seen[conf.ID] = true
+				fresh++
+
+				// remove version suffix
+				conf.Comment = stripProviderSuffix(conf.Comment)
+
+				if !yield(conf, nil) || (limit > 0 && len(seen) >= limit) {
+					return
+				}
+			}
+
+			// An empty page, or one with nothing new, is the end of the history
+			if fresh == 0 {
+				return
+			}
+		}
 	}
+}
 
-	top := VCLConfAPIModel{ID: -1}
-	for _, vc := range confs {
-		if vc.ID > top.ID {
-			top = vc
+// ListVCLConfs returns up to limit configurations of the history of environment, newest first.
+// The whole history is returned when limit is 0 or less.
+func (c *Client) ListVCLConfs(ctx context.Context, environment APIEnvironment, limit int) ([]VCLConfAPIModel, error) {
+	confs := []VCLConfAPIModel{}
+
+	for conf, err := range c.IterVCLConfs(ctx, environment, limit) {
+		if err != nil {
+			return nil, err
 		}
+
+		confs = append(confs, conf)
 	}
 
-	if top.ID <= 0 {
-		return nil, errors.New("no VCL configurations found")
+	return confs, nil
+}
+
+func (c *Client) GetActiveVCLConf(ctx context.Context, environment APIEnvironment) (*VCLConfAPIModel, error) {
+	confs, err := c.ListVCLConfs(ctx, environment, 1)
+	if err != nil {
+		return nil, err
 	}
 
-	// remove version suffix
-	top.Comment = stripProviderSuffix(top.Comment)
+	if len(confs) == 0 {
+		return nil, errors.New("no VCL configurations found")
+	}
 
-	return &top, nil
+	return &confs[0], nil
 }
 
-func (c *Client) GetVCLConfByID(environment APIEnvironment, id int) (*VCLConfAPIModel, error) {
+func (c *Client) GetVCLConfByID(ctx context.Context, environment APIEnvironment, id int) (*VCLConfAPIModel, error) {
 	envpath := c.MustGetAPIEnvironmentPath(environment)
 
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s/%d/config/%d", c.HostURL, envpath, c.CompanyID, id), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s/%d/config/%d", c.HostURL, envpath, c.CompanyIDFor(ctx), id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +146,7 @@ func (c *Client) GetVCLConfByID(environment APIEnvironment, id int) (*VCLConfAPI
 	}
 
 	if sc != http.StatusOK {
-		return nil, fmt.Errorf("failure while retrieving the list of configurations: %s", c.parseAPIError(body))
+		return nil, newAPIError(fmt.Sprintf("failure while retrieving the configuration with ID %d", id), req, sc, body)
 	}
 
 	conf := VCLConfAPIModel{}
@@ -90,33 +162,29 @@ func (c *Client) GetVCLConfByID(environment APIEnvironment, id int) (*VCLConfAPI
 	return &conf, nil
 }
 
-func (c *Client) CreateVclconf(vclconf NewVCLConfAPIModel, environment APIEnvironment) (*VCLConfAPIModel, error) {
+func (c *Client) CreateVclconf(ctx context.Context, vclconf NewVCLConfAPIModel, environment APIEnvironment) (*VCLConfAPIModel, error) {
 	envpath := c.MustGetAPIEnvironmentPath(environment)
 
 	// append version suffix
 	vclconf.Comment = appendProviderSuffix(vclconf.Comment, c.ProviderVersion)
 
-	req, err := c.prepareJSONRequest(vclconf, "POST", fmt.Sprintf("%s/v1/%s/%d/config/", c.HostURL, envpath, c.CompanyID))
+	req, err := c.prepareJSONRequest(ctx, vclconf, "POST", fmt.Sprintf("%s/v1/%s/%d/config/", c.HostURL, envpath, c.CompanyIDFor(ctx)))
 	if err != nil {
 		return nil, err
 	}
 
 	body, sc, err := c.doRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("%d - %s", sc, err.Error())
+		return nil, err
 	}
 
-	if sc == http.StatusBadRequest {
-		apiError := c.parseAPIError(body)
-
-		err := json.Unmarshal(body, &apiError)
-		if err == nil {
-			return nil, fmt.Errorf("VCL COMPILATION ERROR\n\n%s\n", apiError) // nolint
+	if sc != http.StatusOK && sc != http.StatusCreated {
+		apiErr := newAPIError("failed to create the configuration", req, sc, body)
+		if sc == http.StatusBadRequest && apiErr.Message != "" && len(apiErr.FieldErrors) == 0 {
+			return nil, &VCLCompilationError{APIError: apiErr}
 		}
-	}
 
-	if sc != http.StatusOK && sc != http.StatusCreated {
-		return nil, fmt.Errorf("%d - %s", sc, c.parseAPIError(body))
+		return nil, apiErr
 	}
 
 	newVclConf := VCLConfAPIModel{}
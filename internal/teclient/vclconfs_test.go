@@ -0,0 +1,129 @@
+package teclient_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/testserver"
+)
+
+// uploadVCLConfs uploads n configurations and returns their IDs, oldest first.
+func uploadVCLConfs(t *testing.T, client *teclient.Client, n int) []int {
+	t.Helper()
+
+	ids := make([]int, 0, n)
+
+	for i := range n {
+		conf, err := client.CreateVclconf(t.Context(), teclient.NewVCLConfAPIModel{
+			VCLCode: "sub vcl_recv {}\n",
+			Comment: "version " + strconv.Itoa(i),
+		}, teclient.ProdEnv)
+		if err != nil {
+			t.Fatalf("CreateVclconf: %s", err)
+		}
+
+		ids = append(ids, conf.ID)
+	}
+
+	return ids
+}
+
+func TestIterVCLConfs(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{})
+
+	ids := uploadVCLConfs(t, client, 2*testserver.ConfigPageSize+5)
+
+	confs, err := client.ListVCLConfs(t.Context(), teclient.ProdEnv, 0)
+	if err != nil {
+		t.Fatalf("ListVCLConfs: %s", err)
+	}
+
+	if len(confs) != len(ids) {
+		t.Fatalf("expected %d configurations, got %d", len(ids), len(confs))
+	}
+
+	for i, conf := range confs {
+		if want := ids[len(ids)-1-i]; conf.ID != want {
+			t.Fatalf("configuration %d: expected ID %d, got %d", i, want, conf.ID)
+		}
+	}
+
+	if confs[0].Comment != "version 24" {
+		t.Errorf("expected the provider suffix to be stripped, got %q", confs[0].Comment)
+	}
+
+	// The history has 3 pages, the 4th one is a 404
+	if n := srv.CountRequests(http.MethodGet, "/config"); n != 4 {
+		t.Errorf("expected 4 page requests, got %d", n)
+	}
+}
+
+func TestIterVCLConfs_limit(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{})
+
+	uploadVCLConfs(t, client, 2*testserver.ConfigPageSize+5)
+
+	confs, err := client.ListVCLConfs(t.Context(), teclient.ProdEnv, testserver.ConfigPageSize+2)
+	if err != nil {
+		t.Fatalf("ListVCLConfs: %s", err)
+	}
+
+	if len(confs) != testserver.ConfigPageSize+2 {
+		t.Errorf("expected %d configurations, got %d", testserver.ConfigPageSize+2, len(confs))
+	}
+
+	// Only the pages needed are downloaded
+	if n := srv.CountRequests(http.MethodGet, "/config"); n != 2 {
+		t.Errorf("expected 2 page requests, got %d", n)
+	}
+}
+
+func TestIterVCLConfs_uploadWhileIterating(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{})
+
+	uploadVCLConfs(t, client, 2*testserver.ConfigPageSize)
+
+	seen := map[int]bool{}
+	uploaded := false
+
+	for conf, err := range client.IterVCLConfs(t.Context(), teclient.ProdEnv, 0) {
+		if err != nil {
+			t.Fatalf("IterVCLConfs: %s", err)
+		}
+
+		if seen[conf.ID] {
+			t.Fatalf("configuration %d returned twice", conf.ID)
+		}
+
+		seen[conf.ID] = true
+
+		// Shift the second page by one
+		if !uploaded {
+			uploadVCLConfs(t, client, 1)
+			uploaded = true
+		}
+	}
+
+	if len(seen) != 2*testserver.ConfigPageSize {
+		t.Errorf("expected %d configurations, got %d", 2*testserver.ConfigPageSize, len(seen))
+	}
+}
+
+func TestIterVCLConfs_emptyHistory(t *testing.T) {
+	srv := testserver.New(t)
+	client := newTestClient(t, srv, teclient.ClientOptions{})
+
+	confs, err := client.ListVCLConfs(t.Context(), teclient.StagingEnv, 0)
+	if err != nil || len(confs) != 0 {
+		t.Errorf("expected an empty history, got %v, %v", confs, err)
+	}
+
+	if _, err := client.GetActiveVCLConf(t.Context(), teclient.StagingEnv); err == nil {
+		t.Error("expected an error without configurations")
+	}
+}
@@ -0,0 +1,151 @@
+package testserver
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+func (*Server) handleListBackends(w http.ResponseWriter, r *http.Request, c *company) {
+	backends := make([]*teclient.BackendAPIModel, 0, len(c.backends[pathEnv(r)]))
+	for _, backend := range c.backends[pathEnv(r)] {
+		backends = append(backends, backend)
+	}
+
+	slices.SortFunc(backends, func(a, b *teclient.BackendAPIModel) int { return a.ID - b.ID })
+
+	writeJSON(w, http.StatusOK, backends)
+}
+
+func (*Server) handleGetBackend(w http.ResponseWriter, r *http.Request, c *company) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	backend, found := c.backends[pathEnv(r)][id]
+	if !found {
+		writeNotFound(w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, backend)
+}
+
+func (s *Server) handleCreateBackend(w http.ResponseWriter, r *http.Request, c *company) {
+	data := teclient.NewBackendAPIModel{}
+	if !decodeBody(w, r, &data) {
+		return
+	}
+
+	backend := &teclient.BackendAPIModel{
+		ID:           s.newID(),
+		Company:      c.id,
+		Name:         data.Name,
+		Origin:       data.Origin,
+		Ssl:          data.Ssl,
+		Port:         data.Port,
+		Headers:      data.Headers,
+		HCHost:       data.HCHost,
+		HCPath:       data.HCPath,
+		HCStatusCode: data.HCStatusCode,
+		HCInterval:   data.HCInterval,
+		HCDisabled:   data.HCDisabled,
+	}
+
+	if !validateBackend(w, c, pathEnv(r), backend) {
+		return
+	}
+
+	c.backends[pathEnv(r)][backend.ID] = backend
+
+	writeJSON(w, http.StatusCreated, backend)
+}
+
+func (*Server) handleUpdateBackend(w http.ResponseWriter, r *http.Request, c *company) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, found := c.backends[pathEnv(r)][id]; !found {
+		writeNotFound(w)
+
+		return
+	}
+
+	backend := &teclient.BackendAPIModel{}
+	if !decodeBody(w, r, backend) {
+		return
+	}
+
+	backend.ID = id
+	backend.Company = c.id
+
+	if !validateBackend(w, c, pathEnv(r), backend) {
+		return
+	}
+
+	c.backends[pathEnv(r)][id] = backend
+
+	writeJSON(w, http.StatusOK, backend)
+}
+
+func (*Server) handleDeleteBackend(w http.ResponseWriter, r *http.Request, c *company) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	backend, found := c.backends[pathEnv(r)][id]
+	if !found {
+		writeNotFound(w)
+
+		return
+	}
+
+	if active := c.activeConfig(pathEnv(r)); active != nil && strings.Contains(active.VCLCode, vclName(backend)) {
+		writeJSON(w, http.StatusForbidden, map[string]string{
+			"detail": "The backend " + backend.Name + " has references in active config.",
+		})
+
+		return
+	}
+
+	delete(c.backends[pathEnv(r)], id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateBackend writes a validation error and returns false if backend cannot be stored.
+func validateBackend(w http.ResponseWriter, c *company, env teclient.APIEnvironment, backend *teclient.BackendAPIModel) bool {
+	switch {
+	case backend.Name == "":
+		writeFieldError(w, "name", "This field may not be blank.")
+	case backend.Origin == "":
+		writeFieldError(w, "origin", "This field may not be blank.")
+	case backend.Port < 1 || backend.Port > 65535:
+		writeFieldError(w, "port", "Ensure this value is between 1 and 65535.")
+	default:
+		for _, existing := range c.backends[env] {
+			if existing.Name == backend.Name && existing.ID != backend.ID {
+				writeFieldError(w, "name", "backend with this name already exists.")
+
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// vclName returns the name used to reference backend from the VCL code.
+func vclName(backend *teclient.BackendAPIModel) string {
+	return "c" + strconv.Itoa(backend.Company) + "_" + backend.Name
+}
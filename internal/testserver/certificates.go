@@ -0,0 +1,139 @@
+package testserver
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+func (*Server) handleListCertificates(w http.ResponseWriter, _ *http.Request, c *company) {
+	certs := make([]*teclient.SSLCertificate, 0, len(c.certificates))
+	for _, cert := range c.certificates {
+		certs = append(certs, cert)
+	}
+
+	slices.SortFunc(certs, func(a, b *teclient.SSLCertificate) int { return a.ID - b.ID })
+
+	writeJSON(w, http.StatusOK, certs)
+}
+
+func (*Server) handleGetCertificate(w http.ResponseWriter, r *http.Request, c *company) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	cert, found := c.certificates[id]
+	if !found {
+		writeNotFound(w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cert)
+}
+
+func (s *Server) handleCreateCertificate(w http.ResponseWriter, r *http.Request, c *company) {
+	data := teclient.SSLCustomCertificate{}
+	if !decodeBody(w, r, &data) {
+		return
+	}
+
+	cert := &teclient.SSLCertificate{ID: s.newID(), Company: c.id}
+	if !fillCustomCertificate(w, cert, data) {
+		return
+	}
+
+	c.certificates[cert.ID] = cert
+
+	writeJSON(w, http.StatusCreated, cert)
+}
+
+func (*Server) handleUpdateCertificate(w http.ResponseWriter, r *http.Request, c *company) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	cert, found := c.certificates[id]
+	if !found {
+		writeNotFound(w)
+
+		return
+	}
+
+	data := teclient.SSLCustomCertificate{}
+	if !decodeBody(w, r, &data) {
+		return
+	}
+
+	updated := *cert
+	if !fillCustomCertificate(w, &updated, data) {
+		return
+	}
+
+	c.certificates[id] = &updated
+
+	// The API answers updates without the certificate
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Certificate updated."})
+}
+
+func (*Server) handleDeleteCertificate(w http.ResponseWriter, r *http.Request, c *company) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, found := c.certificates[id]; !found {
+		writeNotFound(w)
+
+		return
+	}
+
+	delete(c.certificates, id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// fillCustomCertificate validates the uploaded PEM data and copies it into cert.
+func fillCustomCertificate(w http.ResponseWriter, cert *teclient.SSLCertificate, data teclient.SSLCustomCertificate) bool {
+	block, _ := pem.Decode([]byte(data.PublicKey))
+	if block == nil || block.Type != "CERTIFICATE" {
+		writeFieldError(w, "cert", "The certificate is not in PEM format.")
+
+		return false
+	}
+
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		writeFieldError(w, "cert", "Invalid certificate: "+err.Error())
+
+		return false
+	}
+
+	keyBlock, _ := pem.Decode([]byte(data.PrivateKey))
+	if keyBlock == nil || !strings.HasSuffix(keyBlock.Type, "PRIVATE KEY") {
+		writeFieldError(w, "key", "The private key is not in PEM format.")
+
+		return false
+	}
+
+	cert.CommonName = parsed.Subject.CommonName
+	cert.Domains = parsed.DNSNames
+	cert.Expiration = expiration(parsed.NotAfter)
+	cert.PublicKey = data.PublicKey
+	cert.PrivateKey = data.PrivateKey
+
+	return true
+}
+
+// expiration formats t as the API does, a UNIX timestamp with decimals.
+func expiration(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10) + ".0"
+}
@@ -0,0 +1,616 @@
+// Package testserver implements an in-memory fake of the Transparent Edge API.
+// It serves the endpoints used by the provider so the acceptance tests can run offline,
+// and lets tests inject faults (latency, 5xx, expired tokens, failed verifications).
+package testserver
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+const (
+	// DefaultCompanyID is the company the credentials of a new Server have access to.
+	DefaultCompanyID = 300
+	// ClientID and ClientSecret are the credentials accepted by the token endpoint.
+	ClientID     = "acctest-client-id"
+	ClientSecret = "acctest-client-secret" // nolint: gosec
+	// ConfigPageSize is the number of VCL configurations returned per page.
+	ConfigPageSize = 10
+
+	defaultTokenTTL = time.Hour
+)
+
+// Server is a fake Transparent Edge API listening on a local address.
+type Server struct {
+	// URL is the base URL of the API, i.e: "http://127.0.0.1:34567".
+	URL string
+
+	httpServer *httptest.Server
+
+	mu        sync.Mutex
+	nextID    int
+	companies map[int]*company
+	tokens    map[string]time.Time
+	tokenTTL  time.Duration
+	faults    []*Fault
+	latency   time.Duration
+	requests  []string
+	// User-Agent header of the last request
+	userAgent string
+	// requests received as a proxy, with an absolute URL in the request line
+	proxied int
+	// requests being served right now, and the highest value seen
+	inFlight    int
+	maxInFlight int
+
+	// site verification and ownership, shared by all the companies
+	unverifiedDomains map[string]bool
+	foreignDomains    map[string]bool
+	// domains whose certificate requests fail, with the log reported by the API
+	failingCertDomains map[string]string
+
+	dnsProviders []teclient.CRDNSProvider
+	ipRanges     []string
+}
+
+// company holds the objects owned by a single company.
+type company struct {
+	id           int
+	sites        map[int]*teclient.SiteAPIModel
+	backends     map[teclient.APIEnvironment]map[int]*teclient.BackendAPIModel
+	configs      map[teclient.APIEnvironment][]*teclient.VCLConfAPIModel
+	certificates map[int]*teclient.SSLCertificate
+	credentials  map[int]*teclient.CRDNSCredential
+	dnsCertReqs  map[int]*teclient.CertReqDNS
+	httpCertReqs map[int]*teclient.CertReqHTTP
+}
+
+// Fault makes the requests matching Method and Path fail or slow down.
+type Fault struct {
+	// Method matches any method when empty.
+	Method string
+	// Path is matched as a substring of the request path, it matches any path when empty.
+	Path string
+	// StatusCode and Body are returned instead of the real response, unless StatusCode is 0.
+	StatusCode int
+	Body       string
+	// Header is added to the faulty response, i.e: Retry-After.
+	Header http.Header
+	// Latency is waited before answering.
+	Latency time.Duration
+	// Times is the number of requests affected, 0 affects every request.
+	Times int
+}
+
+// New starts a fake API server that is closed when the test finishes.
+func New(tb testing.TB) *Server {
+	tb.Helper()
+
+	s := newServer()
+	s.httpServer = httptest.NewServer(s.routes())
+	s.URL = s.httpServer.URL
+	tb.Cleanup(s.httpServer.Close)
+
+	return s
+}
+
+// NewTLS is New serving HTTPS with a self-signed certificate, see CertificatePEM.
+// When clientCAs is not nil the server requires a client certificate signed by one of them.
+func NewTLS(tb testing.TB, clientCAs *x509.CertPool) *Server {
+	tb.Helper()
+
+	s := newServer()
+	s.httpServer = httptest.NewUnstartedServer(s.routes())
+	// The handshakes rejected on purpose by the tests are not worth a log line
+	s.httpServer.Config.ErrorLog = log.New(io.Discard, "", 0)
+
+	if clientCAs != nil {
+		s.httpServer.TLS = &tls.Config{
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  clientCAs,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	s.httpServer.StartTLS()
+	s.URL = s.httpServer.URL
+	tb.Cleanup(s.httpServer.Close)
+
+	return s
+}
+
+// CertificatePEM returns the PEM encoded certificate of a server started by NewTLS.
+func (s *Server) CertificatePEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.httpServer.Certificate().Raw}))
+}
+
+func newServer() *Server {
+	s := &Server{
+		nextID:             1000,
+		companies:          map[int]*company{},
+		tokens:             map[string]time.Time{},
+		tokenTTL:           defaultTokenTTL,
+		unverifiedDomains:  map[string]bool{},
+		foreignDomains:     map[string]bool{},
+		failingCertDomains: map[string]string{},
+		dnsProviders:       defaultDNSProviders(),
+		ipRanges:           []string{"192.0.2.0/24", "198.51.100.0/24", "2001:db8::/32"},
+	}
+	s.AddCompany(DefaultCompanyID)
+
+	return s
+}
+
+func defaultDNSProviders() []teclient.CRDNSProvider {
+	return []teclient.CRDNSProvider{
+		{ID: 1, HookName: "dns_aws", Provider: "AWS (Route53)", Keys: []teclient.CRDNSKeys{
+			{KeyNameID: 1, KeyName: "AWS_ACCESS_KEY_ID"},
+			{KeyNameID: 2, KeyName: "AWS_SECRET_ACCESS_KEY"},
+		}},
+		{ID: 2, HookName: "dns_cf", Provider: "Cloudflare", Keys: []teclient.CRDNSKeys{
+			{KeyNameID: 3, KeyName: "CF_Account_ID"},
+			{KeyNameID: 4, KeyName: "CF_Token"},
+		}},
+		{ID: 3, HookName: "dns_cname", Provider: "CNAME Verification", Keys: []teclient.CRDNSKeys{
+			{KeyNameID: 5, KeyName: "CNAME_VERIFICATION"},
+		}},
+		{ID: 4, HookName: "dns_tcdn", Provider: "Transparent Edge", Keys: []teclient.CRDNSKeys{
+			{KeyNameID: 6, KeyName: "TCDN_DNS_PROVIDER"},
+		}},
+	}
+}
+
+// AddCompany gives the test credentials access to another company.
+func (s *Server) AddCompany(companyID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.companies[companyID]; exists {
+		return
+	}
+
+	s.companies[companyID] = &company{
+		id:    companyID,
+		sites: map[int]*teclient.SiteAPIModel{},
+		backends: map[teclient.APIEnvironment]map[int]*teclient.BackendAPIModel{
+			teclient.ProdEnv:    {},
+			teclient.StagingEnv: {},
+		},
+		configs:      map[teclient.APIEnvironment][]*teclient.VCLConfAPIModel{},
+		certificates: map[int]*teclient.SSLCertificate{},
+		credentials:  map[int]*teclient.CRDNSCredential{},
+		dnsCertReqs:  map[int]*teclient.CertReqDNS{},
+		httpCertReqs: map[int]*teclient.CertReqHTTP{},
+	}
+}
+
+// InjectFault registers a fault, faults are evaluated in the order they were injected.
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// SetLatency delays every response by latency.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// SetTokenTTL changes the lifetime announced for the access tokens issued from now on.
+func (s *Server) SetTokenTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokenTTL = ttl
+}
+
+// IssueToken returns a new access token valid for ttl, as if it was obtained out of band.
+func (s *Server) IssueToken(ttl time.Duration) string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	token := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[token] = time.Now().Add(ttl)
+
+	return token
+}
+
+// ExpireTokens invalidates every access token issued so far, the next requests get a 401.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	clear(s.tokens)
+}
+
+// Remove deletes the object with the given ID behind the provider's back, like a change made
+// from the dashboard. Sites are disabled instead, as the API does. It returns false if no object has that ID.
+func (s *Server) Remove(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.companies {
+		if site, found := c.sites[id]; found {
+			site.Active = false
+
+			return true
+		}
+
+		for env, configs := range c.configs {
+			for i, conf := range configs {
+				if conf.ID == id {
+					c.configs[env] = slices.Delete(configs, i, i+1)
+
+					return true
+				}
+			}
+		}
+
+		for _, objects := range []interface{ remove(id int) bool }{
+			idMap[*teclient.BackendAPIModel](c.backends[teclient.ProdEnv]),
+			idMap[*teclient.BackendAPIModel](c.backends[teclient.StagingEnv]),
+			idMap[*teclient.SSLCertificate](c.certificates),
+			idMap[*teclient.CRDNSCredential](c.credentials),
+			idMap[*teclient.CertReqDNS](c.dnsCertReqs),
+			idMap[*teclient.CertReqHTTP](c.httpCertReqs),
+		} {
+			if objects.remove(id) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// idMap is a collection of objects indexed by ID.
+type idMap[T any] map[int]T
+
+func (m idMap[T]) remove(id int) bool {
+	_, found := m[id]
+	delete(m, id)
+
+	return found
+}
+
+// Requests returns the "METHOD /path" of every request received so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+// UserAgent returns the User-Agent header of the last request received.
+func (s *Server) UserAgent() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.userAgent
+}
+
+// ProxiedRequests returns how many requests were received acting as an HTTP proxy.
+func (s *Server) ProxiedRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.proxied
+}
+
+// CountRequests returns how many requests were received for method and a path containing path.
+func (s *Server) CountRequests(method string, path string) int {
+	count := 0
+
+	for _, req := range s.Requests() {
+		reqMethod, reqPath, _ := strings.Cut(req, " ")
+		if (method == "" || reqMethod == method) && strings.Contains(reqPath, path) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// MaxInFlight returns the highest number of requests served at the same time so far.
+func (s *Server) MaxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.maxInFlight
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /v1/oauth2/access_token", s.handleToken)
+	mux.HandleFunc("GET /v2/companies/ipranges", s.handleIPRanges)
+
+	auth := func(pattern string, handler func(http.ResponseWriter, *http.Request, *company)) {
+		mux.HandleFunc(pattern, s.authenticated(handler))
+	}
+
+	auth("POST /v1/companies/{company}/siteverification", s.handleSiteVerification)
+	auth("GET /v1/companies/{company}/sites", s.handleListSites)
+	auth("POST /v1/companies/{company}/sites", s.handleCreateSite)
+	auth("GET /v1/companies/{company}/sites/{id}", s.handleGetSite)
+	auth("DELETE /v1/companies/{company}/sites/{id}", s.handleDeleteSite)
+
+	auth("GET /v1/{env}/{company}/backends", s.handleListBackends)
+	auth("POST /v1/{env}/{company}/backends", s.handleCreateBackend)
+	auth("GET /v1/{env}/{company}/backends/{id}", s.handleGetBackend)
+	auth("PUT /v1/{env}/{company}/backends/{id}", s.handleUpdateBackend)
+	auth("DELETE /v1/{env}/{company}/backends/{id}", s.handleDeleteBackend)
+
+	auth("GET /v1/{env}/{company}/config", s.handleListConfigs)
+	auth("POST /v1/{env}/{company}/config", s.handleCreateConfig)
+	auth("GET /v1/{env}/{company}/config/{id}", s.handleGetConfig)
+
+	auth("GET /v1/autoprovisioning/{company}/sslconfig", s.handleListCertificates)
+	auth("POST /v1/autoprovisioning/{company}/sslconfig", s.handleCreateCertificate)
+	auth("GET /v1/autoprovisioning/{company}/sslconfig/{id}", s.handleGetCertificate)
+	auth("PUT /v1/autoprovisioning/{company}/sslconfig/{id}", s.handleUpdateCertificate)
+	auth("DELETE /v1/autoprovisioning/{company}/sslconfig/{id}", s.handleDeleteCertificate)
+
+	mux.HandleFunc("GET /v1/autoprovisioning/dnshook", s.authenticated(s.handleListDNSProviders))
+	auth("POST /v1/autoprovisioning/{company}/ssldnsverificationcname", s.handleDNSCNAMEVerification)
+
+	auth("GET /v1/autoprovisioning/{company}/dnscredential", s.handleListCredentials)
+	auth("POST /v1/autoprovisioning/{company}/dnscredential", s.handleCreateCredential)
+	auth("GET /v1/autoprovisioning/{company}/dnscredential/{id}", s.handleGetCredential)
+	auth("PUT /v1/autoprovisioning/{company}/dnscredential/{id}", s.handleUpdateCredential)
+	auth("DELETE /v1/autoprovisioning/{company}/dnscredential/{id}", s.handleDeleteCredential)
+
+	auth("GET /v1/autoprovisioning/{company}/dnscertrequest", s.handleListDNSCertReqs)
+	auth("POST /v1/autoprovisioning/{company}/dnscertrequest", s.handleCreateDNSCertReq)
+	auth("GET /v1/autoprovisioning/{company}/dnscertrequest/{id}", s.handleGetDNSCertReq)
+	auth("PUT /v1/autoprovisioning/{company}/dnscertrequest/{id}", s.handleUpdateDNSCertReq)
+	auth("DELETE /v1/autoprovisioning/{company}/dnscertrequest/{id}", s.handleDeleteDNSCertReq)
+
+	auth("GET /v1/autoprovisioning/{company}/sslcertificaterequest", s.handleListHTTPCertReqs)
+	auth("POST /v1/autoprovisioning/{company}/sslcertificaterequest", s.handleCreateHTTPCertReq)
+	auth("GET /v1/autoprovisioning/{company}/sslcertificaterequest/{id}", s.handleGetHTTPCertReq)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The API is not consistent with trailing slashes, accept both forms
+		if r.URL.Path != "/" {
+			r.URL.Path = strings.TrimSuffix(r.URL.Path, "/")
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.userAgent = r.UserAgent()
+
+		if strings.HasPrefix(r.RequestURI, "http://") {
+			s.proxied++
+		}
+
+		s.inFlight++
+		s.maxInFlight = max(s.maxInFlight, s.inFlight)
+		latency := s.latency
+		fault := s.matchFault(r)
+		s.mu.Unlock()
+
+		defer func() {
+			s.mu.Lock()
+			s.inFlight--
+			s.mu.Unlock()
+		}()
+
+		if fault != nil {
+			latency += fault.Latency
+		}
+
+		if latency > 0 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(latency):
+			}
+		}
+
+		if fault != nil && fault.StatusCode != 0 {
+			for key, values := range fault.Header {
+				for _, value := range values {
+					w.Header().Add(key, value)
+				}
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(fault.StatusCode)
+			_, _ = w.Write([]byte(fault.Body))
+
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// matchFault returns the first fault matching r, consuming one of its uses.
+// The caller must hold mu.
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, fault := range s.faults {
+		if fault.Method != "" && fault.Method != r.Method {
+			continue
+		}
+
+		if !strings.Contains(r.URL.Path, fault.Path) {
+			continue
+		}
+
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = slices.Delete(s.faults, i, i+1)
+			}
+		}
+
+		return fault
+	}
+
+	return nil
+}
+
+// authenticated checks the bearer token and the company in the path before calling handler.
+// Handlers run with mu held.
+func (s *Server) authenticated(handler func(http.ResponseWriter, *http.Request, *company)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		expiry, valid := s.tokens[token]
+		if !found || !valid || time.Now().After(expiry) {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"detail": "Authentication credentials were not provided or are invalid."})
+
+			return
+		}
+
+		var owner *company
+
+		if companyParam := r.PathValue("company"); companyParam != "" {
+			companyID, err := strconv.Atoi(companyParam)
+			owner = s.companies[companyID]
+
+			if err != nil || owner == nil {
+				writeJSON(w, http.StatusForbidden, map[string]string{"detail": "You do not have permission to perform this action."})
+
+				return
+			}
+		}
+
+		if env := r.PathValue("env"); env != "" && env != "autoprovisioning" && env != "staging" {
+			writeNotFound(w)
+
+			return
+		}
+
+		handler(w, r, owner)
+	}
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"detail": err.Error()})
+
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "client_credentials" ||
+		r.PostForm.Get("client_id") != ClientID || r.PostForm.Get("client_secret") != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"detail": "Invalid client credentials."})
+
+		return
+	}
+
+	s.mu.Lock()
+	ttl := s.tokenTTL
+	s.mu.Unlock()
+
+	token := s.IssueToken(ttl)
+
+	writeJSON(w, http.StatusOK, teclient.TokenStruct{
+		Token:     token,
+		ExpiresIn: int(ttl.Seconds()),
+		TokenType: "Bearer",
+		Scope:     "read write",
+	})
+}
+
+func (s *Server) handleIPRanges(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.ipRanges)
+}
+
+// newID returns a new unique object ID. The caller must hold mu.
+func (s *Server) newID() int {
+	s.nextID++
+
+	return s.nextID
+}
+
+// pathID parses the {id} wildcard, writing a 404 if it is not a number.
+func pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeNotFound(w)
+
+		return 0, false
+	}
+
+	return id, true
+}
+
+// pathEnv returns the API environment of the {env} wildcard.
+func pathEnv(r *http.Request) teclient.APIEnvironment {
+	if r.PathValue("env") == "staging" {
+		return teclient.StagingEnv
+	}
+
+	return teclient.ProdEnv
+}
+
+// decodeBody decodes the JSON request body into v, writing a 400 on failure.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"detail": "JSON parse error - " + err.Error()})
+
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeJSON(w, http.StatusNotFound, map[string]string{"detail": "Not found."})
+}
+
+// writeFieldError answers with a validation error of a single field, like the API does.
+func writeFieldError(w http.ResponseWriter, field string, msg string) {
+	writeJSON(w, http.StatusBadRequest, map[string][]string{field: {msg}})
+}
+
+// now returns the current date formatted like the API does, in ISO 8601 with microseconds.
+func now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000000Z07:00")
+}
@@ -0,0 +1,183 @@
+package testserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// FailSiteVerification makes the verification of domain fail until PassSiteVerification is called.
+func (s *Server) FailSiteVerification(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unverifiedDomains[domain] = true
+}
+
+// PassSiteVerification makes the verification of domain succeed again.
+func (s *Server) PassSiteVerification(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.unverifiedDomains, domain)
+}
+
+// SetForeignDomain marks domain as owned by a company the test credentials have no access to.
+func (s *Server) SetForeignDomain(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.foreignDomains[domain] = true
+}
+
+// RecreateSite disables the site with id and creates an active one for the same domain with a new ID,
+// like a site deleted and added again from the dashboard. It returns the new ID, or 0 if the site doesn't exist.
+func (s *Server) RecreateSite(id int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.companies {
+		site, found := c.sites[id]
+		if !found {
+			continue
+		}
+
+		site.Active = false
+
+		recreated := &teclient.SiteAPIModel{
+			ID:      s.newID(),
+			Company: c.id,
+			URL:     site.URL,
+			Active:  true,
+		}
+		c.sites[recreated.ID] = recreated
+
+		return recreated.ID
+	}
+
+	return 0
+}
+
+// VerificationString returns the string the API asks to publish to verify domain.
+func VerificationString(domain string) string {
+	sum := sha256.Sum256([]byte("tcdn-verification:" + domain))
+
+	return "tcdn-site-verification=" + hex.EncodeToString(sum[:12])
+}
+
+func (*Server) handleSiteVerification(w http.ResponseWriter, r *http.Request, _ *company) {
+	data := teclient.SiteVerifyStringAPIModelRequest{}
+	if !decodeBody(w, r, &data) {
+		return
+	}
+
+	if data.Domain == "" {
+		writeFieldError(w, "domain", "This field may not be blank.")
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, teclient.SiteVerifyStringAPIModelResponse{Txt: VerificationString(data.Domain)})
+}
+
+func (*Server) handleListSites(w http.ResponseWriter, _ *http.Request, c *company) {
+	sites := make([]*teclient.SiteAPIModel, 0, len(c.sites))
+	for _, site := range c.sites {
+		sites = append(sites, site)
+	}
+
+	slices.SortFunc(sites, func(a, b *teclient.SiteAPIModel) int { return a.ID - b.ID })
+
+	writeJSON(w, http.StatusOK, sites)
+}
+
+func (*Server) handleGetSite(w http.ResponseWriter, r *http.Request, c *company) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	site, found := c.sites[id]
+	if !found {
+		writeNotFound(w)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, site)
+}
+
+func (s *Server) handleCreateSite(w http.ResponseWriter, r *http.Request, c *company) {
+	data := teclient.SiteNewAPIModel{}
+	if !decodeBody(w, r, &data) {
+		return
+	}
+
+	if data.URL == "" {
+		writeFieldError(w, "url", "This field may not be blank.")
+
+		return
+	}
+
+	if s.foreignDomains[data.URL] {
+		writeFieldError(w, "url", "Site ownership denied, the site belongs to another company.")
+
+		return
+	}
+
+	if s.unverifiedDomains[data.URL] {
+		writeJSON(w, http.StatusForbidden, map[string]string{"detail": "Site verification failed for " + data.URL + "."})
+
+		return
+	}
+
+	for _, site := range c.sites {
+		if site.URL != data.URL {
+			continue
+		}
+
+		if site.Active {
+			writeFieldError(w, "url", "site with this url already exists.")
+
+			return
+		}
+
+		// Disabled sites are activated again
+		site.Active = true
+		writeJSON(w, http.StatusCreated, site)
+
+		return
+	}
+
+	site := &teclient.SiteAPIModel{
+		ID:      s.newID(),
+		Company: c.id,
+		URL:     data.URL,
+		Active:  true,
+	}
+	c.sites[site.ID] = site
+
+	writeJSON(w, http.StatusOK, site)
+}
+
+func (*Server) handleDeleteSite(w http.ResponseWriter, r *http.Request, c *company) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	site, found := c.sites[id]
+	if !found {
+		writeNotFound(w)
+
+		return
+	}
+
+	// Sites are disabled, not deleted
+	site.Active = false
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,159 @@
+package testserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/teclient"
+)
+
+// CompilationErrorMarker makes the fake VCL compiler reject any configuration containing it.
+const CompilationErrorMarker = "COMPILATION_ERROR"
+
+// testUser is the creator of every configuration uploaded to the fake API.
+var testUser = teclient.VCLConfCreator{
+	ID:        1,
+	Email:     "acctest@example.com",
+	FirstName: "Acceptance",
+	LastName:  "Tests",
+	Username:  "acctest",
+}
+
+// SetUploadDate overrides the upload date of the configuration with id, like one returned in another format by the API.
+// It returns false if the configuration doesn't exist.
+func (s *Server) SetUploadDate(id int, date string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.companies {
+		for _, configs := range c.configs {
+			for _, conf := range configs {
+				if conf.ID == id {
+					conf.UploadDate = date
+
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// activeConfig returns the configuration in production, the last one uploaded.
+func (c *company) activeConfig(env teclient.APIEnvironment) *teclient.VCLConfAPIModel {
+	configs := c.configs[env]
+	if len(configs) == 0 {
+		return nil
+	}
+
+	return configs[len(configs)-1]
+}
+
+// handleListConfigs returns a page of configurations, newest first.
+// Like the API, pages are numbered from 1 and asking beyond the last one is a 404.
+func (*Server) handleListConfigs(w http.ResponseWriter, r *http.Request, c *company) {
+	page := 1
+
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		var err error
+
+		page, err = strconv.Atoi(offset)
+		if err != nil || page < 1 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"detail": "Invalid page."})
+
+			return
+		}
+	}
+
+	configs := c.configs[pathEnv(r)]
+	start := (page - 1) * ConfigPageSize
+
+	if start > 0 && start >= len(configs) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"detail": "Invalid page."})
+
+		return
+	}
+
+	result := []*teclient.VCLConfAPIModel{}
+
+	for i := len(configs) - 1 - start; i >= 0 && len(result) < ConfigPageSize; i-- {
+		result = append(result, configs[i])
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (*Server) handleGetConfig(w http.ResponseWriter, r *http.Request, c *company) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	for _, conf := range c.configs[pathEnv(r)] {
+		if conf.ID == id {
+			writeJSON(w, http.StatusOK, conf)
+
+			return
+		}
+	}
+
+	writeNotFound(w)
+}
+
+// handleCreateConfig compiles and deploys a new configuration right away.
+func (s *Server) handleCreateConfig(w http.ResponseWriter, r *http.Request, c *company) {
+	data := teclient.NewVCLConfAPIModel{}
+	if !decodeBody(w, r, &data) {
+		return
+	}
+
+	if strings.TrimSpace(data.VCLCode) == "" {
+		writeFieldError(w, "config_body", "This field may not be blank.")
+
+		return
+	}
+
+	if msg := compileVCL(data.VCLCode); msg != "" {
+		// The compiler output is returned as a bare JSON string
+		writeJSON(w, http.StatusBadRequest, msg)
+
+		return
+	}
+
+	env := pathEnv(r)
+	if active := c.activeConfig(env); active != nil {
+		active.Active = false
+	}
+
+	uploaded := now()
+	conf := &teclient.VCLConfAPIModel{
+		ID:             s.newID(),
+		Company:        c.id,
+		VCLCode:        data.VCLCode,
+		UploadDate:     uploaded,
+		ProductionDate: uploaded,
+		Validated:      true,
+		Active:         true,
+		Deployed:       true,
+		CreatorUser:    testUser,
+		Comment:        data.Comment,
+	}
+	c.configs[env] = append(c.configs[env], conf)
+
+	writeJSON(w, http.StatusCreated, conf)
+}
+
+// compileVCL returns the compiler error of code, or an empty string if it compiles.
+func compileVCL(code string) string {
+	if strings.Contains(code, CompilationErrorMarker) {
+		return "Message from VCC-compiler:\nSymbol not found: '" + CompilationErrorMarker + "'\nRunning VCC-compiler failed, exited with 2"
+	}
+
+	if strings.Count(code, "{") != strings.Count(code, "}") {
+		return "Message from VCC-compiler:\nUnbalanced braces in the configuration\nRunning VCC-compiler failed, exited with 2"
+	}
+
+	return ""
+}
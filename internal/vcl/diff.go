@@ -0,0 +1,323 @@
+package vcl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// maxDiffChanges is the number of deleted and inserted lines past which Diff summarizes the changes,
+	// it bounds the memory of the diff to about maxDiffChanges² integers.
+	maxDiffChanges = 500
+	// maxDiffLines is the number of lines of the hunks past which Diff summarizes the changes.
+	maxDiffLines = 1000
+)
+
+// editKind is the kind of an edit of the line based diff.
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+// edit is a line kept, deleted from the old code or inserted from the new code.
+type edit struct {
+	kind editKind
+	// oldLine and newLine are the indexes of the line in the old and new code, before the edit.
+	oldLine int
+	newLine int
+	text    string
+}
+
+// Diff returns a line based unified diff from oldCode to newCode, with contextLines unchanged lines
+// around each change, labeling the files with oldName and newName. It returns "" when both are equal.
+// A diff with too many changes or lines is replaced by the number of lines changed.
+func Diff(oldName, newName, oldCode, newCode string, contextLines int) string {
+	if oldCode == newCode {
+		return ""
+	}
+
+	contextLines = max(contextLines, 0)
+
+	oldLines, newLines := splitLines(oldCode), splitLines(newCode)
+
+	var output strings.Builder
+
+	fmt.Fprintf(&output, "--- %s\n+++ %s\n", oldName, newName)
+
+	edits, ok := diffLines(oldLines, newLines, maxDiffChanges)
+	if !ok {
+		writeSummary(&output, oldLines, newLines)
+
+		return output.String()
+	}
+
+	// The hunks, as ranges of edits
+	var hunks [][2]int
+
+	lines := 0
+
+	for start := 0; start < len(edits); {
+		// Skip to the next change
+		if edits[start].kind == editEqual {
+			start++
+
+			continue
+		}
+
+		// The hunk extends while the unchanged lines between two changes are within the context of both
+		end := start
+		for next := start; next < len(edits); next++ {
+			if edits[next].kind == editEqual {
+				continue
+			}
+
+			if next-end > 2*contextLines {
+				break
+			}
+
+			end = next + 1
+		}
+
+		hunk := [2]int{max(start-contextLines, 0), min(end+contextLines, len(edits))}
+		hunks = append(hunks, hunk)
+		lines += hunk[1] - hunk[0]
+
+		start = end
+	}
+
+	if lines > maxDiffLines {
+		writeSummary(&output, oldLines, newLines)
+
+		return output.String()
+	}
+
+	for _, hunk := range hunks {
+		writeHunk(&output, edits[hunk[0]:hunk[1]])
+	}
+
+	return output.String()
+}
+
+// writeSummary writes the number of lines changed instead of the hunks of a diff too large to show,
+// counting the lines between the common prefix and suffix of both codes.
+func writeSummary(output *strings.Builder, oldLines, newLines []string) {
+	prefix, suffix := commonLines(oldLines, newLines)
+
+	changed := max(len(oldLines), len(newLines)) - prefix - suffix
+
+	lines := "lines"
+	if changed == 1 {
+		lines = "line"
+	}
+
+	fmt.Fprintf(output, "%d %s changed from line %d, too many to show the differences\n", changed, lines, prefix+1)
+}
+
+// writeHunk writes the edits with their header, i.e: @@ -1,3 +1,4 @@.
+func writeHunk(output *strings.Builder, edits []edit) {
+	oldStart, newStart := edits[0].oldLine+1, edits[0].newLine+1
+
+	var oldCount, newCount int
+
+	for _, e := range edits {
+		switch e.kind {
+		case editEqual:
+			oldCount++
+			newCount++
+		case editDelete:
+			oldCount++
+		case editInsert:
+			newCount++
+		}
+	}
+
+	// An empty range starts at the line before it, as in diff -u
+	if oldCount == 0 {
+		oldStart--
+	}
+
+	if newCount == 0 {
+		newStart--
+	}
+
+	fmt.Fprintf(output, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+
+	for _, e := range edits {
+		switch e.kind {
+		case editEqual:
+			output.WriteString(" ")
+		case editDelete:
+			output.WriteString("-")
+		case editInsert:
+			output.WriteString("+")
+		}
+
+		output.WriteString(e.text)
+		output.WriteString("\n")
+	}
+}
+
+// hunkRange formats the range of a hunk header, the count is omitted when it's 1.
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits code into lines, without the ending newline.
+func splitLines(code string) []string {
+	if code == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+}
+
+// commonLines returns the number of lines at the start and at the end that both codes have in common.
+func commonLines(oldLines, newLines []string) (int, int) {
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	return prefix, suffix
+}
+
+// diffLines returns the shortest edit script from oldLines to newLines, or false when it has more than
+// maxChanges deleted and inserted lines. Only the lines between the common prefix and suffix are compared.
+func diffLines(oldLines, newLines []string, maxChanges int) ([]edit, bool) {
+	prefix, suffix := commonLines(oldLines, newLines)
+
+	changed, ok := myersDiff(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix], maxChanges)
+	if !ok {
+		return nil, false
+	}
+
+	edits := make([]edit, 0, prefix+len(changed)+suffix)
+
+	for n := range prefix {
+		edits = append(edits, edit{editEqual, n, n, oldLines[n]})
+	}
+
+	for _, e := range changed {
+		e.oldLine += prefix
+		e.newLine += prefix
+		edits = append(edits, e)
+	}
+
+	for n := range suffix {
+		oldLine, newLine := len(oldLines)-suffix+n, len(newLines)-suffix+n
+		edits = append(edits, edit{editEqual, oldLine, newLine, oldLines[oldLine]})
+	}
+
+	return edits, true
+}
+
+// myersDiff returns the shortest edit script from oldLines to newLines with the Myers algorithm,
+// or false when it has more than maxChanges deleted and inserted lines.
+func myersDiff(oldLines, newLines []string, maxChanges int) ([]edit, bool) {
+	n, m := len(oldLines), len(newLines)
+	offset := n + m
+
+	// trace keeps, before each number of changes d, the furthest reaching x of the diagonals k from -d+1 to d-1,
+	// the only ones the walk back reads, at trace[d][k+d-1]
+	var trace [][]int
+
+	furthest := make([]int, 2*offset+2)
+
+	found := false
+
+search:
+	for d := 0; d <= min(offset, maxChanges); d++ {
+		var live []int
+		if d > 0 {
+			live = slices.Clone(furthest[offset-d+1 : offset+d])
+		}
+
+		trace = append(trace, live)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && furthest[offset+k-1] < furthest[offset+k+1]) {
+				x = furthest[offset+k+1]
+			} else {
+				x = furthest[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && oldLines[x] == newLines[y] {
+				x++
+				y++
+			}
+
+			furthest[offset+k] = x
+
+			if x >= n && y >= m {
+				found = true
+
+				break search
+			}
+		}
+	}
+
+	if !found {
+		return nil, false
+	}
+
+	// Walk the trace backwards from the end of both codes, trace[d] is the state before the change d
+	var edits []edit
+
+	x, y := n, m
+
+	for d := len(trace) - 1; d > 0; d-- {
+		previous := func(k int) int { return trace[d][k+d-1] }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && previous(k-1) < previous(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := previous(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{editEqual, x, y, oldLines[x]})
+		}
+
+		if x == prevX {
+			y--
+			edits = append(edits, edit{editInsert, x, y, newLines[y]})
+		} else {
+			x--
+			edits = append(edits, edit{editDelete, x, y, oldLines[x]})
+		}
+	}
+
+	// The first snake, without changes, starts at the beginning of both codes
+	for x > 0 {
+		x--
+		y--
+		edits = append(edits, edit{editEqual, x, y, oldLines[x]})
+	}
+
+	slices.Reverse(edits)
+
+	return edits, true
+}
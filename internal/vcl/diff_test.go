@@ -0,0 +1,111 @@
+package vcl_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/vcl"
+)
+
+func TestDiff(t *testing.T) {
+	code := "sub vcl_recv {\n  set req.http.A = \"a\";\n  set req.http.B = \"b\";\n  set req.http.C = \"c\";\n  set req.http.D = \"d\";\n  set req.http.E = \"e\";\n  set req.http.F = \"f\";\n  set req.http.G = \"g\";\n}\n"
+
+	tests := map[string]struct {
+		newCode      string
+		contextLines int
+		expected     string
+	}{
+		"equal": {
+			newCode:      code,
+			contextLines: 3,
+			expected:     "",
+		},
+		"changed line": {
+			newCode:      "sub vcl_recv {\n  set req.http.A = \"a\";\n  set req.http.B = \"b\";\n  set req.http.C = \"c\";\n  set req.http.D = \"changed\";\n  set req.http.E = \"e\";\n  set req.http.F = \"f\";\n  set req.http.G = \"g\";\n}\n",
+			contextLines: 1,
+			expected:     "--- current\n+++ planned\n@@ -4,3 +4,3 @@\n   set req.http.C = \"c\";\n-  set req.http.D = \"d\";\n+  set req.http.D = \"changed\";\n   set req.http.E = \"e\";\n",
+		},
+		"separate hunks": {
+			newCode:      "sub vcl_recv {\n  set req.http.A = \"changed\";\n  set req.http.B = \"b\";\n  set req.http.C = \"c\";\n  set req.http.D = \"d\";\n  set req.http.E = \"e\";\n  set req.http.F = \"f\";\n  set req.http.G = \"g\";\n  return (pass);\n}\n",
+			contextLines: 1,
+			expected:     "--- current\n+++ planned\n@@ -1,3 +1,3 @@\n sub vcl_recv {\n-  set req.http.A = \"a\";\n+  set req.http.A = \"changed\";\n   set req.http.B = \"b\";\n@@ -8,2 +8,3 @@\n   set req.http.G = \"g\";\n+  return (pass);\n }\n",
+		},
+		"merged hunks": {
+			newCode:      "sub vcl_recv {\n  set req.http.A = \"a\";\n  set req.http.C = \"c\";\n  set req.http.D = \"d\";\n  set req.http.E = \"e\";\n  set req.http.G = \"g\";\n}\n",
+			contextLines: 2,
+			expected:     "--- current\n+++ planned\n@@ -1,9 +1,7 @@\n sub vcl_recv {\n   set req.http.A = \"a\";\n-  set req.http.B = \"b\";\n   set req.http.C = \"c\";\n   set req.http.D = \"d\";\n   set req.http.E = \"e\";\n-  set req.http.F = \"f\";\n   set req.http.G = \"g\";\n }\n",
+		},
+		"no context": {
+			newCode:      "sub vcl_recv {\n  set req.http.A = \"a\";\n  set req.http.B = \"b\";\n  set req.http.C = \"c\";\n  set req.http.D = \"d\";\n  set req.http.E = \"e\";\n  set req.http.F = \"f\";\n  set req.http.G = \"g\";\n}\n\nsub vcl_hash {\n}\n",
+			contextLines: 0,
+			expected:     "--- current\n+++ planned\n@@ -9,0 +10,3 @@\n+\n+sub vcl_hash {\n+}\n",
+		},
+		"removed lines": {
+			newCode:      "sub vcl_recv {\n}\n",
+			contextLines: 3,
+			expected:     "--- current\n+++ planned\n@@ -1,9 +1,2 @@\n sub vcl_recv {\n-  set req.http.A = \"a\";\n-  set req.http.B = \"b\";\n-  set req.http.C = \"c\";\n-  set req.http.D = \"d\";\n-  set req.http.E = \"e\";\n-  set req.http.F = \"f\";\n-  set req.http.G = \"g\";\n }\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := vcl.Diff("current", "planned", code, test.newCode, test.contextLines); diff != test.expected {
+				t.Errorf("Diff returned:\n%s\nexpected:\n%s", diff, test.expected)
+			}
+		})
+	}
+}
+
+func TestDiff_emptyCode(t *testing.T) {
+	expected := "--- current\n+++ planned\n@@ -0,0 +1,2 @@\n+sub vcl_recv {\n+}\n"
+
+	if diff := vcl.Diff("current", "planned", "", "sub vcl_recv {\n}", 3); diff != expected {
+		t.Errorf("Diff returned:\n%s\nexpected:\n%s", diff, expected)
+	}
+}
+
+// numberedCode returns count lines with format, numbered from 1.
+func numberedCode(format string, count int) string {
+	var code strings.Builder
+	for n := 1; n <= count; n++ {
+		fmt.Fprintf(&code, format+"\n", n)
+	}
+
+	return code.String()
+}
+
+func TestDiff_largeCode(t *testing.T) {
+	code := numberedCode("set req.http.X-Line = \"%d\";", 4000)
+
+	tests := map[string]struct {
+		newCode      string
+		contextLines int
+		expected     string
+	}{
+		"single change": {
+			newCode:      strings.Replace(code, "\"2000\"", "\"changed\"", 1),
+			contextLines: 1,
+			expected: "--- current\n+++ planned\n@@ -1999,3 +1999,3 @@\n set req.http.X-Line = \"1999\";\n" +
+				"-set req.http.X-Line = \"2000\";\n+set req.http.X-Line = \"changed\";\n set req.http.X-Line = \"2001\";\n",
+		},
+		"too many changes": {
+			newCode:      numberedCode("set req.http.X-Line = \"%d\";", 10) + numberedCode("set req.http.X-Other = \"%d\";", 3990),
+			contextLines: 3,
+			expected:     "--- current\n+++ planned\n3990 lines changed from line 11, too many to show the differences\n",
+		},
+		"too many lines": {
+			newCode:      strings.Replace(code, "\"2000\"", "\"changed\"", 1),
+			contextLines: 1000,
+			expected:     "--- current\n+++ planned\n1 line changed from line 2000, too many to show the differences\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := vcl.Diff("current", "planned", code, test.newCode, test.contextLines); diff != test.expected {
+				t.Errorf("Diff returned:\n%s\nexpected:\n%s", diff, test.expected)
+			}
+		})
+	}
+}
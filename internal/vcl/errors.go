@@ -0,0 +1,43 @@
+package vcl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pos is a position in the VCL code, lines and columns start at 1.
+type Pos struct {
+	Line   int
+	Column int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// Error is a syntax error found in VCL code.
+type Error struct {
+	Pos     Pos
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Message
+}
+
+// Errors are the syntax errors of VCL code, in the order they were found.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// quote returns text between single quotes for the error messages.
+func quote(text string) string {
+	return "'" + text + "'"
+}
@@ -0,0 +1,237 @@
+package vcl
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenKind is the kind of a token of VCL code.
+type TokenKind int
+
+const (
+	// EOF ends the tokens of the code.
+	EOF TokenKind = iota
+	// Ident is a name, i.e: "req.http.X-Forwarded-For", "vcl_recv" or "c300_origin.backend".
+	Ident
+	// Number is an integer, a real or a duration, i.e: "200", "0.5" or "10s".
+	Number
+	// String is a quoted string, a long string {"..."} or """...""".
+	String
+	// Punct is an operator or a punctuation mark, i.e: "==", "{" or ";".
+	Punct
+	// CBlock is inline C code between C{ and }C.
+	CBlock
+)
+
+// Token is a token of VCL code, the comments are not kept.
+type Token struct {
+	Kind TokenKind
+	Text string
+	Pos  Pos
+}
+
+// puncts are the operators and punctuation marks, the longest ones first.
+var puncts = []string{
+	"==", "!=", "!~", "<=", ">=", "&&", "||", "+=", "-=", "*=", "/=",
+	"~", "<", ">", "!", "=", "+", "-", "*", "/", "%",
+	"{", "}", "(", ")", ";", ",", ".",
+}
+
+type lexer struct {
+	code   string
+	offset int
+	pos    Pos
+	tokens []Token
+	errs   Errors
+}
+
+// Lex splits code into tokens, skipping the comments. The last token is always EOF.
+func Lex(code string) ([]Token, Errors) {
+	l := &lexer{code: code, pos: Pos{Line: 1, Column: 1}}
+	l.run()
+
+	return l.tokens, l.errs
+}
+
+func (l *lexer) run() {
+	for {
+		l.skipSpaceAndComments()
+
+		if l.offset >= len(l.code) {
+			l.tokens = append(l.tokens, Token{Kind: EOF, Pos: l.pos})
+
+			return
+		}
+
+		start := l.pos
+		rest := l.code[l.offset:]
+
+		switch c := rest[0]; {
+		case strings.HasPrefix(rest, "C{"):
+			l.delimited(start, CBlock, "C{", "}C", "inline C code")
+		case strings.HasPrefix(rest, `"""`):
+			l.delimited(start, String, `"""`, `"""`, "string")
+		case strings.HasPrefix(rest, `{"`):
+			l.delimited(start, String, `{"`, `"}`, "string")
+		case c == '"':
+			l.quoted(start)
+		case isIdentStart(c):
+			l.emit(start, Ident, l.span(isIdentChar))
+		case isDigit(c):
+			l.emit(start, Number, l.number())
+		default:
+			l.punct(start, rest)
+		}
+	}
+}
+
+func (l *lexer) skipSpaceAndComments() {
+	for l.offset < len(l.code) {
+		rest := l.code[l.offset:]
+
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r' || rest[0] == '\n':
+			l.advance(1)
+		case rest[0] == '#' || strings.HasPrefix(rest, "//"):
+			l.advance(lineLength(rest))
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				l.errs = append(l.errs, &Error{Pos: l.pos, Message: "Unterminated comment, '/*' is never closed with '*/'"})
+				l.advance(len(rest))
+
+				return
+			}
+
+			l.advance(end + 4)
+		default:
+			return
+		}
+	}
+}
+
+// delimited lexes a token between open and close, which may span several lines.
+func (l *lexer) delimited(start Pos, kind TokenKind, open string, closing string, what string) {
+	rest := l.code[l.offset:]
+
+	end := strings.Index(rest[len(open):], closing)
+	if end < 0 {
+		l.errs = append(l.errs, &Error{Pos: start, Message: "Unterminated " + what + ", '" + open + "' is never closed with '" + closing + "'"})
+		l.advance(len(rest))
+
+		return
+	}
+
+	l.emit(start, kind, rest[:len(open)+end+len(closing)])
+}
+
+// quoted lexes a "string", it cannot span several lines.
+func (l *lexer) quoted(start Pos) {
+	rest := l.code[l.offset:]
+
+	end := strings.IndexAny(rest[1:], "\"\n")
+	if end < 0 || rest[1+end] == '\n' {
+		l.errs = append(l.errs, &Error{Pos: start, Message: "Unterminated string, a '\"' string must be closed on the same line, use {\"...\"} for strings spanning several lines"})
+		l.advance(lineLength(rest))
+
+		return
+	}
+
+	l.emit(start, String, rest[:end+2])
+}
+
+// number returns the number at the offset, with its unit if any: 10s, 1.5h, 512KB.
+func (l *lexer) number() string {
+	rest := l.code[l.offset:]
+	n := 0
+
+	for n < len(rest) && isDigit(rest[n]) {
+		n++
+	}
+
+	if n+1 < len(rest) && rest[n] == '.' && isDigit(rest[n+1]) {
+		n++
+
+		for n < len(rest) && isDigit(rest[n]) {
+			n++
+		}
+	}
+
+	for n < len(rest) && isLetter(rest[n]) {
+		n++
+	}
+
+	return rest[:n]
+}
+
+func (l *lexer) punct(start Pos, rest string) {
+	for _, punct := range puncts {
+		if strings.HasPrefix(rest, punct) {
+			l.emit(start, Punct, punct)
+
+			return
+		}
+	}
+
+	r, size := utf8.DecodeRuneInString(rest)
+	l.errs = append(l.errs, &Error{Pos: start, Message: "Unexpected character " + quote(string(r))})
+	l.advance(size)
+}
+
+// span returns the longest text at the offset made of the bytes accepted by valid.
+func (l *lexer) span(valid func(byte) bool) string {
+	rest := l.code[l.offset:]
+	n := 0
+
+	for n < len(rest) && valid(rest[n]) {
+		n++
+	}
+
+	return rest[:n]
+}
+
+func (l *lexer) emit(start Pos, kind TokenKind, text string) {
+	l.tokens = append(l.tokens, Token{Kind: kind, Text: text, Pos: start})
+	l.advance(len(text))
+}
+
+// advance moves the offset n bytes forward, keeping track of the line and the column.
+func (l *lexer) advance(n int) {
+	for _, r := range l.code[l.offset : l.offset+n] {
+		if r == '\n' {
+			l.pos.Line++
+			l.pos.Column = 1
+		} else {
+			l.pos.Column++
+		}
+	}
+
+	l.offset += n
+}
+
+// lineLength returns the length of the first line of s, without the newline.
+func lineLength(s string) int {
+	if n := strings.IndexByte(s, '\n'); n >= 0 {
+		return n
+	}
+
+	return len(s)
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return isLetter(c) || c == '_'
+}
+
+// isIdentChar accepts the dots of the fields, the dashes of the headers and the colon of their sub-fields,
+// i.e: req.http.X-Forwarded-For or req.http.Cookie:session.
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.' || c == '-' || c == ':'
+}
@@ -0,0 +1,563 @@
+package vcl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BuiltinSubroutines are the subroutines called by Varnish, the only ones that may be named vcl_*.
+var BuiltinSubroutines = []string{
+	"vcl_init",
+	"vcl_fini",
+	"vcl_recv",
+	"vcl_pipe",
+	"vcl_pass",
+	"vcl_hash",
+	"vcl_purge",
+	"vcl_hit",
+	"vcl_miss",
+	"vcl_deliver",
+	"vcl_synth",
+	"vcl_backend_fetch",
+	"vcl_backend_response",
+	"vcl_backend_error",
+}
+
+// binaryOperators may join two values of an expression.
+var binaryOperators = []string{"==", "!=", "~", "!~", "<", ">", "<=", ">=", "&&", "||", "+", "-", "*", "/", "%"}
+
+// setOperators may follow the variable of a set statement.
+var setOperators = []string{"=", "+=", "-=", "*=", "/="}
+
+type parser struct {
+	tokens []Token
+	n      int
+	errs   Errors
+}
+
+// Validate checks the syntax of VCL code: the braces, parentheses and strings are balanced,
+// the declarations are well formed, no unknown subroutine is named vcl_*, and the set, unset,
+// call, return and if statements are complete.
+//
+// It doesn't compile the code: the names of variables, functions and backends are not checked,
+// and the statements it doesn't know are skipped up to their ';'.
+func Validate(code string) Errors {
+	tokens, errs := Lex(code)
+	if len(errs) > 0 {
+		// The tokens after a lexing error are unreliable
+		return errs
+	}
+
+	p := &parser{tokens: tokens}
+	p.parseFile()
+
+	return p.errs
+}
+
+func (p *parser) parseFile() {
+	for !p.at(EOF, "") {
+		tok := p.peek()
+		errs := len(p.errs)
+
+		switch {
+		case tok.Kind == CBlock, p.at(Punct, ";"):
+			// Varnish ignores a ';' after a declaration, i.e: sub vcl_recv { };
+			p.next()
+		case p.at(Ident, "vcl"):
+			p.next()
+			_ = p.expectKind(Number, "the VCL version after 'vcl'") && p.expectEnd("vcl")
+		case p.at(Ident, "import"):
+			p.next()
+
+			ok := p.expectKind(Ident, "the name of the module after 'import'")
+			if ok && p.accept(Ident, "from") {
+				ok = p.expectKind(String, "the path of the module after 'from'")
+			}
+
+			_ = ok && p.expectEnd("import")
+		case p.at(Ident, "include"):
+			p.next()
+			_ = p.expectKind(String, "the file to include after 'include'") && p.expectEnd("include")
+		case p.at(Ident, "sub"):
+			p.parseSub()
+		case p.at(Ident, "backend"), p.at(Ident, "probe"):
+			p.next()
+
+			if !p.expectKind(Ident, "the name of the "+tok.Text+" after "+quote(tok.Text)) {
+				break
+			}
+
+			// A backend that never answers, i.e: backend default none;
+			if tok.Text == "backend" && p.accept(Ident, "none") {
+				_ = p.expectEnd("backend")
+
+				break
+			}
+
+			p.parseProperties(tok.Text)
+		case p.at(Ident, "acl"):
+			p.next()
+
+			if p.expectKind(Ident, "the name of the ACL after 'acl'") {
+				p.parseACL()
+			}
+		case p.at(Punct, "}"):
+			p.errorf(tok.Pos, "Unbalanced braces, this '}' has no matching '{'")
+			p.next()
+		default:
+			p.errorf(tok.Pos, "Unexpected %s, expected a declaration: vcl, import, include, backend, probe, acl or sub", describe(tok))
+			p.next()
+			p.skipStatement()
+		}
+
+		// Recover from an error in a declaration at the next one
+		if len(p.errs) > errs && !p.atDeclaration() && !p.at(Punct, "}") {
+			p.skipStatement()
+		}
+	}
+}
+
+func (p *parser) parseSub() {
+	p.next()
+
+	name := p.peek()
+	if !p.expectKind(Ident, "the name of the subroutine after 'sub'") {
+		return
+	}
+
+	if strings.HasPrefix(name.Text, "vcl_") && !slices.Contains(BuiltinSubroutines, name.Text) {
+		p.errorf(name.Pos, "Unknown subroutine %s, the subroutines named vcl_* are called by Varnish and must be one of: %s",
+			quote(name.Text), strings.Join(BuiltinSubroutines, ", "))
+	}
+
+	p.parseBlock()
+}
+
+// parseBlock parses a block of statements between braces.
+func (p *parser) parseBlock() bool {
+	open := p.peek()
+	if !p.expect("{", "to open the block") {
+		return false
+	}
+
+	for !p.accept(Punct, "}") {
+		if p.at(EOF, "") {
+			p.errorf(open.Pos, "Unbalanced braces, this '{' is never closed with '}'")
+
+			return false
+		}
+
+		start := p.n
+		p.parseStatement()
+
+		// A stray token that no statement can start with
+		if p.n == start {
+			p.next()
+		}
+	}
+
+	return true
+}
+
+func (p *parser) parseStatement() {
+	tok := p.peek()
+
+	var ok bool
+
+	switch {
+	case p.at(Punct, ";"), tok.Kind == CBlock:
+		p.next()
+
+		return
+	case p.at(Punct, "{"):
+		p.parseBlock()
+
+		return
+	case p.at(Ident, "if"):
+		p.parseIf()
+
+		return
+	case p.at(Ident, "else"), p.at(Ident, "elseif"), p.at(Ident, "elsif"), p.at(Ident, "elif"):
+		p.errorf(tok.Pos, "%s without a previous 'if'", quote(tok.Text))
+		p.next()
+	case p.at(Ident, "set"):
+		ok = p.parseSet()
+	case p.at(Ident, "unset"):
+		p.next()
+		ok = p.expectKind(Ident, "the variable to unset after 'unset'") && p.expectEnd("unset")
+	case p.at(Ident, "call"):
+		p.next()
+		ok = p.expectKind(Ident, "the name of the subroutine after 'call'") && p.expectEnd("call")
+	case p.at(Ident, "return"):
+		ok = p.parseReturn()
+	case p.at(Ident, "synthetic"):
+		p.next()
+		ok = p.expect("(", "after 'synthetic'") && p.parseExpr("the body of 'synthetic'") &&
+			p.expect(")", "to close 'synthetic'") && p.expectEnd("synthetic")
+	case p.at(Ident, "new"):
+		p.next()
+		ok = p.expectKind(Ident, "the name of the object after 'new'") && p.expect("=", "after the name of the object") &&
+			p.parseExpr("the constructor of the object") && p.expectEnd("new")
+	case tok.Kind == Ident && p.peekAt(1).Kind == Punct && p.peekAt(1).Text == "(":
+		// A function call, i.e: std.log("...");
+		ok = p.parseExpr("a function call") && p.expectEnd(tok.Text)
+	}
+
+	if !ok {
+		// Unknown statements are left to the compiler of the API
+		p.skipStatement()
+	}
+}
+
+func (p *parser) parseIf() {
+	p.next()
+
+	if !p.parseCondition("if") || !p.parseBlock() {
+		p.skipStatement()
+
+		return
+	}
+
+	for {
+		tok := p.peek()
+
+		switch {
+		case p.at(Ident, "elseif"), p.at(Ident, "elsif"), p.at(Ident, "elif"):
+			p.next()
+
+			if !p.parseCondition(tok.Text) || !p.parseBlock() {
+				p.skipStatement()
+
+				return
+			}
+		case p.at(Ident, "else"):
+			p.next()
+
+			if p.accept(Ident, "if") {
+				if !p.parseCondition("else if") || !p.parseBlock() {
+					p.skipStatement()
+
+					return
+				}
+
+				continue
+			}
+
+			if !p.parseBlock() {
+				p.skipStatement()
+			}
+
+			return
+		default:
+			return
+		}
+	}
+}
+
+// parseCondition parses the condition between parentheses of if, else if, etc.
+func (p *parser) parseCondition(statement string) bool {
+	return p.expect("(", "after "+quote(statement)) &&
+		p.parseExpr("the condition of "+quote(statement)) &&
+		p.expect(")", "to close the condition of "+quote(statement))
+}
+
+func (p *parser) parseSet() bool {
+	p.next()
+
+	if !p.expectKind(Ident, "the variable to set after 'set'") {
+		return false
+	}
+
+	tok := p.peek()
+	if tok.Kind != Punct || !slices.Contains(setOperators, tok.Text) {
+		p.errorf(tok.Pos, "Expected '=' after the variable to set, found %s", describe(tok))
+
+		return false
+	}
+
+	p.next()
+
+	return p.parseExpr("the value to set") && p.expectEnd("set")
+}
+
+func (p *parser) parseReturn() bool {
+	p.next()
+
+	if p.accept(Punct, "(") {
+		if !p.expectKind(Ident, "the action to return, i.e: return (pass);") {
+			return false
+		}
+
+		if p.at(Punct, "(") && !p.parseArguments() {
+			return false
+		}
+
+		if !p.expect(")", "to close 'return'") {
+			return false
+		}
+	}
+
+	return p.expectEnd("return")
+}
+
+// parseExpr parses an expression, what describes it in the errors.
+func (p *parser) parseExpr(what string) bool {
+	if !p.parseOperand(what) {
+		return false
+	}
+
+	for {
+		tok := p.peek()
+		if tok.Kind != Punct || !slices.Contains(binaryOperators, tok.Text) {
+			return true
+		}
+
+		p.next()
+
+		if !p.parseOperand("a value after " + quote(tok.Text)) {
+			return false
+		}
+	}
+}
+
+func (p *parser) parseOperand(what string) bool {
+	tok := p.peek()
+
+	switch {
+	case p.at(Punct, "!"), p.at(Punct, "-"), p.at(Punct, "+"):
+		p.next()
+
+		return p.parseOperand(what)
+	case p.at(Punct, "("):
+		p.next()
+
+		return p.parseExpr(what) && p.expect(")", "to close the parenthesis")
+	case tok.Kind == String:
+		// Consecutive strings are concatenated
+		for p.peek().Kind == String {
+			p.next()
+		}
+
+		return true
+	case tok.Kind == Number:
+		p.next()
+
+		return true
+	case tok.Kind == Ident:
+		p.next()
+
+		if p.at(Punct, "(") {
+			return p.parseArguments()
+		}
+
+		return true
+	}
+
+	p.errorf(tok.Pos, "Expected %s, found %s", what, describe(tok))
+
+	return false
+}
+
+// parseArguments parses the arguments of a function call, named ones included: f(a, b = 1).
+func (p *parser) parseArguments() bool {
+	p.next()
+
+	if p.accept(Punct, ")") {
+		return true
+	}
+
+	for {
+		if p.peek().Kind == Ident && p.peekAt(1).Kind == Punct && p.peekAt(1).Text == "=" {
+			p.next()
+			p.next()
+		}
+
+		if !p.parseExpr("an argument") {
+			return false
+		}
+
+		if p.accept(Punct, ")") {
+			return true
+		}
+
+		if !p.expect(",", "between the arguments") {
+			return false
+		}
+	}
+}
+
+// parseProperties parses the properties of a backend or a probe: { .name = value; ... }.
+func (p *parser) parseProperties(declaration string) {
+	open := p.peek()
+	if !p.expect("{", "to open the "+declaration) {
+		return
+	}
+
+	for !p.accept(Punct, "}") {
+		if p.at(EOF, "") {
+			p.errorf(open.Pos, "Unbalanced braces, this '{' is never closed with '}'")
+
+			return
+		}
+
+		ok := p.expect(".", "before the name of the property, i.e: .host") &&
+			p.expectKind(Ident, "the name of the property after '.'") &&
+			p.expect("=", "after the name of the property")
+
+		if ok && p.at(Punct, "{") {
+			// A probe defined inside a backend, the ';' after it is optional
+			p.parseProperties("probe")
+			p.accept(Punct, ";")
+
+			continue
+		}
+
+		if !ok || !p.parseExpr("the value of the property") || !p.expectEnd("property") {
+			p.skipStatement()
+		}
+	}
+}
+
+// parseACL parses the entries of an ACL, they are left to the compiler of the API.
+func (p *parser) parseACL() {
+	open := p.peek()
+	if !p.expect("{", "to open the ACL") {
+		return
+	}
+
+	for !p.accept(Punct, "}") {
+		if p.at(EOF, "") {
+			p.errorf(open.Pos, "Unbalanced braces, this '{' is never closed with '}'")
+
+			return
+		}
+
+		p.skipStatement()
+	}
+}
+
+// skipStatement skips the tokens up to the next ';' or the '}' closing the current block,
+// which is not consumed. The blocks and parentheses found on the way are skipped whole,
+// an unbalanced ')' is skipped too.
+func (p *parser) skipStatement() {
+	depth := 0
+
+	for !p.at(EOF, "") {
+		tok := p.peek()
+
+		switch {
+		case p.at(Punct, "{"), p.at(Punct, "("):
+			depth++
+		case p.at(Punct, "}") && depth == 0:
+			return
+		case p.at(Punct, "}"), p.at(Punct, ")"):
+			depth = max(depth-1, 0)
+
+			if depth == 0 && tok.Text == "}" {
+				p.next()
+
+				return
+			}
+		case p.at(Punct, ";") && depth == 0:
+			p.next()
+
+			return
+		}
+
+		p.next()
+	}
+}
+
+// atDeclaration reports whether the next token starts a declaration, or ends the code.
+func (p *parser) atDeclaration() bool {
+	tok := p.peek()
+
+	return tok.Kind == EOF || tok.Kind == CBlock ||
+		(tok.Kind == Ident && slices.Contains([]string{"vcl", "import", "include", "sub", "backend", "probe", "acl"}, tok.Text))
+}
+
+func (p *parser) peek() Token {
+	return p.peekAt(0)
+}
+
+// peekAt returns the token n positions after the next one, or EOF.
+func (p *parser) peekAt(n int) Token {
+	if p.n+n < len(p.tokens) {
+		return p.tokens[p.n+n]
+	}
+
+	return p.tokens[len(p.tokens)-1]
+}
+
+func (p *parser) next() Token {
+	tok := p.peek()
+	if p.n < len(p.tokens)-1 {
+		p.n++
+	}
+
+	return tok
+}
+
+// at reports whether the next token is of kind, and has the text unless it's empty.
+func (p *parser) at(kind TokenKind, text string) bool {
+	tok := p.peek()
+
+	return tok.Kind == kind && (text == "" || tok.Text == text)
+}
+
+// accept consumes the next token if it's of kind with the text.
+func (p *parser) accept(kind TokenKind, text string) bool {
+	if !p.at(kind, text) {
+		return false
+	}
+
+	p.next()
+
+	return true
+}
+
+// expect consumes the punctuation mark text, or adds an error.
+func (p *parser) expect(text string, context string) bool {
+	if p.accept(Punct, text) {
+		return true
+	}
+
+	p.errorf(p.peek().Pos, "Expected %s %s, found %s", quote(text), context, describe(p.peek()))
+
+	return false
+}
+
+// expectKind consumes a token of kind, or adds an error saying what was expected.
+func (p *parser) expectKind(kind TokenKind, what string) bool {
+	if p.accept(kind, "") {
+		return true
+	}
+
+	p.errorf(p.peek().Pos, "Expected %s, found %s", what, describe(p.peek()))
+
+	return false
+}
+
+// expectEnd consumes the ';' ending the statement.
+func (p *parser) expectEnd(statement string) bool {
+	return p.expect(";", "at the end of "+quote(statement))
+}
+
+func (p *parser) errorf(pos Pos, format string, args ...any) {
+	p.errs = append(p.errs, &Error{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// describe returns the token for the error messages.
+func describe(tok Token) string {
+	switch tok.Kind {
+	case EOF:
+		return "the end of the code"
+	case String:
+		return "a string"
+	case CBlock:
+		return "inline C code"
+	}
+
+	return quote(tok.Text)
+}
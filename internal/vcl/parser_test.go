@@ -0,0 +1,232 @@
+package vcl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/vcl"
+)
+
+func TestValidate_valid(t *testing.T) {
+	code := `vcl 4.1;
+
+import std;
+import directors from "/usr/lib/varnish/vmods/libvmod_directors.so";
+include "common.vcl";
+
+/* The origin of the tests,
+   with its health check */
+backend local {
+    .host = "127.0.0.1";
+    .port = "8080";
+    .probe = {
+        .request = "GET /health HTTP/1.1"
+                   "Host: www.example.com";
+        .interval = 5s;
+    };
+}
+
+backend unused none;
+
+acl purgers {
+    "localhost";
+    "192.168.0.0"/24;
+    !"192.168.0.1";
+}
+
+sub vcl_init {
+    new vdir = directors.round_robin();
+    vdir.add_backend(c300_origin1.backend());
+}
+
+sub redirect_https {
+    return (synth(750, "Moved"));
+};
+
+sub vcl_recv {
+    # Comments are skipped
+    if (req.http.host == "www.example.com" && req.url !~ "^/static/") {
+        set req.backend_hint = c300_origin1.backend();
+        set req.http.X-Forwarded-For = req.http.X-Forwarded-For + ", " + client.ip;
+    } else if (req.http.host ~ "(?i)^static\.") {
+        set req.http.X-Session = req.http.Cookie:session;
+        unset req.http.Cookie:_ga;
+    } elsif (!req.http.Authorization) {
+        std.log("anonymous");
+    } else {
+        call redirect_https;
+    }
+
+    if (req.method == "PURGE" && client.ip ~ purgers) {
+        return (purge);
+    }
+
+    set req.grace = 10s;
+    set req.http.X-Long = {"a "quoted" string"};
+    hash_data(req.url);
+    esi;
+    return (hash);
+}
+
+sub vcl_synth {
+    set resp.http.Location = "https://" + req.http.host + req.url;
+    synthetic({"<html>"} + resp.reason + {"</html>"});
+    return (deliver);
+}
+
+C{
+    #include <stdio.h>
+}C
+`
+
+	if errs := vcl.Validate(code); len(errs) > 0 {
+		t.Fatalf("Validate returned errors:\n%s", errs)
+	}
+}
+
+func TestValidate_errors(t *testing.T) {
+	tests := map[string]struct {
+		code     string
+		expected string
+	}{
+		"missing closing brace": {
+			code:     "sub vcl_recv {\n  if (req.url == \"/\") {\n    return (pass);\n}\n",
+			expected: "line 1, column 14: Unbalanced braces, this '{' is never closed with '}'",
+		},
+		"extra closing brace": {
+			code:     "sub vcl_recv {\n  return (pass);\n}\n}\n",
+			expected: "line 4, column 1: Unbalanced braces, this '}' has no matching '{'",
+		},
+		"unknown builtin subroutine": {
+			code:     "sub vcl_recieve {\n}\n",
+			expected: "line 1, column 5: Unknown subroutine 'vcl_recieve'",
+		},
+		"unterminated string": {
+			code:     "sub vcl_recv {\n  set req.http.X = \"value;\n}\n",
+			expected: "line 2, column 20: Unterminated string",
+		},
+		"unterminated long string": {
+			code:     "sub vcl_recv {\n  synthetic({\"body);\n}\n",
+			expected: "line 2, column 13: Unterminated string, '{\"' is never closed with '\"}'",
+		},
+		"unterminated comment": {
+			code:     "/* comment\nsub vcl_recv {\n}\n",
+			expected: "line 1, column 1: Unterminated comment",
+		},
+		"set without operator": {
+			code:     "sub vcl_recv {\n  set req.http.X \"value\";\n}\n",
+			expected: "line 2, column 18: Expected '=' after the variable to set, found a string",
+		},
+		"set without value": {
+			code:     "sub vcl_recv {\n  set req.http.X = ;\n}\n",
+			expected: "line 2, column 20: Expected the value to set, found ';'",
+		},
+		"set without semicolon": {
+			code:     "sub vcl_recv {\n  set req.http.X = \"value\"\n}\n",
+			expected: "line 3, column 1: Expected ';' at the end of 'set', found '}'",
+		},
+		"set without variable": {
+			code:     "sub vcl_recv {\n  set = \"value\";\n}\n",
+			expected: "line 2, column 7: Expected the variable to set after 'set', found '='",
+		},
+		"if without parentheses": {
+			code:     "sub vcl_recv {\n  if req.url == \"/\" {\n    return (pass);\n  }\n}\n",
+			expected: "line 2, column 6: Expected '(' after 'if', found 'req.url'",
+		},
+		"if with incomplete condition": {
+			code:     "sub vcl_recv {\n  if (req.url == ) {\n    return (pass);\n  }\n}\n",
+			expected: "line 2, column 18: Expected a value after '==', found ')'",
+		},
+		"if without closing parenthesis": {
+			code:     "sub vcl_recv {\n  if (req.url == \"/\" {\n    return (pass);\n  }\n}\n",
+			expected: "line 2, column 22: Expected ')' to close the condition of 'if', found '{'",
+		},
+		"if without block": {
+			code:     "sub vcl_recv {\n  if (req.url == \"/\") return (pass);\n}\n",
+			expected: "line 2, column 23: Expected '{' to open the block, found 'return'",
+		},
+		"else without if": {
+			code:     "sub vcl_recv {\n  else {\n  }\n}\n",
+			expected: "line 2, column 3: 'else' without a previous 'if'",
+		},
+		"unknown declaration": {
+			code:     "subroutine vcl_recv {\n}\n",
+			expected: "line 1, column 1: Unexpected 'subroutine', expected a declaration",
+		},
+		"unexpected character": {
+			code:     "sub vcl_recv {\n  set req.http.X = @;\n}\n",
+			expected: "line 2, column 20: Unexpected character '@'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := vcl.Validate(test.code)
+			if len(errs) == 0 {
+				t.Fatalf("Validate returned no errors, expected %q", test.expected)
+			}
+
+			if !strings.HasPrefix(errs[0].Error(), test.expected) {
+				t.Errorf("first error = %q, expected it to start with %q", errs[0].Error(), test.expected)
+			}
+		})
+	}
+}
+
+// The statements the parser doesn't know are left to the compiler of the API.
+func TestValidate_unknownStatements(t *testing.T) {
+	code := "sub vcl_recv {\n  error 404 \"Not found\";\n  COMPILATION_ERROR\n}\n"
+
+	if errs := vcl.Validate(code); len(errs) > 0 {
+		t.Fatalf("Validate returned errors:\n%s", errs)
+	}
+}
+
+// The parser recovers after an error and reports the following ones.
+func TestValidate_severalErrors(t *testing.T) {
+	code := "sub vcl_recv {\n  set req.http.X = ;\n  set req.http.Y \"y\";\n  return (pass);\n}\n\nsub vcl_delivr {\n}\n"
+
+	errs := vcl.Validate(code)
+	if len(errs) != 3 {
+		t.Fatalf("Validate returned %d errors, expected 3:\n%s", len(errs), errs)
+	}
+
+	for n, line := range []int{2, 3, 7} {
+		if errs[n].Pos.Line != line {
+			t.Errorf("error %d is on line %d, expected %d: %s", n, errs[n].Pos.Line, line, errs[n])
+		}
+	}
+}
+
+func TestLex(t *testing.T) {
+	tokens, errs := vcl.Lex("set req.http.X-Forwarded-For = c300_origin.backend(); # comment\nset beresp.ttl = 1.5h;")
+	if len(errs) > 0 {
+		t.Fatalf("Lex returned errors:\n%s", errs)
+	}
+
+	expected := []vcl.Token{
+		{Kind: vcl.Ident, Text: "set", Pos: vcl.Pos{Line: 1, Column: 1}},
+		{Kind: vcl.Ident, Text: "req.http.X-Forwarded-For", Pos: vcl.Pos{Line: 1, Column: 5}},
+		{Kind: vcl.Punct, Text: "=", Pos: vcl.Pos{Line: 1, Column: 30}},
+		{Kind: vcl.Ident, Text: "c300_origin.backend", Pos: vcl.Pos{Line: 1, Column: 32}},
+		{Kind: vcl.Punct, Text: "(", Pos: vcl.Pos{Line: 1, Column: 51}},
+		{Kind: vcl.Punct, Text: ")", Pos: vcl.Pos{Line: 1, Column: 52}},
+		{Kind: vcl.Punct, Text: ";", Pos: vcl.Pos{Line: 1, Column: 53}},
+		{Kind: vcl.Ident, Text: "set", Pos: vcl.Pos{Line: 2, Column: 1}},
+		{Kind: vcl.Ident, Text: "beresp.ttl", Pos: vcl.Pos{Line: 2, Column: 5}},
+		{Kind: vcl.Punct, Text: "=", Pos: vcl.Pos{Line: 2, Column: 16}},
+		{Kind: vcl.Number, Text: "1.5h", Pos: vcl.Pos{Line: 2, Column: 18}},
+		{Kind: vcl.Punct, Text: ";", Pos: vcl.Pos{Line: 2, Column: 22}},
+		{Kind: vcl.EOF, Pos: vcl.Pos{Line: 2, Column: 23}},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Lex returned %d tokens, expected %d: %v", len(tokens), len(expected), tokens)
+	}
+
+	for n := range expected {
+		if tokens[n] != expected[n] {
+			t.Errorf("token %d = %+v, expected %+v", n, tokens[n], expected[n])
+		}
+	}
+}
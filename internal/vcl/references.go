@@ -0,0 +1,57 @@
+package vcl
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// backendNameRe matches the name of a backend in VCL code: c{company_id}_{name}.
+var backendNameRe = regexp.MustCompile(`^c([0-9]+)_[a-z0-9]+$`)
+
+// BackendReference is a backend used by VCL code.
+type BackendReference struct {
+	// VCLName is the name of the backend in the code: c{company_id}_{name}.
+	VCLName string
+	// CompanyID owns the backend.
+	CompanyID int
+	// Pos is the first use of the backend in the code.
+	Pos Pos
+}
+
+// BackendReferences returns the backends used by code, sorted by their name.
+// The names in strings and comments are not references.
+func BackendReferences(code string) []BackendReference {
+	// The tokens before a lexing error are still worth reporting
+	tokens, _ := Lex(code)
+
+	var references []BackendReference
+
+	for _, tok := range tokens {
+		if tok.Kind != Ident {
+			continue
+		}
+
+		// i.e: c300_origin.backend()
+		name, _, _ := strings.Cut(tok.Text, ".")
+
+		match := backendNameRe.FindStringSubmatch(name)
+		if match == nil || slices.ContainsFunc(references, func(r BackendReference) bool { return r.VCLName == name }) {
+			continue
+		}
+
+		companyID, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+
+		references = append(references, BackendReference{VCLName: name, CompanyID: companyID, Pos: tok.Pos})
+	}
+
+	slices.SortFunc(references, func(a, b BackendReference) int {
+		return strings.Compare(a.VCLName, b.VCLName)
+	})
+
+	return references
+}
@@ -0,0 +1,40 @@
+package vcl_test
+
+import (
+	"testing"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/vcl"
+)
+
+func TestBackendReferences(t *testing.T) {
+	code := `sub vcl_recv {
+    # c300_commented.backend() is not used
+    if (req.http.host == "c300_quoted") {
+        set req.backend_hint = c300_origin2.backend();
+    } else {
+        set req.backend_hint = c300_origin1.backend();
+        set req.http.X-Backend = c300_origin2;
+    }
+
+    set bereq.backend = c301_other.backend();
+    set req.http.X-Client = client.ip;
+}
+`
+
+	expected := []vcl.BackendReference{
+		{VCLName: "c300_origin1", CompanyID: 300, Pos: vcl.Pos{Line: 6, Column: 32}},
+		{VCLName: "c300_origin2", CompanyID: 300, Pos: vcl.Pos{Line: 4, Column: 32}},
+		{VCLName: "c301_other", CompanyID: 301, Pos: vcl.Pos{Line: 10, Column: 25}},
+	}
+
+	references := vcl.BackendReferences(code)
+	if len(references) != len(expected) {
+		t.Fatalf("BackendReferences returned %v, expected %v", references, expected)
+	}
+
+	for n := range expected {
+		if references[n] != expected[n] {
+			t.Errorf("reference %d = %+v, expected %+v", n, references[n], expected[n])
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package vcl
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// snippetIndent indents the content of the snippets added to a subroutine.
+const snippetIndent = "    "
+
+// Snippet is a piece of VCL code added to a subroutine, or outside of them when Subroutine is empty,
+// i.e: imports, ACLs or backends.
+type Snippet struct {
+	// Name optionally identifies the snippet in the marker comments.
+	Name       string
+	Subroutine string
+	// Priority sorts the snippets of the same subroutine, lower first.
+	Priority int64
+	Content  string
+}
+
+// Assembly is the VCL code assembled from snippets.
+type Assembly struct {
+	Code string
+	// lines locates each line of Code, from 0, in the snippets
+	lines []SnippetPos
+}
+
+// SnippetPos is a position of the assembled code in a snippet.
+type SnippetPos struct {
+	// Snippet is the index of the snippet in the list given to Assemble.
+	Snippet int
+	// Pos is the position in the content of the snippet, it's zero outside of the content, i.e: in the marker comments.
+	Pos Pos
+	// Declaration reports that the position is in the declaration of the subroutine of the snippet, i.e: sub vcl_recv {
+	Declaration bool
+}
+
+// Assemble joins the snippets into VCL code, with marker comments around each one naming the snippet, if it has a name,
+// so adding or removing a snippet doesn't change the markers of the others:
+// first the snippets outside subroutines, then the custom subroutines sorted by their name,
+// and the builtin subroutines in the order of BuiltinSubroutines.
+// The snippets of the same subroutine are sorted by priority, and those with the same priority keep their order.
+func Assemble(snippets []Snippet) Assembly {
+	groups := make(map[string][]int)
+	for n, snippet := range snippets {
+		groups[snippet.Subroutine] = append(groups[snippet.Subroutine], n)
+	}
+
+	subroutines := make([]string, 0, len(groups))
+	for subroutine := range groups {
+		subroutines = append(subroutines, subroutine)
+	}
+
+	slices.SortFunc(subroutines, func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(subroutineOrder(a), subroutineOrder(b)),
+			strings.Compare(a, b),
+		)
+	})
+
+	var assembly Assembly
+
+	var code strings.Builder
+
+	addLine := func(line string, pos SnippetPos) {
+		code.WriteString(line)
+		code.WriteString("\n")
+
+		assembly.lines = append(assembly.lines, pos)
+	}
+
+	addLine("# Assembled from the 'snippets' of the VCL configuration, edit them instead of this code.", SnippetPos{Snippet: -1})
+
+	for _, subroutine := range subroutines {
+		group := groups[subroutine]
+
+		slices.SortStableFunc(group, func(a, b int) int {
+			return cmp.Compare(snippets[a].Priority, snippets[b].Priority)
+		})
+
+		indent := ""
+
+		addLine("", SnippetPos{Snippet: -1})
+
+		if subroutine != "" {
+			addLine("sub "+subroutine+" {", SnippetPos{Snippet: group[0], Declaration: true})
+
+			indent = snippetIndent
+		}
+
+		for _, n := range group {
+			label := "snippet"
+			if snippets[n].Name != "" {
+				label += " " + snippets[n].Name
+			}
+
+			addLine(fmt.Sprintf("%s# BEGIN %s, priority %d", indent, label, snippets[n].Priority), SnippetPos{Snippet: n})
+
+			for number, line := range strings.Split(trimBlankLines(snippets[n].Content), "\n") {
+				pos := SnippetPos{Snippet: n, Pos: Pos{Line: number + 1, Column: 1 - len(indent)}}
+
+				if strings.TrimSpace(line) == "" {
+					addLine("", pos)
+				} else {
+					addLine(indent+line, pos)
+				}
+			}
+
+			addLine(indent+"# END "+label, SnippetPos{Snippet: n})
+		}
+
+		if subroutine != "" {
+			addLine("}", SnippetPos{Snippet: group[len(group)-1]})
+		}
+	}
+
+	assembly.Code = code.String()
+
+	return assembly
+}
+
+// Locate returns the position in the snippets of pos, a position of the assembled code.
+// It returns false when pos is outside of all the snippets.
+func (a Assembly) Locate(pos Pos) (SnippetPos, bool) {
+	if pos.Line < 1 || pos.Line > len(a.lines) {
+		return SnippetPos{}, false
+	}
+
+	located := a.lines[pos.Line-1]
+	if located.Snippet < 0 {
+		return SnippetPos{}, false
+	}
+
+	if located.Pos.Line > 0 {
+		// The column in the content, without the indentation
+		located.Pos.Column = max(located.Pos.Column+pos.Column-1, 1)
+	}
+
+	return located, true
+}
+
+// subroutineOrder sorts the code outside subroutines first, then the custom subroutines, then the builtin ones.
+func subroutineOrder(subroutine string) int {
+	if subroutine == "" {
+		return -2
+	}
+
+	if n := slices.Index(BuiltinSubroutines, subroutine); n >= 0 {
+		return n
+	}
+
+	return -1
+}
+
+// trimBlankLines removes the blank lines at the start and the end of content.
+func trimBlankLines(content string) string {
+	content = strings.TrimRight(content, " \t\r\n")
+
+	for {
+		line, rest, found := strings.Cut(content, "\n")
+		if !found || strings.TrimSpace(line) != "" {
+			return content
+		}
+
+		content = rest
+	}
+}
@@ -0,0 +1,123 @@
+package vcl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TransparentEdge/terraform-provider-transparentedge/internal/vcl"
+)
+
+func TestAssemble(t *testing.T) {
+	assembly := vcl.Assemble([]vcl.Snippet{
+		{Subroutine: "vcl_deliver", Priority: 0, Content: "unset resp.http.Via;"},
+		{Name: "team-b", Subroutine: "vcl_recv", Priority: 20, Content: "set req.http.X-Team = \"b\";"},
+		{Name: "admin", Subroutine: "vcl_recv", Priority: 10, Content: "\nif (req.url ~ \"^/admin\") {\n  return (pass);\n}\n\n"},
+		{Subroutine: "", Priority: 0, Content: "import std;"},
+		{Subroutine: "normalize_host", Priority: 0, Content: "set req.http.host = std.tolower(req.http.host);"},
+		{Subroutine: "vcl_recv", Priority: 20, Content: "call normalize_host;"},
+	})
+
+	expected := `# Assembled from the 'snippets' of the VCL configuration, edit them instead of this code.
+
+# BEGIN snippet, priority 0
+import std;
+# END snippet
+
+sub normalize_host {
+    # BEGIN snippet, priority 0
+    set req.http.host = std.tolower(req.http.host);
+    # END snippet
+}
+
+sub vcl_recv {
+    # BEGIN snippet admin, priority 10
+    if (req.url ~ "^/admin") {
+      return (pass);
+    }
+    # END snippet admin
+    # BEGIN snippet team-b, priority 20
+    set req.http.X-Team = "b";
+    # END snippet team-b
+    # BEGIN snippet, priority 20
+    call normalize_host;
+    # END snippet
+}
+
+sub vcl_deliver {
+    # BEGIN snippet, priority 0
+    unset resp.http.Via;
+    # END snippet
+}
+`
+
+	if assembly.Code != expected {
+		t.Errorf("Assemble returned:\n%s\nexpected:\n%s", assembly.Code, expected)
+	}
+
+	if errs := vcl.Validate(assembly.Code); len(errs) > 0 {
+		t.Errorf("the assembled code has errors:\n%s", errs)
+	}
+}
+
+// The markers don't depend on the position of the snippets in the list.
+func TestAssemble_stableMarkers(t *testing.T) {
+	admin := vcl.Snippet{Name: "admin", Subroutine: "vcl_recv", Content: "return (pass);"}
+	via := vcl.Snippet{Subroutine: "vcl_deliver", Content: "unset resp.http.Via;"}
+
+	before := vcl.Assemble([]vcl.Snippet{admin, via}).Code
+	after := vcl.Assemble([]vcl.Snippet{{Content: "import std;"}, admin, via}).Code
+
+	if !strings.HasSuffix(after, strings.TrimPrefix(before, "# Assembled from the 'snippets' of the VCL configuration, edit them instead of this code.\n")) {
+		t.Errorf("adding a snippet changed the code of the others:\n%s\nbefore:\n%s", after, before)
+	}
+}
+
+func TestAssembly_Locate(t *testing.T) {
+	assembly := vcl.Assemble([]vcl.Snippet{
+		{Subroutine: "vcl_recieve", Content: "set req.http.A = \"a\";"},
+		{Subroutine: "vcl_recieve", Priority: 1, Content: "set req.http.B = \"b\";\nset req.http.C \"c\";"},
+		{Content: "import std"},
+	})
+
+	tests := map[string]struct {
+		pos      vcl.Pos
+		expected vcl.SnippetPos
+		found    bool
+	}{
+		"header comment": {
+			pos: vcl.Pos{Line: 1, Column: 1},
+		},
+		"outside subroutines": {
+			pos:      vcl.Pos{Line: 4, Column: 8},
+			expected: vcl.SnippetPos{Snippet: 2, Pos: vcl.Pos{Line: 1, Column: 8}},
+			found:    true,
+		},
+		"declaration": {
+			pos:      vcl.Pos{Line: 7, Column: 5},
+			expected: vcl.SnippetPos{Snippet: 0, Declaration: true},
+			found:    true,
+		},
+		"indented content": {
+			pos:      vcl.Pos{Line: 13, Column: 20},
+			expected: vcl.SnippetPos{Snippet: 1, Pos: vcl.Pos{Line: 2, Column: 16}},
+			found:    true,
+		},
+		"closing brace": {
+			pos:      vcl.Pos{Line: 15, Column: 1},
+			expected: vcl.SnippetPos{Snippet: 1},
+			found:    true,
+		},
+		"after the code": {
+			pos: vcl.Pos{Line: 16, Column: 1},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			located, found := assembly.Locate(test.pos)
+			if found != test.found || located != test.expected {
+				t.Errorf("Locate(%s) = %+v, %t, expected %+v, %t", test.pos, located, found, test.expected, test.found)
+			}
+		})
+	}
+}